@@ -2,11 +2,13 @@ package broodcmd
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/spf13/cobra"
 
 	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/brood"
 )
 
 func CreateApplicationsCommand() *cobra.Command {
@@ -19,8 +21,11 @@ func CreateApplicationsCommand() *cobra.Command {
 	applicationsGetCmd := CreateApplicationsGetCommand()
 	applicationsListCmd := CreateApplicationsListCommand()
 	applicationsDeleteCmd := CreateApplicationsDeleteCommand()
+	applicationsUpdateCmd := CreateApplicationsUpdateCommand()
+	applicationsUsersCmd := CreateApplicationsUsersCommand()
+	applicationsTransferCmd := CreateApplicationsTransferCommand()
 
-	applicationsCmd.AddCommand(applicationsCreateCmd, applicationsGetCmd, applicationsListCmd, applicationsDeleteCmd)
+	applicationsCmd.AddCommand(applicationsCreateCmd, applicationsGetCmd, applicationsListCmd, applicationsDeleteCmd, applicationsUpdateCmd, applicationsUsersCmd, applicationsTransferCmd)
 
 	return applicationsCmd
 }
@@ -142,3 +147,102 @@ func CreateApplicationsDeleteCommand() *cobra.Command {
 
 	return applicationsDeleteCmd
 }
+
+func CreateApplicationsUpdateCommand() *cobra.Command {
+	var token, applicationId, name, description string
+	applicationsUpdateCmd := &cobra.Command{
+		Use:     "update",
+		Short:   "Change the name or description of an application",
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" && description == "" {
+				return errors.New("Please specify a new name or description")
+			}
+
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			application, applicationErr := broodClient.UpdateApplication(token, applicationId, name, description)
+			if applicationErr != nil {
+				return applicationErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(application)
+			return encodeErr
+		},
+	}
+
+	applicationsUpdateCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	applicationsUpdateCmd.Flags().StringVarP(&applicationId, "application", "a", "", "ID of application to update")
+	applicationsUpdateCmd.Flags().StringVarP(&name, "name", "n", "", "New name of the application (optional)")
+	applicationsUpdateCmd.Flags().StringVarP(&description, "description", "d", "", "New description of the application (optional)")
+	applicationsUpdateCmd.MarkFlagRequired("application")
+
+	return applicationsUpdateCmd
+}
+
+func CreateApplicationsUsersCommand() *cobra.Command {
+	var token, applicationId string
+	applicationsUsersCmd := &cobra.Command{
+		Use:     "users",
+		Short:   "List the users of an application",
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			users, usersErr := broodClient.ListApplicationUsers(token, applicationId)
+			if usersErr != nil {
+				return usersErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(users)
+			return encodeErr
+		},
+	}
+
+	applicationsUsersCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	applicationsUsersCmd.Flags().StringVarP(&applicationId, "application", "a", "", "ID of application to list users of")
+	applicationsUsersCmd.MarkFlagRequired("application")
+
+	return applicationsUsersCmd
+}
+
+func CreateApplicationsTransferCommand() *cobra.Command {
+	var token, applicationId, groupId string
+	applicationsTransferCmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Move an application to another group",
+		Long: `Move an application to another group.
+
+You must be an owner of both the current group of the application and the new one. The users and
+resources of the application are kept.`,
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			application, applicationErr := broodClient.TransferApplication(token, applicationId, groupId)
+			if applicationErr != nil {
+				return applicationErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(application)
+			return encodeErr
+		},
+	}
+
+	applicationsTransferCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	applicationsTransferCmd.Flags().StringVarP(&applicationId, "application", "a", "", "ID of application to transfer")
+	applicationsTransferCmd.Flags().StringVarP(&groupId, "group", "g", "", "ID of Brood group to move the application to")
+	applicationsTransferCmd.MarkFlagRequired("application")
+	applicationsTransferCmd.MarkFlagRequired("group")
+
+	return applicationsTransferCmd
+}
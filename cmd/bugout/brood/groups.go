@@ -9,6 +9,7 @@ import (
 
 	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/brood"
 )
 
 func CreateGroupsCommand() *cobra.Command {
@@ -23,8 +24,10 @@ func CreateGroupsCommand() *cobra.Command {
 	groupsRenameCmd := CreateGroupsRenameCommand()
 	groupsAddUserCmd := CreateGroupsAddUserCommand()
 	groupsRemoveUserCmd := CreateGroupsRemoveUserCommand()
+	groupsMembersCmd := CreateGroupsMembersCommand()
+	groupsSetRoleCmd := CreateGroupsSetRoleCommand()
 
-	groupsCmd.AddCommand(groupsCreateCmd, groupsListCmd, groupsDeleteCmd, groupsRenameCmd, groupsAddUserCmd, groupsRemoveUserCmd)
+	groupsCmd.AddCommand(groupsCreateCmd, groupsListCmd, groupsDeleteCmd, groupsRenameCmd, groupsAddUserCmd, groupsRemoveUserCmd, groupsMembersCmd, groupsSetRoleCmd)
 
 	return groupsCmd
 }
@@ -145,30 +148,32 @@ func CreateGroupsRenameCommand() *cobra.Command {
 	return groupsRenameCmd
 }
 
+// Choices for the --role flag of group commands
+func groupRoleChoices() string {
+	roles := make([]string, len(brood.GroupRoles))
+	for i, role := range brood.GroupRoles {
+		roles[i] = string(role)
+	}
+	return strings.Join(roles, ",")
+}
+
+func checkGroupRole(role string) error {
+	if !brood.GroupRole(role).Valid() {
+		return fmt.Errorf("Invalid role: %s. Choices: %s", role, groupRoleChoices())
+	}
+	return nil
+}
+
 func CreateGroupsAddUserCommand() *cobra.Command {
 	var token, groupID, username, role string
 
-	roles := []string{"owner", "member"}
-	rolesStr := strings.Join(roles, ",")
-	rolesHelp := fmt.Sprintf("User's role in group. Choices: %s", rolesStr)
+	rolesHelp := fmt.Sprintf("User's role in group. Choices: %s", groupRoleChoices())
 
 	groupsAddUserCmd := &cobra.Command{
 		Use:   "add-user",
 		Short: "Add a Bugout user to a group",
 		Args: func(cmd *cobra.Command, args []string) error {
-			roleMatch := false
-			for i := range roles {
-				if role == roles[i] {
-					roleMatch = true
-					break
-				}
-			}
-
-			if !roleMatch {
-				return fmt.Errorf("Invalid role: %s. Choices: %s", role, rolesStr)
-			}
-
-			return nil
+			return checkGroupRole(role)
 		},
 		PreRunE: cmdutils.TokenArgPopulator,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -229,3 +234,72 @@ func CreateGroupsRemoveUserCommand() *cobra.Command {
 
 	return groupsRemoveUserCmd
 }
+
+func CreateGroupsMembersCommand() *cobra.Command {
+	var token, groupID string
+
+	groupsMembersCmd := &cobra.Command{
+		Use:     "members",
+		Short:   "List the members of a group with their roles",
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			members, membersErr := broodClient.ListGroupMembers(token, groupID)
+			if membersErr != nil {
+				return membersErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(members)
+			return encodeErr
+		},
+	}
+
+	groupsMembersCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	groupsMembersCmd.Flags().StringVarP(&groupID, "id", "i", "", "ID of group to list members of")
+	groupsMembersCmd.MarkFlagRequired("id")
+
+	return groupsMembersCmd
+}
+
+func CreateGroupsSetRoleCommand() *cobra.Command {
+	var token, groupID, username, role string
+
+	rolesHelp := fmt.Sprintf("New role of the user in the group. Choices: %s", groupRoleChoices())
+
+	groupsSetRoleCmd := &cobra.Command{
+		Use:   "set-role",
+		Short: "Change the role of a member of a group",
+		Args: func(cmd *cobra.Command, args []string) error {
+			return checkGroupRole(role)
+		},
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			membership, membershipErr := broodClient.SetUserGroupRole(token, groupID, username, brood.GroupRole(role))
+			if membershipErr != nil {
+				return membershipErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(membership)
+			return encodeErr
+		},
+	}
+
+	groupsSetRoleCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	groupsSetRoleCmd.Flags().StringVarP(&groupID, "id", "i", "", "ID of group the user is a member of")
+	groupsSetRoleCmd.Flags().StringVarP(&username, "username", "u", "", "Bugout username of the member")
+	groupsSetRoleCmd.Flags().StringVarP(&role, "role", "r", "", rolesHelp)
+	groupsSetRoleCmd.MarkFlagRequired("id")
+	groupsSetRoleCmd.MarkFlagRequired("username")
+	groupsSetRoleCmd.MarkFlagRequired("role")
+
+	return groupsSetRoleCmd
+}
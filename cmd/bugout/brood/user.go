@@ -1,14 +1,19 @@
 package broodcmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/brood"
 )
 
 func CreateUserCommand() *cobra.Command {
@@ -25,8 +30,9 @@ func CreateUserCommand() *cobra.Command {
 	userFindCmd := CreateUserFindCommand()
 	userVerifyCmd := CreateUserVerifyCommand()
 	userChangePasswordCmd := CreateUserChangePasswordCommand()
+	userResetPasswordCmd := CreateUserResetPasswordCommand()
 
-	userCmd.AddCommand(userAuthCmd, userCreateCmd, userLoginCmd, userTokensCmd, userGetCmd, userFindCmd, userVerifyCmd, userChangePasswordCmd)
+	userCmd.AddCommand(userAuthCmd, userCreateCmd, userLoginCmd, userTokensCmd, userGetCmd, userFindCmd, userVerifyCmd, userChangePasswordCmd, userResetPasswordCmd)
 
 	return userCmd
 }
@@ -59,17 +65,23 @@ func CreateUserAuthCommand() *cobra.Command {
 }
 
 func CreateUserCreateCommand() *cobra.Command {
-	var username, email, password string
+	var username, email, password, applicationID, applicationToken string
 	userCreateCmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new bugout user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := bugout.ClientFromEnv()
+			broodClient, err := brood.ClientFromEnv()
 			if err != nil {
 				return err
 			}
 
-			user, userErr := client.Brood.CreateUser(username, email, password)
+			var user brood.User
+			var userErr error
+			if applicationID != "" {
+				user, userErr = broodClient.CreateApplicationUser(applicationID, applicationToken, username, email, password)
+			} else {
+				user, userErr = broodClient.CreateUser(username, email, password)
+			}
 			if userErr != nil {
 				return userErr
 			}
@@ -82,6 +94,8 @@ func CreateUserCreateCommand() *cobra.Command {
 	userCreateCmd.Flags().StringVarP(&username, "username", "u", "", "Desired username")
 	userCreateCmd.Flags().StringVarP(&email, "email", "e", "", "Email address for user")
 	userCreateCmd.Flags().StringVarP(&password, "password", "p", "", "Password for user")
+	userCreateCmd.Flags().StringVarP(&applicationID, "application", "a", "", "ID of application to register the user in (optional)")
+	userCreateCmd.Flags().StringVar(&applicationToken, "application-token", "", "Access token to register the user in the application with, if it does not accept open registration (optional)")
 	userCreateCmd.MarkFlagRequired("username")
 	userCreateCmd.MarkFlagRequired("email")
 	userCreateCmd.MarkFlagRequired("password")
@@ -90,23 +104,29 @@ func CreateUserCreateCommand() *cobra.Command {
 }
 
 func CreateUserLoginCommand() *cobra.Command {
-	var username, password, tokenType, note string
+	var username, password, tokenType, note, applicationID, applicationToken string
 	userLoginCmd := &cobra.Command{
 		Use:   "login",
 		Short: "Generate an access token for the given Bugout user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := bugout.ClientFromEnv()
+			broodClient, err := brood.ClientFromEnv()
 			if err != nil {
 				return err
 			}
 
-			token, tokenErr := client.Brood.GenerateToken(username, password)
+			var token string
+			var tokenErr error
+			if applicationID != "" {
+				token, tokenErr = broodClient.GenerateApplicationToken(applicationID, applicationToken, username, password)
+			} else {
+				token, tokenErr = broodClient.GenerateToken(username, password)
+			}
 			if tokenErr != nil {
 				return tokenErr
 			}
 
 			if tokenType != "" || note != "" {
-				_, annotationErr := client.Brood.AnnotateToken(token, tokenType, note)
+				_, annotationErr := broodClient.AnnotateToken(token, tokenType, note)
 				if annotationErr != nil {
 					return annotationErr
 				}
@@ -122,6 +142,8 @@ func CreateUserLoginCommand() *cobra.Command {
 	userLoginCmd.Flags().StringVarP(&password, "password", "p", "", "Password for user")
 	userLoginCmd.Flags().StringVarP(&tokenType, "type", "t", "", "Token type")
 	userLoginCmd.Flags().StringVar(&note, "note", "Created using bugout CLI", "Note about the token")
+	userLoginCmd.Flags().StringVarP(&applicationID, "application", "a", "", "ID of application the user belongs to (optional)")
+	userLoginCmd.Flags().StringVar(&applicationToken, "application-token", "", "Access token to log in to the application with, if it requires one (optional)")
 	userLoginCmd.MarkFlagRequired("username")
 	userLoginCmd.MarkFlagRequired("password")
 
@@ -155,9 +177,166 @@ The user is identified by a Bugout access token.`,
 
 	userTokensCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
 
+	userTokensRevokeCmd := CreateUserTokensRevokeCommand()
+	userTokensPruneCmd := CreateUserTokensPruneCommand()
+	userTokensCmd.AddCommand(userTokensRevokeCmd, userTokensPruneCmd)
+
 	return userTokensCmd
 }
 
+func CreateUserTokensRevokeCommand() *cobra.Command {
+	var token string
+	userTokensRevokeCmd := &cobra.Command{
+		Use:   "revoke [tokens...]",
+		Short: "Revoke access tokens",
+		Long: `Revoke access tokens.
+
+The tokens must belong to the same user as the access token used for the request, which may also
+revoke itself.`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			for _, targetToken := range args {
+				revoked, revokeErr := broodClient.RevokeToken(token, targetToken)
+				if revokeErr != nil {
+					return revokeErr
+				}
+				encodeErr := encoder.Encode(&revoked)
+				if encodeErr != nil {
+					return encodeErr
+				}
+			}
+			return nil
+		},
+	}
+
+	userTokensRevokeCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+
+	return userTokensRevokeCmd
+}
+
+type tokenPruneResult struct {
+	Token   brood.UserToken `json:"token"`
+	Revoked bool            `json:"revoked"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func CreateUserTokensPruneCommand() *cobra.Command {
+	var token, olderThan string
+	var inactive, dryRun bool
+	userTokensPruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Revoke old or inactive access tokens",
+		Long: `Revoke old or inactive access tokens.
+
+Revokes the tokens of the user which match all the given conditions. The access token used for the
+request is never revoked. The age for --older-than is a duration like 720h or 30d, and is compared
+with the time at which each token was created. Tokens without a creation time are not revoked by
+--older-than.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if !inactive && olderThan == "" {
+				return errors.New("At least one of --inactive or --older-than must be specified")
+			}
+			return nil
+		},
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cutoff time.Time
+			if olderThan != "" {
+				age, ageErr := parseAge(olderThan)
+				if ageErr != nil {
+					return ageErr
+				}
+				cutoff = time.Now().Add(-age)
+			}
+
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			tokens, err := broodClient.ListTokens(token)
+			if err != nil {
+				return err
+			}
+
+			results := []tokenPruneResult{}
+			for _, userToken := range tokens.Tokens {
+				if !shouldPruneToken(userToken, token, inactive, cutoff) {
+					continue
+				}
+				result := tokenPruneResult{Token: userToken}
+				if !dryRun {
+					_, revokeErr := broodClient.RevokeToken(token, userToken.Id)
+					if revokeErr != nil {
+						result.Error = revokeErr.Error()
+					} else {
+						result.Revoked = true
+					}
+				}
+				results = append(results, result)
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(&results)
+			if encodeErr != nil {
+				return encodeErr
+			}
+			failed := 0
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("Could not revoke %d of %d tokens", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	userTokensPruneCmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	userTokensPruneCmd.Flags().BoolVar(&inactive, "inactive", false, "Only revoke tokens which are no longer active")
+	userTokensPruneCmd.Flags().StringVar(&olderThan, "older-than", "", "Only revoke tokens created longer ago than this (e.g. 720h or 30d)")
+	userTokensPruneCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the tokens which would be revoked without revoking them")
+
+	return userTokensPruneCmd
+}
+
+// Reports whether prune revokes the given token. The token used for the request is never revoked.
+// If cutoff is not zero, only tokens created before it are revoked, and a token whose creation time
+// is unknown is not.
+func shouldPruneToken(userToken brood.UserToken, currentToken string, inactive bool, cutoff time.Time) bool {
+	if userToken.Id == currentToken || (inactive && userToken.Active) {
+		return false
+	}
+	if !cutoff.IsZero() && (userToken.CreatedAt.IsZero() || !userToken.CreatedAt.Before(cutoff)) {
+		return false
+	}
+	return true
+}
+
+// Parses a duration, which may also be given in days (e.g. 30d).
+func parseAge(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, parseErr := strconv.ParseFloat(strings.TrimSuffix(value, "d"), 64)
+		if parseErr != nil || days < 0 {
+			return 0, fmt.Errorf("Invalid age: %s", value)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	age, parseErr := time.ParseDuration(value)
+	if parseErr != nil || age < 0 {
+		return 0, fmt.Errorf("Invalid age: %s", value)
+	}
+	return age, nil
+}
+
 func CreateUserGetCommand() *cobra.Command {
 	var token string
 	userGetCmd := &cobra.Command{
@@ -192,14 +371,14 @@ func CreateUserFindCommand() *cobra.Command {
 		Short: "Find user if exists",
 		Args: func(cmd *cobra.Command, args []string) error {
 			if user_id == "" && username == "" && email == "" && application_id == "" {
-				return errors.New("Exactly one of --user_id or --username or --email or application_id must be specified")
+				return errors.New("Exactly one of --user_id or --username or --email or --application must be specified")
 			}
 
 			return nil
 		},
 		PreRunE: cmdutils.TokenArgPopulator,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := bugout.ClientFromEnv()
+			broodClient, err := brood.ClientFromEnv()
 			if err != nil {
 				return err
 			}
@@ -214,11 +393,13 @@ func CreateUserFindCommand() *cobra.Command {
 			if email != "" {
 				queryParams["email"] = email
 			}
+
+			var user brood.User
 			if application_id != "" {
-				queryParams["application_id"] = application_id
+				user, err = broodClient.FindApplicationUser(token, application_id, queryParams)
+			} else {
+				user, err = broodClient.FindUser(token, queryParams)
 			}
-
-			user, err := client.Brood.FindUser(token, queryParams)
 			if err != nil {
 				return err
 			}
@@ -232,7 +413,9 @@ func CreateUserFindCommand() *cobra.Command {
 	userFindCmd.Flags().StringVarP(&user_id, "user_id", "i", "", "Bugout user ID")
 	userFindCmd.Flags().StringVarP(&username, "username", "u", "", "User name")
 	userFindCmd.Flags().StringVarP(&email, "email", "e", "", "User email address")
-	userFindCmd.Flags().StringVarP(&application_id, "application_id", "a", "", "Application user belongs to")
+	userFindCmd.Flags().StringVarP(&application_id, "application", "a", "", "Application user belongs to")
+	userFindCmd.Flags().StringVar(&application_id, "application_id", "", "Application user belongs to")
+	userFindCmd.Flags().MarkDeprecated("application_id", "use --application instead")
 
 	return userFindCmd
 }
@@ -296,3 +479,97 @@ func CreateUserChangePasswordCommand() *cobra.Command {
 
 	return userChangePasswordCmd
 }
+
+func CreateUserResetPasswordCommand() *cobra.Command {
+	userResetPasswordCmd := &cobra.Command{
+		Use:   "reset-password",
+		Short: "Reset the password of a user who forgot it",
+		Long: `Reset the password of a user who forgot it.
+
+First run "bugout user reset-password request" to have a reset ID sent to the email address of the
+user, and then "bugout user reset-password confirm" with that reset ID to set a new password.`,
+	}
+
+	userResetPasswordRequestCmd := CreateUserResetPasswordRequestCommand()
+	userResetPasswordConfirmCmd := CreateUserResetPasswordConfirmCommand()
+
+	userResetPasswordCmd.AddCommand(userResetPasswordRequestCmd, userResetPasswordConfirmCmd)
+
+	return userResetPasswordCmd
+}
+
+func CreateUserResetPasswordRequestCommand() *cobra.Command {
+	var email string
+	userResetPasswordRequestCmd := &cobra.Command{
+		Use:   "request",
+		Short: "Send a password reset email to a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			_, err = broodClient.RequestPasswordReset(email)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Sent a password reset email to %s\n", email)
+			return nil
+		},
+	}
+
+	userResetPasswordRequestCmd.Flags().StringVar(&email, "email", "", "Email address of the user")
+	userResetPasswordRequestCmd.MarkFlagRequired("email")
+
+	return userResetPasswordRequestCmd
+}
+
+func CreateUserResetPasswordConfirmCommand() *cobra.Command {
+	var resetID string
+	userResetPasswordConfirmCmd := &cobra.Command{
+		Use:   "confirm",
+		Short: "Set a new password using the reset ID from a password reset email",
+		Long: `Set a new password using the reset ID from a password reset email.
+
+The new password is read from standard input. When standard input is a terminal, the password is
+not echoed and has to be typed twice.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := bufio.NewReader(cmd.InOrStdin())
+			newPassword, err := cmdutils.ReadPassword(input, cmd.ErrOrStderr(), "New password: ")
+			if err != nil {
+				return err
+			}
+			if newPassword == "" {
+				return errors.New("New password must not be empty")
+			}
+			if cmdutils.StdinIsTerminal() {
+				repeatedPassword, err := cmdutils.ReadPassword(input, cmd.ErrOrStderr(), "Repeat new password: ")
+				if err != nil {
+					return err
+				}
+				if repeatedPassword != newPassword {
+					return errors.New("Passwords do not match")
+				}
+			}
+
+			broodClient, err := brood.ClientFromEnv()
+			if err != nil {
+				return err
+			}
+
+			user, err := broodClient.ConfirmPasswordReset(resetID, newPassword)
+			if err != nil {
+				return err
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(&user)
+			return encodeErr
+		},
+	}
+
+	userResetPasswordConfirmCmd.Flags().StringVar(&resetID, "reset-id", "", "Reset ID from the password reset email")
+	userResetPasswordConfirmCmd.MarkFlagRequired("reset-id")
+
+	return userResetPasswordConfirmCmd
+}
@@ -0,0 +1,40 @@
+package broodcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+)
+
+func TestShouldPruneToken(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	old := cutoff.Add(-time.Hour)
+	recent := cutoff.Add(time.Hour)
+
+	cases := []struct {
+		name     string
+		token    brood.UserToken
+		inactive bool
+		cutoff   time.Time
+		pruned   bool
+	}{
+		{"current token", brood.UserToken{Id: "current", CreatedAt: old}, false, cutoff, false},
+		{"old token", brood.UserToken{Id: "t", CreatedAt: old, Active: true}, false, cutoff, true},
+		{"recent token", brood.UserToken{Id: "t", CreatedAt: recent}, false, cutoff, false},
+		{"token created at the cutoff", brood.UserToken{Id: "t", CreatedAt: cutoff}, false, cutoff, false},
+		{"token without a creation time", brood.UserToken{Id: "t"}, false, cutoff, false},
+		{"inactive token without a creation time", brood.UserToken{Id: "t"}, true, cutoff, false},
+		{"inactive token without a cutoff", brood.UserToken{Id: "t"}, true, time.Time{}, true},
+		{"active token without a cutoff", brood.UserToken{Id: "t", Active: true}, true, time.Time{}, false},
+		{"old inactive token", brood.UserToken{Id: "t", CreatedAt: old}, true, cutoff, true},
+		{"old active token", brood.UserToken{Id: "t", CreatedAt: old, Active: true}, true, cutoff, false},
+	}
+
+	for _, c := range cases {
+		if pruned := shouldPruneToken(c.token, "current", c.inactive, c.cutoff); pruned != c.pruned {
+			t.Errorf("%s: expected %v, got %v", c.name, c.pruned, pruned)
+		}
+	}
+}
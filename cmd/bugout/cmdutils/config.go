@@ -2,15 +2,37 @@ package cmdutils
 
 import (
 	"fmt"
-	"os"
+	"sync"
 
 	"github.com/spf13/cobra"
+
+	"github.com/bugout-dev/bugout-go/pkg/config"
+)
+
+var (
+	loadEnvOnce sync.Once
+	loadedEnv   config.Env
+	loadEnvErr  error
 )
 
+// LoadEnv loads the configuration profile selected with --profile. The configuration file is only
+// read once per process, so all settings of a command come from the same profile.
+func LoadEnv() (config.Env, error) {
+	loadEnvOnce.Do(func() {
+		loadedEnv, loadEnvErr = config.LoadEnv()
+	})
+	return loadedEnv, loadEnvErr
+}
+
 func MergeString(stringVar, envName string, onNotSet error) (string, error) {
 	finalVal := stringVar
 
-	envVar := os.Getenv(envName)
+	env, envErr := LoadEnv()
+	if envErr != nil {
+		return "", envErr
+	}
+
+	envVar := env.Getenv(envName)
 	if stringVar == "" && envVar != "" {
 		finalVal = envVar
 	}
@@ -24,10 +46,21 @@ func MergeString(stringVar, envName string, onNotSet error) (string, error) {
 
 const EnvKeyBugoutAccessToken string = "BUGOUT_ACCESS_TOKEN"
 const EnvKeyBugoutJournalID string = "BUGOUT_JOURNAL_ID"
+const EnvKeyBugoutSpireURL string = "BUGOUT_SPIRE_URL"
+const EnvKeyBugoutBroodURL string = "BUGOUT_BROOD_URL"
+const EnvKeyBugoutURL string = "BUGOUT_URL"
+const EnvKeyBugoutTimeoutSeconds string = "BUGOUT_TIMEOUT_SECONDS"
+const EnvKeyBugoutRetryMaxAttempts string = "BUGOUT_RETRY_MAX_ATTEMPTS"
 
+// Settings which can be stored in configuration profiles (see `bugout config`)
 var EnvVars []string = []string{
 	EnvKeyBugoutAccessToken,
 	EnvKeyBugoutJournalID,
+	EnvKeyBugoutSpireURL,
+	EnvKeyBugoutBroodURL,
+	EnvKeyBugoutURL,
+	EnvKeyBugoutTimeoutSeconds,
+	EnvKeyBugoutRetryMaxAttempts,
 }
 
 func IsValidEnvVar(key string) bool {
@@ -75,11 +108,14 @@ func CompositePopulator(populators ...cobra.PositionalArgs) cobra.PositionalArgs
 	}
 }
 
-func BugoutURL() string {
+func BugoutURL() (string, error) {
 	defaultBugoutURL := "https://bugout.dev"
-	bugoutURL := os.Getenv("BUGOUT_URL")
+	bugoutURL, err := MergeString("", EnvKeyBugoutURL, nil)
+	if err != nil {
+		return "", err
+	}
 	if bugoutURL == "" {
 		bugoutURL = defaultBugoutURL
 	}
-	return bugoutURL
+	return bugoutURL, nil
 }
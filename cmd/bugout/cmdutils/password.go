@@ -0,0 +1,39 @@
+package cmdutils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// StdinIsTerminal reports whether the standard input of the process is an interactive terminal.
+func StdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// ReadPassword writes prompt to output and reads a password from the next line of input. If the
+// standard input is a terminal, the password is read from it directly and is not echoed while it
+// is typed.
+func ReadPassword(input *bufio.Reader, output io.Writer, prompt string) (string, error) {
+	fmt.Fprint(output, prompt)
+
+	if StdinIsTerminal() {
+		password, readErr := term.ReadPassword(int(os.Stdin.Fd()))
+		// The newline typed after the password was not echoed either.
+		fmt.Fprintln(output)
+		if readErr != nil {
+			return "", fmt.Errorf("Could not read password: %s", readErr.Error())
+		}
+		return string(password), nil
+	}
+
+	line, readErr := input.ReadString('\n')
+	if readErr != nil && !(readErr == io.EOF && line != "") {
+		return "", readErr
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,170 @@
+package configcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
+	"github.com/bugout-dev/bugout-go/pkg/config"
+)
+
+func PopulateConfigCommands(cmd *cobra.Command) {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage bugout configuration profiles",
+		Long: fmt.Sprintf(`Manage the profiles in your bugout configuration file
+
+Each profile stores values for the following settings:
+	%s
+
+Settings are resolved in this order of precedence:
+	1. Command line flags (e.g. --token)
+	2. Environment variables (e.g. BUGOUT_ACCESS_TOKEN)
+	3. The active profile
+	4. Defaults
+
+The active profile is selected by the --profile flag, or else the %s environment variable, or else
+"bugout config use", or else is the profile named "%s".
+
+The configuration file is stored in your user configuration directory (e.g.
+~/.config/bugout/config). You can change its location with the %s environment variable.`,
+			strings.Join(cmdutils.EnvVars, "\n\t"), config.EnvKeyProfile, config.DefaultProfile, config.EnvKeyConfigFile),
+	}
+
+	getCmd := CreateConfigGetCommand()
+	setCmd := CreateConfigSetCommand()
+	listCmd := CreateConfigListCommand()
+	useCmd := CreateConfigUseCommand()
+
+	configCmd.AddCommand(getCmd, setCmd, listCmd, useCmd)
+
+	cmd.AddCommand(configCmd)
+}
+
+func validateKey(key string) error {
+	if !cmdutils.IsValidEnvVar(key) {
+		return fmt.Errorf("Invalid setting: %s. Choices: %s", key, strings.Join(cmdutils.EnvVars, ","))
+	}
+	return nil
+}
+
+func CreateConfigGetCommand() *cobra.Command {
+	getCmd := &cobra.Command{
+		Use:   "get <setting>",
+		Short: "Show the value of a setting",
+		Long:  "Show the value which bugout uses for a setting, taking environment variables into account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if err := validateKey(key); err != nil {
+				return err
+			}
+
+			env, envErr := config.LoadEnv()
+			if envErr != nil {
+				return envErr
+			}
+			value := env.Getenv(key)
+			if value == "" {
+				return fmt.Errorf("Setting is not set in profile \"%s\" or in the environment: %s", env.ProfileName, key)
+			}
+
+			cmd.OutOrStdout().Write([]byte(value + "\n"))
+			return nil
+		},
+	}
+
+	return getCmd
+}
+
+func CreateConfigSetCommand() *cobra.Command {
+	setCmd := &cobra.Command{
+		Use:   "set <setting> <value>",
+		Short: "Store a setting in the active profile",
+		Long:  "Store a setting in the active profile. Setting an empty value removes the setting from the profile.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			if err := validateKey(key); err != nil {
+				return err
+			}
+
+			bugoutConfig, loadErr := config.Load()
+			if loadErr != nil {
+				return loadErr
+			}
+			profile := bugoutConfig.ActiveProfile()
+			bugoutConfig.Set(profile, key, value)
+			if saveErr := bugoutConfig.Save(); saveErr != nil {
+				return saveErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(bugoutConfig.Profiles[profile])
+			return encodeErr
+		},
+	}
+
+	return setCmd
+}
+
+type profileListing struct {
+	ConfigFile    string                    `json:"config_file"`
+	ActiveProfile string                    `json:"active_profile"`
+	Profiles      map[string]config.Profile `json:"profiles"`
+}
+
+func CreateConfigListCommand() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all profiles and their settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, pathErr := config.Path()
+			if pathErr != nil {
+				return pathErr
+			}
+			bugoutConfig, loadErr := config.LoadFile(path)
+			if loadErr != nil {
+				return loadErr
+			}
+
+			listing := profileListing{
+				ConfigFile:    path,
+				ActiveProfile: bugoutConfig.ActiveProfile(),
+				Profiles:      bugoutConfig.Profiles,
+			}
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(listing)
+			return encodeErr
+		},
+	}
+
+	return listCmd
+}
+
+func CreateConfigUseCommand() *cobra.Command {
+	useCmd := &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Make a profile the active profile",
+		Long:  "Make a profile the active profile by default. The --profile flag and the BUGOUT_PROFILE environment variable still take precedence.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := args[0]
+
+			bugoutConfig, loadErr := config.Load()
+			if loadErr != nil {
+				return loadErr
+			}
+			if _, exists := bugoutConfig.Profiles[profile]; !exists {
+				bugoutConfig.Profiles[profile] = config.Profile{}
+				cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Created new profile: %s\n", profile)))
+			}
+			bugoutConfig.CurrentProfile = profile
+			return bugoutConfig.Save()
+		},
+	}
+
+	return useCmd
+}
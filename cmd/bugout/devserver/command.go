@@ -0,0 +1,56 @@
+package devservercmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bugout-dev/bugout-go/pkg/devserver"
+)
+
+func PopulateDevServerCommands(cmd *cobra.Command) {
+	devServerCmd := CreateDevServerCommand()
+	cmd.AddCommand(devServerCmd)
+}
+
+func CreateDevServerCommand() *cobra.Command {
+	var address, statePath string
+
+	devServerCmd := &cobra.Command{
+		Use:   "dev-server",
+		Short: "Run a local emulator of the Bugout APIs",
+		Long: `Runs a local emulator of the Spire and Brood APIs, which you can use in place of
+https://spire.bugout.dev and https://auth.bugout.dev during development.
+
+All data is kept in memory unless you specify a state file with --state, in which case it is loaded
+from that file on startup and saved to it after every change.
+
+To point the bugout tool (or any other client built on this library) at the emulator:
+	$ export BUGOUT_SPIRE_URL=http://127.0.0.1:7475
+	$ export BUGOUT_BROOD_URL=http://127.0.0.1:7475`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, serverErr := devserver.New(statePath)
+			if serverErr != nil {
+				return serverErr
+			}
+
+			listener, listenErr := net.Listen("tcp", address)
+			if listenErr != nil {
+				return listenErr
+			}
+			baseURL := fmt.Sprintf("http://%s", listener.Addr().String())
+			server.Spire.SpireURL = baseURL
+
+			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Serving the Spire and Brood APIs at: %s\n\nexport BUGOUT_SPIRE_URL=%s\nexport BUGOUT_BROOD_URL=%s\n", baseURL, baseURL, baseURL)))
+
+			return http.Serve(listener, server.Handler())
+		},
+	}
+
+	devServerCmd.Flags().StringVarP(&address, "address", "a", "127.0.0.1:7475", "Address on which to listen for requests")
+	devServerCmd.Flags().StringVarP(&statePath, "state", "s", "", "File in which to persist the emulator's state (by default, state is not persisted)")
+
+	return devServerCmd
+}
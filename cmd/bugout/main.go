@@ -7,12 +7,17 @@ import (
 	"github.com/spf13/cobra"
 
 	broodcmd "github.com/bugout-dev/bugout-go/cmd/bugout/brood"
+	configcmd "github.com/bugout-dev/bugout-go/cmd/bugout/config"
+	devservercmd "github.com/bugout-dev/bugout-go/cmd/bugout/devserver"
 	spirecmd "github.com/bugout-dev/bugout-go/cmd/bugout/spire"
 	trapcmd "github.com/bugout-dev/bugout-go/cmd/bugout/trap"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/config"
 )
 
 func CreateBugoutCommand() *cobra.Command {
+	var profile string
+
 	bugoutCmd := &cobra.Command{
 		Use:   "bugout",
 		Short: "Interact with Bugout from your command line",
@@ -22,9 +27,17 @@ The bugout utility lets you interact with your Bugout resources from your comman
 		Version: bugout.Version,
 	}
 
+	bugoutCmd.PersistentFlags().StringVar(&profile, "profile", "", "Configuration profile to use (see \"bugout config\")")
+	// Initializers run before argument validation, which is where some commands read their settings.
+	cobra.OnInitialize(func() {
+		config.UseProfile(profile)
+	})
+
 	broodcmd.PopulateBroodCommands(bugoutCmd)
 	spirecmd.PopulateSpireCommands(bugoutCmd)
 	trapcmd.PopulateTrapCommands(bugoutCmd)
+	devservercmd.PopulateDevServerCommands(bugoutCmd)
+	configcmd.PopulateConfigCommands(bugoutCmd)
 
 	completionCmd := CreateBugoutCompletionCommand()
 	bugoutCmd.AddCommand(completionCmd)
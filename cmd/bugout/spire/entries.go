@@ -1,11 +1,17 @@
 package spirecmd
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
@@ -21,13 +27,15 @@ func CreateEntriesCommand() *cobra.Command {
 
 	createCmd := CreateEntriesCreateCommand()
 	deleteCmd := CreateEntriesDeleteCommand()
+	editCmd := CreateEntriesEditCommand()
 	getCmd := CreateEntriesGetCommand()
+	importCmd := CreateEntriesImportCommand()
 	listCmd := CreateEntriesListCommand()
 	searchCmd := CreateEntriesSearchCommand()
 	tagCmd := CreateEntriesTagCommand()
 	untagCmd := CreateEntriesUntagCommand()
 	updateCmd := CreateEntriesUpdateCommand()
-	cmd.AddCommand(createCmd, deleteCmd, getCmd, listCmd, searchCmd, tagCmd, untagCmd, updateCmd)
+	cmd.AddCommand(createCmd, deleteCmd, editCmd, getCmd, importCmd, listCmd, searchCmd, tagCmd, untagCmd, updateCmd)
 
 	return cmd
 }
@@ -109,6 +117,104 @@ func CreateEntriesCreateCommand() *cobra.Command {
 	return cmd
 }
 
+type importResult struct {
+	Line  int          `json:"line"`
+	Entry *spire.Entry `json:"entry,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+func CreateEntriesImportCommand() *cobra.Command {
+	var token, journalID, inputFile string
+	var parallelism int
+	var rateLimit float64
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create entries in a Bugout journal from a JSON Lines file",
+		Long: `Create entries in a Bugout journal from a JSON Lines file
+
+Each non-empty line of the file describes one entry:
+	{"title": "...", "content": "...", "tags": ["..."], "context_type": "...", "context_id": "...", "context_url": "..."}
+
+The whole file is checked before any entries are created. A failure to create one entry does not
+stop the import. The outcome for each line is written to stdout as a JSON object with the line number
+and either the created entry or an error, and the command fails if any entry could not be created.`,
+		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := cmd.InOrStdin()
+			if inputFile != "-" {
+				file, openErr := os.Open(inputFile)
+				if openErr != nil {
+					return openErr
+				}
+				defer file.Close()
+				input = file
+			}
+
+			specs := []spire.EntrySpec{}
+			lineNumbers := []int{}
+			scanner := bufio.NewScanner(input)
+			scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+			lineNumber := 0
+			for scanner.Scan() {
+				lineNumber++
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				var spec spire.EntrySpec
+				decodeErr := json.Unmarshal([]byte(line), &spec)
+				if decodeErr != nil {
+					return fmt.Errorf("Could not parse entry on line %d: %s", lineNumber, decodeErr.Error())
+				}
+				specs = append(specs, spec)
+				lineNumbers = append(lineNumbers, lineNumber)
+			}
+			if scanErr := scanner.Err(); scanErr != nil {
+				return scanErr
+			}
+
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+
+			options := spire.BulkOptions{Parallelism: parallelism, RateLimit: rateLimit}
+			results := spire.CreateEntries(context.Background(), spireClient, token, journalID, specs, options)
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			numFailed := 0
+			for i, result := range results {
+				output := importResult{Line: lineNumbers[i]}
+				if result.Err != nil {
+					output.Error = result.Err.Error()
+					numFailed++
+				} else {
+					entry := result.Entry
+					output.Entry = &entry
+				}
+				if encodeErr := encoder.Encode(output); encodeErr != nil {
+					return encodeErr
+				}
+			}
+
+			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Created %d of %d entries\n", len(results)-numFailed, len(results))))
+			if numFailed > 0 {
+				return fmt.Errorf("Failed to create %d entries", numFailed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal")
+	cmd.Flags().StringVarP(&inputFile, "file", "f", "-", "JSON Lines file to read entries from (\"-\" for stdin)")
+	cmd.Flags().IntVarP(&parallelism, "parallelism", "P", spire.DefaultBulkParallelism, "Maximum number of entries to create at once")
+	cmd.Flags().Float64Var(&rateLimit, "rate", 0, "Maximum number of entries to create per second (0 for no limit)")
+	cmd.MarkFlagFilename("file")
+
+	return cmd
+}
+
 func CreateEntriesDeleteCommand() *cobra.Command {
 	var token, journalID, entryID string
 	cmd := &cobra.Command{
@@ -204,19 +310,86 @@ func CreateEntriesSearchCommand() *cobra.Command {
 	var token, journalID string
 	var limit, offset int
 	var queryParams map[string]string
+	var tags, excludedTags []string
+	var createdAfter, createdBefore, updatedAfter, updatedBefore string
+	var contextType, contextID, order string
 	cmd := &cobra.Command{
-		Use:     "search [query]",
-		Short:   "Search across the entries in a Bugout journal",
+		Use:   "search [query]",
+		Short: "Search across the entries in a Bugout journal",
+		Long: `Search across the entries in a Bugout journal
+
+The query is sent to Spire as it is given. It is made up of the following terms, all of which must
+match:
+	tag:<tag>                 Entries with the given tag
+	!tag:<tag>                Entries without the given tag
+	title:<term>              Entries with the term in their title
+	content:<term>            Entries with the term in their content
+	created_at:>2021-01-31    Entries created after the given time (RFC 3339 or YYYY-MM-DD)
+	created_at:<2021-01-31    Entries created before the given time
+	updated_at:>...           Entries updated after or before the given time
+	context_type:<type>       Entries with the given context type
+	context_id:<id>           Entries with the given context ID
+	<term>                    Entries with the term in their title or content
+
+The same conditions can also be given with flags (--tag, --created-after, --order and so on). Flag
+conditions are checked before they are sent to Spire, and are added to the query. Use
+"--params content=false" to leave the content of entries out of the results.`,
 		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			searchQuery := strings.Join(args, " ")
+			parameters := queryParams
+
+			structuredFlags := []string{"tag", "exclude-tag", "created-after", "created-before", "updated-after", "updated-before", "context-type", "context-id", "order"}
+			structured := false
+			for _, flagName := range structuredFlags {
+				if cmd.Flags().Changed(flagName) {
+					structured = true
+				}
+			}
+
+			if structured {
+				query := spire.SearchQuery{
+					RequiredTags:    tags,
+					ExcludedTags:    excludedTags,
+					ContextType:     contextType,
+					ContextID:       contextID,
+					Order:           order,
+					ExtraParameters: queryParams,
+				}
+				bounds := []struct {
+					flagName string
+					value    string
+					bound    *time.Time
+				}{
+					{"created-after", createdAfter, &query.CreatedAfter},
+					{"created-before", createdBefore, &query.CreatedBefore},
+					{"updated-after", updatedAfter, &query.UpdatedAfter},
+					{"updated-before", updatedBefore, &query.UpdatedBefore},
+				}
+				for _, bound := range bounds {
+					if bound.value == "" {
+						continue
+					}
+					parsed, parseErr := spire.ParseSearchTime(bound.value)
+					if parseErr != nil {
+						return fmt.Errorf("Could not parse --%s (use RFC 3339 or YYYY-MM-DD): %s", bound.flagName, bound.value)
+					}
+					*bound.bound = parsed
+				}
+				if validationErr := query.Validate(); validationErr != nil {
+					return validationErr
+				}
+
+				searchQuery = strings.TrimSpace(query.Query() + " " + searchQuery)
+				parameters = query.Parameters()
+			}
+
 			client, clientErr := bugout.ClientFromEnv()
 			if clientErr != nil {
 				return clientErr
 			}
 
-			searchQuery := strings.Join(args, " ")
-
-			entries, err := client.Spire.SearchEntries(token, journalID, searchQuery, limit, offset, queryParams)
+			entries, err := client.Spire.SearchEntries(token, journalID, searchQuery, limit, offset, parameters)
 			if err != nil {
 				return err
 			}
@@ -231,36 +404,118 @@ func CreateEntriesSearchCommand() *cobra.Command {
 	cmd.Flags().IntVarP(&limit, "limit", "N", 10, "Number of entries per page")
 	cmd.Flags().IntVarP(&offset, "offset", "n", 0, "Index of starting entry on current page")
 	cmd.Flags().StringToStringVarP(&queryParams, "params", "p", nil, "Optional query parameters to add to the query")
+	cmd.Flags().StringSliceVar(&tags, "tag", []string{}, "Only match entries with these tags (as a comma-separated list of strings)")
+	cmd.Flags().StringSliceVar(&excludedTags, "exclude-tag", []string{}, "Only match entries without these tags (as a comma-separated list of strings)")
+	cmd.Flags().StringVar(&createdAfter, "created-after", "", "Only match entries created after this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&createdBefore, "created-before", "", "Only match entries created before this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&updatedAfter, "updated-after", "", "Only match entries updated after this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&updatedBefore, "updated-before", "", "Only match entries updated before this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&contextType, "context-type", "", "Only match entries with this context type")
+	cmd.Flags().StringVar(&contextID, "context-id", "", "Only match entries with this context ID")
+	cmd.Flags().StringVar(&order, "order", "", fmt.Sprintf("Order of results by creation time (%s or %s)", spire.SearchOrderAscending, spire.SearchOrderDescending))
 
 	return cmd
 }
 
+type tagUpdateResult struct {
+	EntryID string       `json:"entry_id"`
+	Changed bool         `json:"changed"`
+	Entry   *spire.Entry `json:"entry,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
 func CreateEntriesTagCommand() *cobra.Command {
-	var token, journalID, entryID string
+	var token, journalID, entryID, searchQuery string
+	var removeTags []string
+	var queryParams map[string]string
+	var parallelism int
+	var rateLimit float64
 	cmd := &cobra.Command{
-		Use:     "tag [tags...]",
-		Short:   "Add tags to an entry",
+		Use:   "tag [tags...]",
+		Short: "Add tags to an entry, or to every entry matching a search query",
+		Long: `Add tags to an entry, or to every entry matching a search query
+
+With --id, the tags are added to a single entry. With --query, the tags are added to every entry in
+the journal which matches the query (see "bugout entries search --help" for the query syntax), and
+the tags given with --remove are removed from them. The outcome for each matching entry is written
+to stdout as a JSON object, and the command fails if any entry could not be updated.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if (entryID == "" && searchQuery == "") || (entryID != "" && searchQuery != "") {
+				return errors.New("Exactly one of --id or --query must be specified")
+			}
+			if entryID != "" && len(removeTags) > 0 {
+				return errors.New("--remove can only be used with --query (use \"bugout entries untag\" for a single entry)")
+			}
+			return nil
+		},
 		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, clientErr := bugout.ClientFromEnv()
+			if searchQuery == "" {
+				client, clientErr := bugout.ClientFromEnv()
+				if clientErr != nil {
+					return clientErr
+				}
+
+				entry, err := client.Spire.TagEntry(token, journalID, entryID, args)
+				if err != nil {
+					return err
+				}
+
+				encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(entry)
+				return encodeErr
+			}
+
+			query, queryErr := spire.ParseSearchQuery(searchQuery, queryParams)
+			if queryErr != nil {
+				return queryErr
+			}
+
+			spireClient, clientErr := spire.ClientFromEnv()
 			if clientErr != nil {
 				return clientErr
 			}
 
-			entry, err := client.Spire.TagEntry(token, journalID, entryID, args)
+			options := spire.BulkOptions{Parallelism: parallelism, RateLimit: rateLimit}
+			results, err := spire.UpdateTagsByQuery(context.Background(), spireClient, token, journalID, query, args, removeTags, options)
 			if err != nil {
 				return err
 			}
 
-			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(entry)
-			return encodeErr
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			numChanged, numFailed := 0, 0
+			for _, result := range results {
+				output := tagUpdateResult{EntryID: result.EntryID, Changed: result.Changed}
+				if result.Err != nil {
+					output.Error = result.Err.Error()
+					numFailed++
+				} else {
+					entry := result.Entry
+					output.Entry = &entry
+					if result.Changed {
+						numChanged++
+					}
+				}
+				if encodeErr := encoder.Encode(output); encodeErr != nil {
+					return encodeErr
+				}
+			}
+
+			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Updated %d of %d matching entries\n", numChanged, len(results))))
+			if numFailed > 0 {
+				return fmt.Errorf("Failed to update %d entries", numFailed)
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
 	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal")
 	cmd.Flags().StringVarP(&entryID, "id", "i", "", "ID of entry")
-	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVarP(&searchQuery, "query", "q", "", "Search query selecting the entries to tag")
+	cmd.Flags().StringToStringVarP(&queryParams, "params", "p", nil, "Optional query parameters to add to the query (with --query)")
+	cmd.Flags().StringSliceVar(&removeTags, "remove", []string{}, "Tags to remove from the matching entries (with --query)")
+	cmd.Flags().IntVarP(&parallelism, "parallelism", "P", spire.DefaultBulkParallelism, "Maximum number of entries to update at once (with --query)")
+	cmd.Flags().Float64Var(&rateLimit, "rate", 0, "Maximum number of entries to update per second, 0 for no limit (with --query)")
 
 	return cmd
 }
@@ -347,3 +602,226 @@ func CreateEntriesUpdateCommand() *cobra.Command {
 
 	return cmd
 }
+
+func CreateEntriesEditCommand() *cobra.Command {
+	var token, journalID, entryID, editor string
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit an entry in a Bugout journal with a text editor",
+		Long: `Edit an entry in a Bugout journal with a text editor
+
+The entry is opened in your editor (--editor, or $VISUAL or $EDITOR). The first line of the file is
+the title of the entry, and its content follows after a blank line. The entry is updated once the
+editor exits.
+
+If someone else changed the entry while you were editing it, your changes are merged with theirs.
+You can then save the merged entry, edit it (conflicting changes are marked like in "git merge"),
+overwrite their changes with yours or abort. If you abort, your version of the entry is kept in a
+file so that no work is lost.`,
+		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+			// Editors which are set to blank strings count as unset.
+			if strings.TrimSpace(editor) == "" {
+				editor = os.Getenv("VISUAL")
+			}
+			if strings.TrimSpace(editor) == "" {
+				editor = os.Getenv("EDITOR")
+			}
+			if strings.TrimSpace(editor) == "" {
+				editor = "vi"
+			}
+
+			ctx := context.Background()
+			base, getErr := spireClient.GetEntryContext(ctx, token, journalID, entryID)
+			if getErr != nil {
+				return getErr
+			}
+
+			file, fileErr := ioutil.TempFile("", "bugout-entry-*.md")
+			if fileErr != nil {
+				return fileErr
+			}
+			file.Close()
+			path := file.Name()
+
+			title, content, editErr := editEntryText(editor, path, base.Title, base.Content)
+			if editErr != nil {
+				return editErr
+			}
+			if title == base.Title && content == base.Content {
+				os.Remove(path)
+				cmd.ErrOrStderr().Write([]byte("No changes\n"))
+				return nil
+			}
+
+			stderr := cmd.ErrOrStderr()
+			input := bufio.NewReader(cmd.InOrStdin())
+			for {
+				if title == "" {
+					return fmt.Errorf("Title cannot be empty. Your version of the entry is saved in %s", path)
+				}
+				entry, updateErr := spireClient.UpdateEntryIfUnchangedContext(ctx, token, journalID, entryID, title, content, spire.EntryVersionOf(base))
+				if updateErr == nil {
+					os.Remove(path)
+					return json.NewEncoder(cmd.OutOrStdout()).Encode(entry)
+				}
+				var conflictErr *spire.EntryConflictError
+				if !errors.As(updateErr, &conflictErr) {
+					return fmt.Errorf("%s\nYour version of the entry is saved in %s", updateErr.Error(), path)
+				}
+
+				theirs := conflictErr.Current
+				mergedTitle, titleConflict := mergeTitle(base.Title, title, theirs.Title)
+				mergedContent, conflicts := mergeText(base.Content, content, theirs.Content)
+				if titleConflict {
+					conflicts++
+				}
+
+				stderr.Write([]byte(fmt.Sprintf("Entry %s was changed by someone else while you were editing it.\n", entryID)))
+				if titleConflict {
+					stderr.Write([]byte(fmt.Sprintf("Both of you changed the title. Yours: %q, theirs: %q\n", title, theirs.Title)))
+				}
+				var choice byte
+				var promptErr error
+				if conflicts == 0 {
+					stderr.Write([]byte("Your changes merge cleanly with theirs.\n"))
+					choice, promptErr = promptChoice(input, stderr, "[s]ave merged, [e]dit merged, [o]verwrite theirs with yours, [a]bort", "seoa")
+				} else {
+					stderr.Write([]byte(fmt.Sprintf("Merging your changes with theirs left %d conflicts.\n", conflicts)))
+					choice, promptErr = promptChoice(input, stderr, "[e]dit merged, [o]verwrite theirs with yours, [a]bort", "eoa")
+				}
+				if promptErr != nil {
+					return promptErr
+				}
+
+				// The next update is based on their version of the entry.
+				base = theirs
+				switch choice {
+				case 's':
+					title, content = mergedTitle, mergedContent
+				case 'e':
+					title, content, editErr = editEntryText(editor, path, mergedTitle, mergedContent)
+					if editErr != nil {
+						return editErr
+					}
+					for hasConflictMarkers(content) && choice != 's' {
+						stderr.Write([]byte("The entry still contains conflict markers.\n"))
+						choice, promptErr = promptChoice(input, stderr, "[e]dit again, [s]ave anyway, [a]bort", "esa")
+						if promptErr != nil {
+							return promptErr
+						}
+						if choice == 'a' {
+							return fmt.Errorf("Entry was not updated. Your version of the entry is saved in %s", path)
+						}
+						if choice == 'e' {
+							title, content, editErr = editEntryText(editor, path, title, content)
+							if editErr != nil {
+								return editErr
+							}
+						}
+					}
+				case 'o':
+				case 'a':
+					if writeErr := ioutil.WriteFile(path, []byte(formatEntryText(title, content)), 0600); writeErr != nil {
+						return writeErr
+					}
+					return fmt.Errorf("Entry was not updated. Your version of the entry is saved in %s", path)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal")
+	cmd.Flags().StringVarP(&entryID, "id", "i", "", "ID of entry")
+	cmd.Flags().StringVar(&editor, "editor", "", "Command to edit the entry with (defaults to $VISUAL or $EDITOR)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+// Formats an entry for editing: the title on the first line and the content after a blank line.
+func formatEntryText(title, content string) string {
+	return title + "\n\n" + content
+}
+
+func parseEntryText(text string) (string, string) {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	newline := strings.Index(text, "\n")
+	if newline < 0 {
+		return strings.TrimSpace(text), ""
+	}
+	return strings.TrimSpace(text[:newline]), strings.TrimPrefix(text[newline+1:], "\n")
+}
+
+// Writes the entry to the file at path, opens it in the editor and returns the edited title and
+// content.
+func editEntryText(editor, path, title, content string) (string, string, error) {
+	if writeErr := ioutil.WriteFile(path, []byte(formatEntryText(title, content)), 0600); writeErr != nil {
+		return "", "", writeErr
+	}
+
+	editorArgs := strings.Fields(editor)
+	if len(editorArgs) == 0 {
+		return "", "", fmt.Errorf("No editor command given. Your version of the entry is saved in %s", path)
+	}
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if runErr := editorCmd.Run(); runErr != nil {
+		return "", "", fmt.Errorf("Editor failed: %s. Your version of the entry is saved in %s", runErr.Error(), path)
+	}
+
+	edited, readErr := ioutil.ReadFile(path)
+	if readErr != nil {
+		return "", "", readErr
+	}
+	editedTitle, editedContent := parseEntryText(string(edited))
+	return editedTitle, editedContent, nil
+}
+
+// Titles are merged as a whole: if both sides changed the title, ours is kept and a conflict is
+// reported.
+func mergeTitle(base, ours, theirs string) (string, bool) {
+	switch {
+	case ours == base:
+		return theirs, false
+	case theirs == base, ours == theirs:
+		return ours, false
+	default:
+		return ours, true
+	}
+}
+
+func hasConflictMarkers(content string) bool {
+	for _, line := range splitLines(content) {
+		if strings.HasPrefix(line, conflictMarkerOurs) || strings.HasPrefix(line, conflictMarkerTheirs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Asks the user to pick one of the choices, identified by their first letters, until they do. The
+// end of the input counts as aborting.
+func promptChoice(input *bufio.Reader, output io.Writer, question, choices string) (byte, error) {
+	for {
+		output.Write([]byte(question + "? "))
+		line, readErr := input.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if answer != "" && strings.IndexByte(choices, answer[0]) >= 0 {
+			return answer[0], nil
+		}
+		if readErr == io.EOF {
+			output.Write([]byte("\n"))
+			return 'a', nil
+		} else if readErr != nil {
+			return 0, readErr
+		}
+	}
+}
@@ -1,12 +1,20 @@
 package spirecmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/bugout-dev/bugout-go/cmd/bugout/cmdutils"
 	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/journalsync"
 	"github.com/bugout-dev/bugout-go/pkg/spire"
 	"github.com/spf13/cobra"
 )
@@ -24,8 +32,12 @@ func CreateJournalsCommand() *cobra.Command {
 	updateCmd := CreateJournalsUpdateCommand()
 	addMemberCmd := CreateJournalsAddMemberCommand()
 	removeMemberCmd := CreateJournalsRemoveMemberCommand()
+	membersCmd := CreateJournalsMembersCommand()
+	exportCmd := CreateJournalsExportCommand()
+	importCmd := CreateJournalsImportCommand()
+	syncCmd := CreateJournalsSyncCommand()
 
-	journalsCmd.AddCommand(createCmd, deleteCmd, getCmd, listCmd, updateCmd, addMemberCmd, removeMemberCmd)
+	journalsCmd.AddCommand(createCmd, deleteCmd, getCmd, listCmd, updateCmd, addMemberCmd, removeMemberCmd, membersCmd, exportCmd, importCmd, syncCmd)
 
 	return journalsCmd
 }
@@ -242,3 +254,421 @@ func CreateJournalsRemoveMemberCommand() *cobra.Command {
 
 	return cmd
 }
+
+func CreateJournalsMembersCommand() *cobra.Command {
+	var token, journalID string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "List the members of a Bugout journal and their permissions",
+		Long: `List the members of a Bugout journal and their permissions
+
+Prints a table with a row for each user or group which holds permissions on the journal, and a
+column for each permission. Use --json to get the raw list of permissions instead.`,
+		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+
+			permissionsList, err := spireClient.ListJournalMembers(token, journalID)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(permissionsList)
+				return encodeErr
+			}
+			return renderMembersMatrix(cmd.OutOrStdout(), permissionsList)
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the list of permissions as JSON instead of a table")
+
+	return cmd
+}
+
+// Renders a holder-by-permission table, with holders in the order in which they first appear.
+func renderMembersMatrix(w io.Writer, permissionsList spire.JournalPermissionsList) error {
+	type holder struct {
+		holderType  string
+		holderID    string
+		permissions map[string]bool
+	}
+	holders := []*holder{}
+	holdersByID := map[string]*holder{}
+	for _, scope := range permissionsList.Scopes {
+		key := scope.HolderType + "/" + scope.HolderID
+		if _, exists := holdersByID[key]; !exists {
+			holdersByID[key] = &holder{holderType: scope.HolderType, holderID: scope.HolderID, permissions: map[string]bool{}}
+			holders = append(holders, holdersByID[key])
+		}
+		holdersByID[key].permissions[scope.Permission] = true
+	}
+
+	permissions := spire.ValidJournalPermissions()
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := []string{"TYPE", "ID"}
+	for _, permission := range permissions {
+		header = append(header, strings.TrimPrefix(permission, "journals."))
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	for _, h := range holders {
+		row := []string{h.holderType, h.holderID}
+		for _, permission := range permissions {
+			if h.permissions[permission] {
+				row = append(row, "x")
+			} else {
+				row = append(row, "-")
+			}
+		}
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+func CreateJournalsExportCommand() *cobra.Command {
+	var token, journalID, outputFile string
+	var resume bool
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a Bugout journal to an archive file",
+		Long: `Export a Bugout journal to an archive file
+
+The archive is a JSON Lines file holding the journal, the permissions of its members and all of its
+entries. Use "bugout journals import" to load it into a journal.
+
+With --resume, an interrupted export to the same file is continued: entries which are already in the
+file are not written again.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if resume && outputFile == "-" {
+				return errors.New("--resume requires an output file (--output)")
+			}
+			return nil
+		},
+		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+
+			options := spire.ExportOptions{}
+			output := cmd.OutOrStdout()
+			if outputFile != "-" {
+				flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+				if resume {
+					exportedIDs, resumeErr := prepareExportResume(outputFile)
+					if resumeErr != nil {
+						return resumeErr
+					}
+					// The header and scopes precede the entries, so they are complete if any
+					// entries were written. Otherwise, the export starts over.
+					if len(exportedIDs) > 0 {
+						options.SkipEntries = exportedIDs
+						options.EntriesOnly = true
+						flags = os.O_WRONLY | os.O_APPEND
+					}
+				}
+				file, openErr := os.OpenFile(outputFile, flags, 0644)
+				if openErr != nil {
+					return openErr
+				}
+				defer file.Close()
+				output = file
+			}
+
+			summary, exportErr := spire.ExportJournal(context.Background(), spireClient, token, journalID, output, options)
+			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Exported %d scopes and %d entries (%d already exported)\n", summary.Scopes, summary.Entries, summary.Skipped)))
+			return exportErr
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal to export")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "-", "File to write the archive to (\"-\" for stdout)")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Continue an interrupted export to the output file")
+	cmd.MarkFlagFilename("output")
+
+	return cmd
+}
+
+// Reads a partially written archive and returns the IDs of the entries in it. A partially written
+// last line is removed from the file.
+func prepareExportResume(path string) (map[string]bool, error) {
+	exportedIDs := map[string]bool{}
+	contents, readErr := ioutil.ReadFile(path)
+	if os.IsNotExist(readErr) {
+		return exportedIDs, nil
+	} else if readErr != nil {
+		return exportedIDs, readErr
+	}
+
+	complete := contents
+	if lastNewline := bytes.LastIndexByte(contents, '\n'); lastNewline < len(contents)-1 {
+		complete = contents[:lastNewline+1]
+		if truncateErr := os.Truncate(path, int64(len(complete))); truncateErr != nil {
+			return exportedIDs, truncateErr
+		}
+	}
+
+	for i, line := range bytes.Split(complete, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record spire.ArchiveRecord
+		if decodeErr := json.Unmarshal(line, &record); decodeErr != nil {
+			return exportedIDs, fmt.Errorf("Could not parse archive record on line %d of %s: %s", i+1, path, decodeErr.Error())
+		}
+		if record.Type == spire.ArchiveRecordEntry && record.Entry != nil {
+			exportedIDs[record.Entry.Id] = true
+		}
+	}
+	return exportedIDs, nil
+}
+
+// A line of the state file for "bugout journals import". The first line records the journal, a
+// line with members_imported set records that the member scopes were granted, and every other line
+// records an imported entry.
+type importStateRecord struct {
+	JournalID       string `json:"journal_id"`
+	MembersImported bool   `json:"members_imported,omitempty"`
+	ArchivedID      string `json:"archived_id,omitempty"`
+	EntryID         string `json:"entry_id,omitempty"`
+}
+
+// Progress of an import, as recorded in its state file
+type importState struct {
+	JournalID       string
+	MembersImported bool
+	Entries         map[string]string
+}
+
+func CreateJournalsImportCommand() *cobra.Command {
+	var token, journalID, name, inputFile, stateFile string
+	var importMembers, dryRun bool
+	var holderIDs map[string]string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import an archive file into a Bugout journal",
+		Long: `Import an archive file written by "bugout journals export" into a Bugout journal
+
+By default, a new journal is created with the name of the archived journal. Use --journal to import
+into an existing journal instead. Entries get new IDs (and creation times) when they are imported.
+
+The journal and the IDs of the imported entries are recorded in a state file, next to the archive by
+default. If an import is interrupted, running the same command again resumes it from where it
+stopped, without creating another journal or granting member permissions again. The state file also
+serves as the mapping from archived entry IDs to new entry IDs.
+
+With --members, the permissions of the archived journal's members are granted on the journal. Use
+--map-holder to give a member a different ID on the target deployment, or an empty ID to leave it
+out (e.g. --map-holder <old user ID>=<new user ID>,<old group ID>=).
+
+With --dry-run, the archive is checked and a summary of what would be imported is printed, but
+nothing is created.`,
+		PreRunE: cmdutils.TokenArgPopulator,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := cmd.InOrStdin()
+			if inputFile != "-" {
+				file, openErr := os.Open(inputFile)
+				if openErr != nil {
+					return openErr
+				}
+				defer file.Close()
+				input = file
+				if stateFile == "" {
+					stateFile = inputFile + ".import-state"
+				}
+			}
+
+			options := spire.ImportOptions{
+				JournalID:       journalID,
+				Name:            name,
+				ImportMembers:   importMembers,
+				HolderIDs:       holderIDs,
+				ImportedEntries: map[string]string{},
+				DryRun:          dryRun,
+			}
+
+			state := importState{}
+			if stateFile != "" {
+				var loadErr error
+				state, loadErr = loadImportState(stateFile)
+				if loadErr != nil {
+					return loadErr
+				}
+				if state.JournalID != "" {
+					if journalID != "" && journalID != state.JournalID {
+						return fmt.Errorf("State file (%s) is for a different journal: %s", stateFile, state.JournalID)
+					}
+					options.JournalID = state.JournalID
+					options.MembersImported = state.MembersImported
+					options.ImportedEntries = state.Entries
+					cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Resuming import into journal %s (%d entries already imported)\n", state.JournalID, len(state.Entries))))
+				}
+			}
+
+			if !dryRun && stateFile != "" {
+				stateOutput, openErr := os.OpenFile(stateFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+				if openErr != nil {
+					return openErr
+				}
+				defer stateOutput.Close()
+				stateEncoder := json.NewEncoder(stateOutput)
+				writeState := func(record importStateRecord) error {
+					if encodeErr := stateEncoder.Encode(record); encodeErr != nil {
+						return fmt.Errorf("Could not write to import state file (%s): %s", stateFile, encodeErr.Error())
+					}
+					return nil
+				}
+				options.OnJournal = func(journalID string) error {
+					if journalID == state.JournalID {
+						return nil
+					}
+					return writeState(importStateRecord{JournalID: journalID})
+				}
+				options.OnMembers = func(journalID string) error {
+					return writeState(importStateRecord{JournalID: journalID, MembersImported: true})
+				}
+				options.OnEntry = func(journalID, archivedID string, entry spire.Entry) error {
+					return writeState(importStateRecord{JournalID: journalID, ArchivedID: archivedID, EntryID: entry.Id})
+				}
+			}
+
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+
+			summary, importErr := spire.ImportJournal(context.Background(), spireClient, token, input, options)
+			if importErr != nil {
+				if summary.Entries > 0 || (summary.JournalID != "" && summary.JournalID != state.JournalID) {
+					cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Imported %d entries before failing. Run the same command again to resume.\n", summary.Entries)))
+				}
+				return importErr
+			}
+
+			if dryRun {
+				cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("Dry run: would import %d scopes and %d entries from journal \"%s\" (%d already imported)\n", summary.Scopes, summary.Entries, summary.ArchivedName, summary.Skipped)))
+			}
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(summary)
+			return encodeErr
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of an existing journal to import into")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Name of the journal to create (defaults to the archived name)")
+	cmd.Flags().StringVarP(&inputFile, "file", "f", "-", "Archive file to import (\"-\" for stdin)")
+	cmd.Flags().StringVar(&stateFile, "state", "", "File to record imported entries in, for resuming (defaults to <file>.import-state)")
+	cmd.Flags().BoolVar(&importMembers, "members", false, "Grant the archived member permissions on the journal")
+	cmd.Flags().StringToStringVar(&holderIDs, "map-holder", nil, "Map archived user and group IDs to new IDs (in the format <old ID>=<new ID>)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the archive and print a summary without importing anything")
+	cmd.MarkFlagFilename("file")
+
+	return cmd
+}
+
+// Loads the progress recorded in an import state file. A missing state file is not an error.
+func loadImportState(path string) (importState, error) {
+	state := importState{Entries: map[string]string{}}
+	file, openErr := os.Open(path)
+	if os.IsNotExist(openErr) {
+		return state, nil
+	} else if openErr != nil {
+		return state, openErr
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	var validLength int64
+	for {
+		var record importStateRecord
+		decodeErr := decoder.Decode(&record)
+		if decodeErr == io.EOF {
+			break
+		} else if decodeErr == io.ErrUnexpectedEOF {
+			// The last record was cut short when the import was interrupted. Remove it so that new
+			// records can be appended.
+			return state, os.Truncate(path, validLength)
+		} else if decodeErr != nil {
+			return state, fmt.Errorf("Could not parse import state file (%s): %s", path, decodeErr.Error())
+		}
+		validLength = decoder.InputOffset()
+		if state.JournalID != "" && record.JournalID != state.JournalID {
+			return state, fmt.Errorf("Import state file (%s) refers to more than one journal", path)
+		}
+		state.JournalID = record.JournalID
+		if record.MembersImported {
+			state.MembersImported = true
+		}
+		if record.ArchivedID != "" {
+			state.Entries[record.ArchivedID] = record.EntryID
+		}
+	}
+	return state, nil
+}
+
+func CreateJournalsSyncCommand() *cobra.Command {
+	var token, journalID, dir string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronize a Bugout journal with a directory of Markdown files",
+		Long: `Synchronize a Bugout journal with a directory of Markdown files
+
+Each entry in the journal is kept in a Markdown file in the directory. The file's YAML front matter
+holds the entry's ID, title, tags and context, and the rest of the file holds its content:
+
+	---
+	id: "9c1c5e8a-..."
+	title: "Deploying to production"
+	tags:
+	  - runbook
+	---
+	The content of the entry.
+
+Entries which changed in the journal are written to their files, and files which changed locally are
+pushed to their entries. Files without an ID are created as new entries. Entries and files which both
+changed since the last sync are reported as conflicts and left alone. The state of the last sync is
+kept in ` + journalsync.StateFileName + ` in the directory.
+
+The changes are written to stdout as JSON. The command fails if there were conflicts or errors.`,
+		PreRunE: cmdutils.CompositePopulator(cmdutils.TokenArgPopulator, cmdutils.JournalIDArgPopulator),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spireClient, clientErr := spire.ClientFromEnv()
+			if clientErr != nil {
+				return clientErr
+			}
+
+			options := journalsync.Options{DryRun: dryRun}
+			report, syncErr := journalsync.Sync(context.Background(), spireClient, token, journalID, dir, options)
+			if syncErr != nil {
+				return syncErr
+			}
+
+			encodeErr := json.NewEncoder(cmd.OutOrStdout()).Encode(&report)
+			if encodeErr != nil {
+				return encodeErr
+			}
+			if report.Conflicts() > 0 || report.Errors() > 0 {
+				return fmt.Errorf("Sync finished with %d conflicts and %d errors", report.Conflicts(), report.Errors())
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Bugout access token to use for the request")
+	cmd.Flags().StringVarP(&journalID, "journal", "j", "", "ID of journal to synchronize")
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory of Markdown files to synchronize with the journal")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report the changes without making them")
+	cmd.MarkFlagRequired("dir")
+	cmd.MarkFlagDirname("dir")
+
+	return cmd
+}
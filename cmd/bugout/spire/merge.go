@@ -0,0 +1,155 @@
+package spirecmd
+
+import (
+	"strings"
+)
+
+// Markers around conflicting hunks in the output of mergeText, in the style of "git merge" with
+// merge.conflictStyle set to diff3.
+const (
+	conflictMarkerOurs   = "<<<<<<< yours"
+	conflictMarkerBase   = "||||||| original"
+	conflictMarkerSplit  = "======="
+	conflictMarkerTheirs = ">>>>>>> theirs"
+)
+
+// Merges two sets of line-based changes made to base. Hunks which were changed on only one side
+// take that side's version. Hunks which were changed differently on both sides are written out with
+// conflict markers, and their number is returned.
+func mergeText(base, ours, theirs string) (string, int) {
+	baseLines, ourLines, theirLines := splitLines(base), splitLines(ours), splitLines(theirs)
+	ourMatches := matchLines(baseLines, ourLines)
+	theirMatches := matchLines(baseLines, theirLines)
+
+	var merged strings.Builder
+	conflicts := 0
+	resolve := func(baseHunk, ourHunk, theirHunk []string) {
+		switch {
+		case equalLines(ourHunk, baseHunk):
+			writeLines(&merged, theirHunk)
+		case equalLines(theirHunk, baseHunk), equalLines(ourHunk, theirHunk):
+			writeLines(&merged, ourHunk)
+		default:
+			conflicts++
+			merged.WriteString(conflictMarkerOurs + "\n")
+			writeLines(&merged, terminated(ourHunk))
+			merged.WriteString(conflictMarkerBase + "\n")
+			writeLines(&merged, terminated(baseHunk))
+			merged.WriteString(conflictMarkerSplit + "\n")
+			writeLines(&merged, terminated(theirHunk))
+			merged.WriteString(conflictMarkerTheirs + "\n")
+		}
+	}
+
+	// Walks through the base, stopping at lines which are kept on both sides. The lines between
+	// these stable lines form the hunks which may have changed.
+	i, a, b := 0, 0, 0
+	for {
+		k := i
+		for k < len(baseLines) && (ourMatches[k] < 0 || theirMatches[k] < 0) {
+			k++
+		}
+		if k == len(baseLines) {
+			resolve(baseLines[i:], ourLines[a:], theirLines[b:])
+			break
+		}
+		if k > i || ourMatches[k] > a || theirMatches[k] > b {
+			resolve(baseLines[i:k], ourLines[a:ourMatches[k]], theirLines[b:theirMatches[k]])
+		}
+		merged.WriteString(baseLines[k])
+		i, a, b = k+1, ourMatches[k]+1, theirMatches[k]+1
+	}
+	return merged.String(), conflicts
+}
+
+// Splits text into lines, keeping the line endings.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Returns, for each line of base, the index of the same line in other according to a longest common
+// subsequence of the two, or -1 if the line was removed or changed.
+func matchLines(base, other []string) []int {
+	matches := make([]int, len(base))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	// Lines at the start and end which did not change are matched directly, which keeps the table
+	// below small for typical edits.
+	prefix := 0
+	for prefix < len(base) && prefix < len(other) && base[prefix] == other[prefix] {
+		matches[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(base)-prefix && suffix < len(other)-prefix && base[len(base)-1-suffix] == other[len(other)-1-suffix] {
+		matches[len(base)-1-suffix] = len(other) - 1 - suffix
+		suffix++
+	}
+
+	baseMiddle := base[prefix : len(base)-suffix]
+	otherMiddle := other[prefix : len(other)-suffix]
+	// lengths[i][j] is the length of the longest common subsequence of baseMiddle[i:] and
+	// otherMiddle[j:].
+	lengths := make([][]int, len(baseMiddle)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(otherMiddle)+1)
+	}
+	for i := len(baseMiddle) - 1; i >= 0; i-- {
+		for j := len(otherMiddle) - 1; j >= 0; j-- {
+			if baseMiddle[i] == otherMiddle[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(baseMiddle) && j < len(otherMiddle); {
+		switch {
+		case baseMiddle[i] == otherMiddle[j]:
+			matches[prefix+i] = prefix + j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+func equalLines(left, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(builder *strings.Builder, lines []string) {
+	for _, line := range lines {
+		builder.WriteString(line)
+	}
+}
+
+// Makes sure the last line ends with a newline, so that a conflict marker can follow it.
+func terminated(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	result := append([]string{}, lines...)
+	result[len(result)-1] += "\n"
+	return result
+}
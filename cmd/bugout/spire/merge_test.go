@@ -0,0 +1,259 @@
+package spirecmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeText(t *testing.T) {
+	cases := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		merged    string
+		conflicts int
+	}{
+		{
+			name:   "no changes",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nb\nc\n",
+		},
+		{
+			name:   "only ours changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nB\nc\n",
+		},
+		{
+			name:   "only theirs changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nC\n",
+			merged: "a\nb\nC\n",
+		},
+		{
+			name:   "clean merge of separate changes",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "a\nB\nc\nd\ne\n",
+			theirs: "a\nb\nc\nD\ne\nf\n",
+			merged: "a\nB\nc\nD\ne\nf\n",
+		},
+		{
+			name:   "clean merge of insertions and deletions",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "new\na\nb\nd\ne\n",
+			theirs: "a\nb\nc\nd\ninserted\ne\n",
+			merged: "new\na\nb\nd\ninserted\ne\n",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nB\nc\n",
+			merged: "a\nB\nc\n",
+		},
+		{
+			name:      "conflict",
+			base:      "a\nb\nc\n",
+			ours:      "a\nours\nc\n",
+			theirs:    "a\ntheirs\nc\n",
+			merged:    "a\n<<<<<<< yours\nours\n||||||| original\nb\n=======\ntheirs\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "conflict and clean change",
+			base:      "a\nb\nc\nd\ne\n",
+			ours:      "A\nb\nc\nours\ne\n",
+			theirs:    "a\nb\nc\ntheirs\ne\n",
+			merged:    "A\nb\nc\n<<<<<<< yours\nours\n||||||| original\nd\n=======\ntheirs\n>>>>>>> theirs\ne\n",
+			conflicts: 1,
+		},
+		{
+			name:      "two conflicts",
+			base:      "a\nb\nc\n",
+			ours:      "1\nb\n3\n",
+			theirs:    "one\nb\nthree\n",
+			merged:    "<<<<<<< yours\n1\n||||||| original\na\n=======\none\n>>>>>>> theirs\nb\n<<<<<<< yours\n3\n||||||| original\nc\n=======\nthree\n>>>>>>> theirs\n",
+			conflicts: 2,
+		},
+		{
+			// Like "git merge", changes to adjacent lines are not merged automatically.
+			name:      "adjacent-line edits on both sides",
+			base:      "a\nb\nc\nd\n",
+			ours:      "a\nB\nc\nd\n",
+			theirs:    "a\nb\nC\nd\n",
+			merged:    "a\n<<<<<<< yours\nB\nc\n||||||| original\nb\nc\n=======\nb\nC\n>>>>>>> theirs\nd\n",
+			conflicts: 1,
+		},
+		{
+			name:   "insertion at end of file",
+			base:   "a\nb\n",
+			ours:   "A\nb\n",
+			theirs: "a\nb\nc\n",
+			merged: "A\nb\nc\n",
+		},
+		{
+			name:   "insertion at end of file without trailing newline",
+			base:   "a\nb",
+			ours:   "a\nb",
+			theirs: "a\nb\nc",
+			merged: "a\nb\nc",
+		},
+		{
+			name:      "different insertions at end of file",
+			base:      "a\n",
+			ours:      "a\nours",
+			theirs:    "a\ntheirs",
+			merged:    "a\n<<<<<<< yours\nours\n||||||| original\n=======\ntheirs\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name:   "empty base, same content",
+			base:   "",
+			ours:   "a\n",
+			theirs: "a\n",
+			merged: "a\n",
+		},
+		{
+			name:   "empty base, only ours",
+			base:   "",
+			ours:   "a\nb\n",
+			theirs: "",
+			merged: "a\nb\n",
+		},
+		{
+			name:      "empty base, different content",
+			base:      "",
+			ours:      "ours\n",
+			theirs:    "theirs\n",
+			merged:    "<<<<<<< yours\nours\n||||||| original\n=======\ntheirs\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name:   "empty ours",
+			base:   "a\nb\n",
+			ours:   "",
+			theirs: "a\nb\n",
+			merged: "",
+		},
+		{
+			name:   "empty theirs",
+			base:   "a\nb\n",
+			ours:   "a\nb\n",
+			theirs: "",
+			merged: "",
+		},
+		{
+			name:      "empty theirs, changed ours",
+			base:      "a\nb\n",
+			ours:      "a\nB\n",
+			theirs:    "",
+			merged:    "<<<<<<< yours\na\nB\n||||||| original\na\nb\n=======\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name:   "all empty",
+			base:   "",
+			ours:   "",
+			theirs: "",
+			merged: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			merged, conflicts := mergeText(c.base, c.ours, c.theirs)
+			if merged != c.merged {
+				t.Errorf("Expected merge:\n%s\ngot:\n%s", c.merged, merged)
+			}
+			if conflicts != c.conflicts {
+				t.Errorf("Expected %d conflicts, got %d", c.conflicts, conflicts)
+			}
+			if hasConflictMarkers(merged) != (c.conflicts > 0) {
+				t.Errorf("Conflict markers do not match the number of conflicts: %d", conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeTextIsSymmetric(t *testing.T) {
+	base := "a\nb\nc\nd\ne\nf\n"
+	ours := "a\nB\nc\nd\ne\nf\ng\n"
+	theirs := "a\nb\nc\nD\ne\nf\n"
+
+	merged, conflicts := mergeText(base, ours, theirs)
+	swapped, swappedConflicts := mergeText(base, theirs, ours)
+	if conflicts != 0 || swappedConflicts != 0 {
+		t.Fatalf("Expected clean merges, got %d and %d conflicts", conflicts, swappedConflicts)
+	}
+	if merged != swapped {
+		t.Errorf("Merge depends on the order of the sides:\n%s\nand\n%s", merged, swapped)
+	}
+}
+
+func TestMergeTitle(t *testing.T) {
+	cases := []struct {
+		base, ours, theirs string
+		merged             string
+		conflict           bool
+	}{
+		{"title", "title", "title", "title", false},
+		{"title", "ours", "title", "ours", false},
+		{"title", "title", "theirs", "theirs", false},
+		{"title", "same", "same", "same", false},
+		{"title", "ours", "theirs", "ours", true},
+	}
+
+	for _, c := range cases {
+		merged, conflict := mergeTitle(c.base, c.ours, c.theirs)
+		if merged != c.merged || conflict != c.conflict {
+			t.Errorf("mergeTitle(%q, %q, %q): expected %q (conflict: %v), got %q (conflict: %v)", c.base, c.ours, c.theirs, c.merged, c.conflict, merged, conflict)
+		}
+	}
+}
+
+func TestEntryText(t *testing.T) {
+	cases := []struct {
+		text    string
+		title   string
+		content string
+	}{
+		{"Title\n\nContent\n", "Title", "Content\n"},
+		{"Title\r\n\r\nLine one\r\nLine two", "Title", "Line one\nLine two"},
+		{"  Title  \nContent right after the title", "Title", "Content right after the title"},
+		{"Title only", "Title only", ""},
+		{"", "", ""},
+	}
+
+	for _, c := range cases {
+		title, content := parseEntryText(c.text)
+		if title != c.title || content != c.content {
+			t.Errorf("parseEntryText(%q): expected %q and %q, got %q and %q", c.text, c.title, c.content, title, content)
+		}
+	}
+
+	title, content := parseEntryText(formatEntryText("Title", "\nContent starting with a blank line\n"))
+	if title != "Title" || content != "\nContent starting with a blank line\n" {
+		t.Errorf("Entry text did not round trip: %q and %q", title, content)
+	}
+}
+
+func TestEditEntryTextWithoutEditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entry.md")
+	for _, editor := range []string{"", "   ", "\t"} {
+		_, _, err := editEntryText(editor, path, "Title", "Content")
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("Expected an error pointing to the saved entry for editor %q, got %v", editor, err)
+		}
+		saved, readErr := ioutil.ReadFile(path)
+		if readErr != nil || string(saved) != formatEntryText("Title", "Content") {
+			t.Errorf("Expected the entry to be saved in %s, got %q (%v)", path, saved, readErr)
+		}
+	}
+}
@@ -58,7 +58,12 @@ Specify the wrapped command using "--" followed by the command:
 				return err
 			}
 
-			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("\n\nBugout entry created at: %s/journals/%s/%s\n", cmdutils.BugoutURL(), journalID, response.Id)))
+			bugoutURL, urlErr := cmdutils.BugoutURL()
+			if urlErr != nil {
+				return urlErr
+			}
+
+			cmd.ErrOrStderr().Write([]byte(fmt.Sprintf("\n\nBugout entry created at: %s/journals/%s/%s\n", bugoutURL, journalID, response.Id)))
 
 			if result.ExitCode > 0 {
 				os.Exit(result.ExitCode)
@@ -2,4 +2,9 @@ module github.com/bugout-dev/bugout-go
 
 go 1.15
 
-require github.com/spf13/cobra v1.1.1
+require (
+	github.com/spf13/cobra v1.1.1
+	golang.org/x/sys v0.0.0-20210423185535-09eb48e85fd7 // indirect
+	golang.org/x/term v0.0.0-20210422114643-f5beecf764ed
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -1,6 +1,7 @@
 package brood
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,6 +13,10 @@ import (
 )
 
 func (client BroodClient) CreateApplication(token, groupId, name, description string) (Application, error) {
+	return client.CreateApplicationContext(context.Background(), token, groupId, name, description)
+}
+
+func (client BroodClient) CreateApplicationContext(ctx context.Context, token, groupId, name, description string) (Application, error) {
 	applicationsRoute := client.Routes.Applications
 	data := url.Values{}
 	data.Add("group_id", groupId)
@@ -21,7 +26,7 @@ func (client BroodClient) CreateApplication(token, groupId, name, description st
 	}
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", applicationsRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", applicationsRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return Application{}, requestErr
 	}
@@ -29,7 +34,7 @@ func (client BroodClient) CreateApplication(token, groupId, name, description st
 	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "CreateApplication")
 	if err != nil {
 		return Application{}, err
 	}
@@ -46,16 +51,20 @@ func (client BroodClient) CreateApplication(token, groupId, name, description st
 }
 
 func (client BroodClient) GetApplication(token, applicationId string) (Application, error) {
+	return client.GetApplicationContext(context.Background(), token, applicationId)
+}
+
+func (client BroodClient) GetApplicationContext(ctx context.Context, token, applicationId string) (Application, error) {
 	applicationsRoute := client.Routes.Applications
 	specificApplicationRoute := fmt.Sprintf("%s/%s", applicationsRoute, applicationId)
-	request, requestErr := http.NewRequest("GET", specificApplicationRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", specificApplicationRoute, nil)
 	if requestErr != nil {
 		return Application{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "GetApplication")
 	if err != nil {
 		return Application{}, err
 	}
@@ -72,8 +81,12 @@ func (client BroodClient) GetApplication(token, applicationId string) (Applicati
 }
 
 func (client BroodClient) ListApplications(token, groupId string) (ApplicationsList, error) {
+	return client.ListApplicationsContext(context.Background(), token, groupId)
+}
+
+func (client BroodClient) ListApplicationsContext(ctx context.Context, token, groupId string) (ApplicationsList, error) {
 	applicationsRoute := client.Routes.Applications
-	request, requestErr := http.NewRequest("GET", applicationsRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", applicationsRoute, nil)
 	if requestErr != nil {
 		return ApplicationsList{}, requestErr
 	}
@@ -86,7 +99,7 @@ func (client BroodClient) ListApplications(token, groupId string) (ApplicationsL
 	}
 	request.URL.RawQuery = query.Encode()
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "ListApplications")
 	if err != nil {
 		return ApplicationsList{}, err
 	}
@@ -103,17 +116,103 @@ func (client BroodClient) ListApplications(token, groupId string) (ApplicationsL
 }
 
 func (client BroodClient) DeleteApplication(token, applicationId string) (Application, error) {
+	return client.DeleteApplicationContext(context.Background(), token, applicationId)
+}
+
+func (client BroodClient) DeleteApplicationContext(ctx context.Context, token, applicationId string) (Application, error) {
 	applicationsRoute := client.Routes.Applications
 	deletionRoute := fmt.Sprintf("%s/%s", applicationsRoute, applicationId)
 
-	request, requestErr := http.NewRequest("DELETE", deletionRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", deletionRoute, nil)
+	if requestErr != nil {
+		return Application{}, requestErr
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "DeleteApplication")
+	if err != nil {
+		return Application{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return Application{}, statusErr
+	}
+
+	var application Application
+	decodeErr := json.NewDecoder(response.Body).Decode(&application)
+	return application, decodeErr
+}
+
+func (client BroodClient) UpdateApplication(token, applicationId, name, description string) (Application, error) {
+	return client.UpdateApplicationContext(context.Background(), token, applicationId, name, description)
+}
+
+// UpdateApplicationContext changes the name and description of an application. An empty name or
+// description is left unchanged.
+func (client BroodClient) UpdateApplicationContext(ctx context.Context, token, applicationId, name, description string) (Application, error) {
+	applicationsRoute := client.Routes.Applications
+	specificApplicationRoute := fmt.Sprintf("%s/%s", applicationsRoute, applicationId)
+	data := url.Values{}
+	if name != "" {
+		data.Add("name", name)
+	}
+	if description != "" {
+		data.Add("description", description)
+	}
+	encodedData := data.Encode()
+
+	request, requestErr := http.NewRequestWithContext(ctx, "PUT", specificApplicationRoute, strings.NewReader(encodedData))
+	if requestErr != nil {
+		return Application{}, requestErr
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "UpdateApplication")
+	if err != nil {
+		return Application{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return Application{}, statusErr
+	}
+
+	var application Application
+	decodeErr := json.NewDecoder(response.Body).Decode(&application)
+	return application, decodeErr
+}
+
+func (client BroodClient) TransferApplication(token, applicationId, groupId string) (Application, error) {
+	return client.TransferApplicationContext(context.Background(), token, applicationId, groupId)
+}
+
+// TransferApplicationContext moves an application to another group. The user must be an owner of
+// both the current group of the application and the new one. The users and resources of the
+// application are kept.
+func (client BroodClient) TransferApplicationContext(ctx context.Context, token, applicationId, groupId string) (Application, error) {
+	applicationsRoute := client.Routes.Applications
+	transferRoute := fmt.Sprintf("%s/%s/group", applicationsRoute, applicationId)
+	data := url.Values{}
+	data.Add("group_id", groupId)
+	encodedData := data.Encode()
+
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", transferRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return Application{}, requestErr
 	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "TransferApplication")
 	if err != nil {
 		return Application{}, err
 	}
@@ -128,3 +227,34 @@ func (client BroodClient) DeleteApplication(token, applicationId string) (Applic
 	decodeErr := json.NewDecoder(response.Body).Decode(&application)
 	return application, decodeErr
 }
+
+func (client BroodClient) ListApplicationUsers(token, applicationId string) (ApplicationUsersList, error) {
+	return client.ListApplicationUsersContext(context.Background(), token, applicationId)
+}
+
+// ListApplicationUsersContext lists the users which were created for an application.
+func (client BroodClient) ListApplicationUsersContext(ctx context.Context, token, applicationId string) (ApplicationUsersList, error) {
+	applicationsRoute := client.Routes.Applications
+	usersRoute := fmt.Sprintf("%s/%s/users", applicationsRoute, applicationId)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", usersRoute, nil)
+	if requestErr != nil {
+		return ApplicationUsersList{}, requestErr
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "ListApplicationUsers")
+	if err != nil {
+		return ApplicationUsersList{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return ApplicationUsersList{}, statusErr
+	}
+
+	var users ApplicationUsersList
+	decodeErr := json.NewDecoder(response.Body).Decode(&users)
+	return users, decodeErr
+}
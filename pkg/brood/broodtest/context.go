@@ -0,0 +1,298 @@
+// Context variants of the Fake methods, for brood.BroodCallerContext and the optional Context
+// interfaces. They fail if the context is already done and otherwise behave exactly like the
+// methods they wrap.
+
+package broodtest
+
+import (
+	"context"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+)
+
+func (fake *Fake) PingContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.Ping()
+}
+
+func (fake *Fake) VersionContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.Version()
+}
+
+func (fake *Fake) AuthContext(ctx context.Context, token string) (brood.AuthUser, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.AuthUser{}, err
+	}
+	return fake.Auth(token)
+}
+
+func (fake *Fake) CreateUserContext(ctx context.Context, username, email, password string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.CreateUser(username, email, password)
+}
+
+func (fake *Fake) GenerateTokenContext(ctx context.Context, username, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.GenerateToken(username, password)
+}
+
+func (fake *Fake) CreateApplicationUserContext(ctx context.Context, applicationId, applicationToken, username, email, password string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.CreateApplicationUser(applicationId, applicationToken, username, email, password)
+}
+
+func (fake *Fake) GenerateApplicationTokenContext(ctx context.Context, applicationId, applicationToken, username, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.GenerateApplicationToken(applicationId, applicationToken, username, password)
+}
+
+func (fake *Fake) AnnotateTokenContext(ctx context.Context, token, tokenType, note string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.AnnotateToken(token, tokenType, note)
+}
+
+func (fake *Fake) ListTokensContext(ctx context.Context, token string) (brood.UserTokensList, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserTokensList{}, err
+	}
+	return fake.ListTokens(token)
+}
+
+func (fake *Fake) RevokeTokenContext(ctx context.Context, token, targetToken string) (brood.UserToken, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserToken{}, err
+	}
+	return fake.RevokeToken(token, targetToken)
+}
+
+func (fake *Fake) FindUserContext(ctx context.Context, token string, queryParameters map[string]string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.FindUser(token, queryParameters)
+}
+
+func (fake *Fake) FindApplicationUserContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.FindApplicationUser(token, applicationId, queryParameters)
+}
+
+func (fake *Fake) GetUserContext(ctx context.Context, token string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.GetUser(token)
+}
+
+func (fake *Fake) VerifyUserContext(ctx context.Context, token, code string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.VerifyUser(token, code)
+}
+
+func (fake *Fake) ChangePasswordContext(ctx context.Context, token, currentPassword, newPassword string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.ChangePassword(token, currentPassword, newPassword)
+}
+
+func (fake *Fake) RequestPasswordResetContext(ctx context.Context, email string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.RequestPasswordReset(email)
+}
+
+func (fake *Fake) ConfirmPasswordResetContext(ctx context.Context, resetID, newPassword string) (brood.User, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.User{}, err
+	}
+	return fake.ConfirmPasswordReset(resetID, newPassword)
+}
+
+func (fake *Fake) CreateGroupContext(ctx context.Context, token, name string) (brood.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Group{}, err
+	}
+	return fake.CreateGroup(token, name)
+}
+
+func (fake *Fake) GetUserGroupsContext(ctx context.Context, token string) (brood.UserGroupsList, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserGroupsList{}, err
+	}
+	return fake.GetUserGroups(token)
+}
+
+func (fake *Fake) GetGroupContext(ctx context.Context, token, groupID string) (brood.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Group{}, err
+	}
+	return fake.GetGroup(token, groupID)
+}
+
+func (fake *Fake) ListGroupMembersContext(ctx context.Context, token, groupID string) (brood.GroupUsersList, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.GroupUsersList{}, err
+	}
+	return fake.ListGroupMembers(token, groupID)
+}
+
+func (fake *Fake) DeleteGroupContext(ctx context.Context, token, groupID string) (brood.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Group{}, err
+	}
+	return fake.DeleteGroup(token, groupID)
+}
+
+func (fake *Fake) RenameGroupContext(ctx context.Context, token, groupID, name string) (brood.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Group{}, err
+	}
+	return fake.RenameGroup(token, groupID, name)
+}
+
+func (fake *Fake) AddUserToGroupContext(ctx context.Context, token, groupID, username, role string) (brood.UserGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserGroup{}, err
+	}
+	return fake.AddUserToGroup(token, groupID, username, role)
+}
+
+func (fake *Fake) SetUserGroupRoleContext(ctx context.Context, token, groupID, username string, role brood.GroupRole) (brood.UserGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserGroup{}, err
+	}
+	return fake.SetUserGroupRole(token, groupID, username, role)
+}
+
+func (fake *Fake) RemoveUserFromGroupContext(ctx context.Context, token, groupID, username string) (brood.UserGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.UserGroup{}, err
+	}
+	return fake.RemoveUserFromGroup(token, groupID, username)
+}
+
+func (fake *Fake) CreateResourceContext(ctx context.Context, token, applicationId string, resourceData interface{}) (brood.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Resource{}, err
+	}
+	return fake.CreateResource(token, applicationId, resourceData)
+}
+
+func (fake *Fake) UpdateResourceContext(ctx context.Context, token, resourceId string, update interface{}, dropKeys []string) (brood.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Resource{}, err
+	}
+	return fake.UpdateResource(token, resourceId, update, dropKeys)
+}
+
+func (fake *Fake) GetResourceContext(ctx context.Context, token, resourceId string) (brood.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Resource{}, err
+	}
+	return fake.GetResource(token, resourceId)
+}
+
+func (fake *Fake) GetResourcesContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (brood.Resources, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Resources{}, err
+	}
+	return fake.GetResources(token, applicationId, queryParameters)
+}
+
+func (fake *Fake) DeleteResourceContext(ctx context.Context, token, resourceId string) (brood.Resource, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Resource{}, err
+	}
+	return fake.DeleteResource(token, resourceId)
+}
+
+func (fake *Fake) GetResourceHoldersContext(ctx context.Context, token, resourceId string) (brood.ResourceHolders, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	return fake.GetResourceHolders(token, resourceId)
+}
+
+func (fake *Fake) AddResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	return fake.AddResourceHolderPermissions(token, resourceId, resourceHolder)
+}
+
+func (fake *Fake) DeleteResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	return fake.DeleteResourceHolderPermissions(token, resourceId, resourceHolder)
+}
+
+func (fake *Fake) CreateApplicationContext(ctx context.Context, token, groupId, name, description string) (brood.Application, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Application{}, err
+	}
+	return fake.CreateApplication(token, groupId, name, description)
+}
+
+func (fake *Fake) GetApplicationContext(ctx context.Context, token, applicationId string) (brood.Application, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Application{}, err
+	}
+	return fake.GetApplication(token, applicationId)
+}
+
+func (fake *Fake) ListApplicationsContext(ctx context.Context, token, groupId string) (brood.ApplicationsList, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.ApplicationsList{}, err
+	}
+	return fake.ListApplications(token, groupId)
+}
+
+func (fake *Fake) DeleteApplicationContext(ctx context.Context, token, applicationId string) (brood.Application, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Application{}, err
+	}
+	return fake.DeleteApplication(token, applicationId)
+}
+
+func (fake *Fake) UpdateApplicationContext(ctx context.Context, token, applicationId, name, description string) (brood.Application, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Application{}, err
+	}
+	return fake.UpdateApplication(token, applicationId, name, description)
+}
+
+func (fake *Fake) TransferApplicationContext(ctx context.Context, token, applicationId, groupId string) (brood.Application, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.Application{}, err
+	}
+	return fake.TransferApplication(token, applicationId, groupId)
+}
+
+func (fake *Fake) ListApplicationUsersContext(ctx context.Context, token, applicationId string) (brood.ApplicationUsersList, error) {
+	if err := ctx.Err(); err != nil {
+		return brood.ApplicationUsersList{}, err
+	}
+	return fake.ListApplicationUsers(token, applicationId)
+}
@@ -0,0 +1,1225 @@
+// Package broodtest provides an in-memory implementation of brood.BroodCaller for use in tests.
+package broodtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+)
+
+// Valid permissions for holders of a resource.
+var validResourcePermissions = []string{"admin", "create", "read", "update", "delete"}
+
+type userRecord struct {
+	User             brood.User `json:"user"`
+	Password         string     `json:"password"`
+	VerificationCode string     `json:"verification_code"`
+	// ID of the pending password reset, if any
+	PasswordResetID string `json:"password_reset_id,omitempty"`
+}
+
+type groupRecord struct {
+	Group brood.Group `json:"group"`
+	// Maps user IDs to their role in the group
+	Members map[string]brood.GroupRole `json:"members"`
+}
+
+type resourceRecord struct {
+	Resource brood.Resource `json:"resource"`
+	// Maps holder IDs to their permissions on the resource
+	Holders map[string]brood.ResourceHolder `json:"holders"`
+}
+
+// The complete contents of a Fake, in the form in which SaveState serializes it.
+type fakeState struct {
+	Users        map[string]*userRecord        `json:"users"`
+	Tokens       map[string]*brood.UserToken   `json:"tokens"`
+	Groups       map[string]*groupRecord       `json:"groups"`
+	Applications map[string]*brood.Application `json:"applications"`
+	Resources    map[string]*resourceRecord    `json:"resources"`
+	Counter      int                           `json:"counter"`
+}
+
+func newState() fakeState {
+	return fakeState{
+		Users:        map[string]*userRecord{},
+		Tokens:       map[string]*brood.UserToken{},
+		Groups:       map[string]*groupRecord{},
+		Applications: map[string]*brood.Application{},
+		Resources:    map[string]*resourceRecord{},
+	}
+}
+
+// Fake is a stateful, in-memory implementation of brood.BroodCaller, brood.BroodCallerContext and
+// the optional interfaces such as brood.GroupRoleCaller. It enforces group roles and resource
+// holder permissions the same way Brood does, and returns *brood.APIError values with matching
+// status codes on failure. It is safe for concurrent use.
+//
+// Use the Seed* methods to set up state without going through the API, and the inspection
+// methods (Users, Groups, ...) to make assertions about it.
+type Fake struct {
+	// Now returns the current time. Override it to make timestamps deterministic.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	state fakeState
+}
+
+var _ brood.BroodCaller = (*Fake)(nil)
+var _ brood.BroodCallerContext = (*Fake)(nil)
+var _ brood.ApplicationUserCaller = (*Fake)(nil)
+var _ brood.ApplicationUserCallerContext = (*Fake)(nil)
+var _ brood.TokenRevocationCaller = (*Fake)(nil)
+var _ brood.TokenRevocationCallerContext = (*Fake)(nil)
+var _ brood.PasswordResetCaller = (*Fake)(nil)
+var _ brood.PasswordResetCallerContext = (*Fake)(nil)
+var _ brood.GroupRoleCaller = (*Fake)(nil)
+var _ brood.GroupRoleCallerContext = (*Fake)(nil)
+var _ brood.ApplicationCaller = (*Fake)(nil)
+var _ brood.ApplicationCallerContext = (*Fake)(nil)
+
+func NewFake() *Fake {
+	return &Fake{Now: time.Now, state: newState()}
+}
+
+func apiError(statusCode int, detail string) error {
+	return &brood.APIError{StatusCode: statusCode, Detail: detail, Body: fmt.Sprintf(`{"detail":%q}`, detail)}
+}
+
+func (fake *Fake) newID() string {
+	fake.state.Counter++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", fake.state.Counter)
+}
+
+// Bugout APIs return timestamps with microsecond precision.
+func (fake *Fake) timestamp() time.Time {
+	return fake.Now().UTC().Truncate(time.Microsecond)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the user that the given token belongs to.
+func (fake *Fake) authenticate(token string) (*userRecord, error) {
+	userToken, exists := fake.state.Tokens[token]
+	if !exists || !userToken.Active {
+		return nil, apiError(http.StatusUnauthorized, "Access token not found")
+	}
+	user, exists := fake.state.Users[userToken.UserId]
+	if !exists {
+		return nil, apiError(http.StatusUnauthorized, "Access token not found")
+	}
+	return user, nil
+}
+
+// Usernames are only unique within an application, so users of applications are only found if
+// applicationID is set.
+func (fake *Fake) findUserByUsername(username, applicationID string) (*userRecord, bool) {
+	for _, user := range fake.state.Users {
+		if user.User.Username == username && user.User.ApplicationId == applicationID {
+			return user, true
+		}
+	}
+	return nil, false
+}
+
+// Checks that the application exists and, if an application token is given, that it belongs to a
+// member of the group which owns the application.
+func (fake *Fake) requireApplication(applicationID, applicationToken string) error {
+	application, exists := fake.state.Applications[applicationID]
+	if !exists {
+		return apiError(http.StatusNotFound, "Application not found")
+	}
+	if applicationToken == "" {
+		return nil
+	}
+	user, err := fake.authenticate(applicationToken)
+	if err != nil {
+		return err
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoles...); err != nil {
+		return apiError(http.StatusForbidden, "Insufficient permissions")
+	}
+	return nil
+}
+
+func (fake *Fake) userGroupIDs(userID string) []string {
+	groupIDs := []string{}
+	for groupID, group := range fake.state.Groups {
+		if _, isMember := group.Members[userID]; isMember {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	sort.Strings(groupIDs)
+	return groupIDs
+}
+
+func (fake *Fake) membership(groupID, userID string) brood.UserGroup {
+	group := fake.state.Groups[groupID]
+	return brood.UserGroup{
+		GroupID:   groupID,
+		GroupName: group.Group.Name,
+		UserId:    userID,
+		UserType:  string(group.Members[userID]),
+	}
+}
+
+func (fake *Fake) requireGroupRole(groupID, userID string, roles ...brood.GroupRole) (*groupRecord, error) {
+	group, exists := fake.state.Groups[groupID]
+	if !exists {
+		return nil, apiError(http.StatusNotFound, "Group not found")
+	}
+	role, isMember := group.Members[userID]
+	if !isMember {
+		return nil, apiError(http.StatusNotFound, "Group not found")
+	}
+	for _, allowedRole := range roles {
+		if role == allowedRole {
+			return group, nil
+		}
+	}
+	return nil, apiError(http.StatusForbidden, "Insufficient permissions")
+}
+
+// Returns true if the user (directly or through one of their groups) holds the permission on
+// the resource.
+func (fake *Fake) hasResourcePermission(resource *resourceRecord, userID, permission string) bool {
+	holderIDs := append([]string{userID}, fake.userGroupIDs(userID)...)
+	for _, holderID := range holderIDs {
+		holder, exists := resource.Holders[holderID]
+		if exists && (contains(holder.Permissions, permission) || contains(holder.Permissions, "admin")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fake *Fake) requireResourcePermission(resourceID, userID, permission string) (*resourceRecord, error) {
+	resource, exists := fake.state.Resources[resourceID]
+	if !exists {
+		return nil, apiError(http.StatusNotFound, "Resource not found")
+	}
+	if !fake.hasResourcePermission(resource, userID, permission) {
+		return nil, apiError(http.StatusNotFound, "Resource not found")
+	}
+	return resource, nil
+}
+
+func (fake *Fake) resourceHolders(resource *resourceRecord) brood.ResourceHolders {
+	holderIDs := make([]string, 0, len(resource.Holders))
+	for holderID := range resource.Holders {
+		holderIDs = append(holderIDs, holderID)
+	}
+	sort.Strings(holderIDs)
+
+	holders := brood.ResourceHolders{ResourceId: resource.Resource.Id, Holders: []brood.ResourceHolder{}}
+	for _, holderID := range holderIDs {
+		holders.Holders = append(holders.Holders, resource.Holders[holderID])
+	}
+	return holders
+}
+
+func (fake *Fake) issueToken(userID string) string {
+	token := fake.newID()
+	now := fake.timestamp()
+	fake.state.Tokens[token] = &brood.UserToken{
+		Id:        token,
+		UserId:    userID,
+		TokenType: "bugout",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Active:    true,
+	}
+	return token
+}
+
+func (fake *Fake) createUser(username, email, password, applicationID string) (brood.User, error) {
+	if username == "" || email == "" || password == "" {
+		return brood.User{}, apiError(http.StatusBadRequest, "Username, email and password are required")
+	}
+	normalizedEmail := strings.ToLower(email)
+	for _, user := range fake.state.Users {
+		if user.User.ApplicationId != applicationID {
+			continue
+		}
+		if user.User.Username == username || user.User.NormalizedEmail == normalizedEmail {
+			return brood.User{}, apiError(http.StatusConflict, "User with that username or email already exists")
+		}
+	}
+
+	now := fake.timestamp()
+	user := brood.User{
+		Id:              fake.newID(),
+		Username:        username,
+		Email:           email,
+		NormalizedEmail: normalizedEmail,
+		ApplicationId:   applicationID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	fake.state.Users[user.Id] = &userRecord{User: user, Password: password, VerificationCode: fake.newID()[24:]}
+	return user, nil
+}
+
+// Seeding and inspection
+
+// SeedUser creates a user and returns it along with an access token for it.
+func (fake *Fake) SeedUser(username, email, password string) (brood.User, string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.createUser(username, email, password, "")
+	if err != nil {
+		return user, "", err
+	}
+	return user, fake.issueToken(user.Id), nil
+}
+
+// SeedToken issues a new access token for the user with the given ID.
+func (fake *Fake) SeedToken(userID string) (string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if _, exists := fake.state.Users[userID]; !exists {
+		return "", apiError(http.StatusNotFound, "User not found")
+	}
+	return fake.issueToken(userID), nil
+}
+
+// SeedGroup creates a group owned by the user with the given ID.
+func (fake *Fake) SeedGroup(ownerID, name string) (brood.Group, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if _, exists := fake.state.Users[ownerID]; !exists {
+		return brood.Group{}, apiError(http.StatusNotFound, "User not found")
+	}
+	group := brood.Group{Id: fake.newID(), Name: name}
+	fake.state.Groups[group.Id] = &groupRecord{Group: group, Members: map[string]brood.GroupRole{ownerID: brood.GroupRoleOwner}}
+	return group, nil
+}
+
+// VerificationCode returns the code which VerifyUser accepts for the user with the given ID.
+func (fake *Fake) VerificationCode(userID string) string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, exists := fake.state.Users[userID]
+	if !exists {
+		return ""
+	}
+	return user.VerificationCode
+}
+
+// PasswordResetID returns the reset ID which ConfirmPasswordReset accepts for the user with the
+// given ID, or an empty string if no password reset was requested for them. Brood sends this ID by
+// email.
+func (fake *Fake) PasswordResetID(userID string) string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, exists := fake.state.Users[userID]
+	if !exists {
+		return ""
+	}
+	return user.PasswordResetID
+}
+
+// SaveState writes the complete state of the fake to w as JSON.
+func (fake *Fake) SaveState(w io.Writer) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(fake.state)
+}
+
+// LoadState replaces the state of the fake with state that was written by SaveState.
+func (fake *Fake) LoadState(r io.Reader) error {
+	state := newState()
+	decodeErr := json.NewDecoder(r).Decode(&state)
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.state = state
+	return nil
+}
+
+// Users returns all users, sorted by ID.
+func (fake *Fake) Users() []brood.User {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	users := []brood.User{}
+	for _, user := range fake.state.Users {
+		users = append(users, user.User)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users
+}
+
+// Groups returns all groups, sorted by ID.
+func (fake *Fake) Groups() []brood.Group {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	groups := []brood.Group{}
+	for _, group := range fake.state.Groups {
+		groups = append(groups, group.Group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+	return groups
+}
+
+// GroupMembers returns a map from user IDs to roles for the members of a group.
+func (fake *Fake) GroupMembers(groupID string) map[string]brood.GroupRole {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	members := map[string]brood.GroupRole{}
+	if group, exists := fake.state.Groups[groupID]; exists {
+		for userID, role := range group.Members {
+			members[userID] = role
+		}
+	}
+	return members
+}
+
+// Resources returns all resources, sorted by ID.
+func (fake *Fake) Resources() []brood.Resource {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	resources := []brood.Resource{}
+	for _, resource := range fake.state.Resources {
+		resources = append(resources, resource.Resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Id < resources[j].Id })
+	return resources
+}
+
+// API
+
+func (fake *Fake) Ping() (string, error) {
+	return `{"status":"ok"}`, nil
+}
+
+func (fake *Fake) Version() (string, error) {
+	return `{"version":"broodtest"}`, nil
+}
+
+func (fake *Fake) Auth(token string) (brood.AuthUser, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.AuthUser{}, err
+	}
+
+	authUser := brood.AuthUser{
+		UserId:          user.User.Id,
+		Username:        user.User.Username,
+		Email:           user.User.Email,
+		NormalizedEmail: user.User.NormalizedEmail,
+		Verified:        user.User.Verified,
+		Autogenerated:   user.User.Autogenerated,
+		ApplicationId:   user.User.ApplicationId,
+		CreatedAt:       user.User.CreatedAt,
+		UpdatedAt:       user.User.UpdatedAt,
+		Groups:          []brood.AuthUserGroup{},
+	}
+	for _, groupID := range fake.userGroupIDs(user.User.Id) {
+		group := fake.state.Groups[groupID]
+		authUser.Groups = append(authUser.Groups, brood.AuthUserGroup{
+			GroupId:   groupID,
+			UserId:    user.User.Id,
+			UserType:  string(group.Members[user.User.Id]),
+			GroupName: group.Group.Name,
+		})
+	}
+	return authUser, nil
+}
+
+func (fake *Fake) CreateUser(username, email, password string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.createUser(username, email, password, "")
+}
+
+func (fake *Fake) GenerateToken(username, password string) (string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, exists := fake.findUserByUsername(username, "")
+	if !exists || user.Password != password {
+		return "", apiError(http.StatusForbidden, "Incorrect username or password")
+	}
+	return fake.issueToken(user.User.Id), nil
+}
+
+func (fake *Fake) CreateApplicationUser(applicationId, applicationToken, username, email, password string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if err := fake.requireApplication(applicationId, applicationToken); err != nil {
+		return brood.User{}, err
+	}
+	return fake.createUser(username, email, password, applicationId)
+}
+
+func (fake *Fake) GenerateApplicationToken(applicationId, applicationToken, username, password string) (string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if err := fake.requireApplication(applicationId, applicationToken); err != nil {
+		return "", err
+	}
+	user, exists := fake.findUserByUsername(username, applicationId)
+	if !exists || user.Password != password {
+		return "", apiError(http.StatusForbidden, "Incorrect username or password")
+	}
+	return fake.issueToken(user.User.Id), nil
+}
+
+func (fake *Fake) AnnotateToken(token, tokenType, note string) (string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	userToken, exists := fake.state.Tokens[token]
+	if !exists || !userToken.Active {
+		return "", apiError(http.StatusNotFound, "Access token not found")
+	}
+	if tokenType != "" {
+		userToken.TokenType = tokenType
+	}
+	userToken.Note = note
+	userToken.UpdatedAt = fake.timestamp()
+	return token, nil
+}
+
+func (fake *Fake) ListTokens(token string) (brood.UserTokensList, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserTokensList{}, err
+	}
+
+	tokens := brood.UserTokensList{UserId: user.User.Id, Username: user.User.Username, Tokens: []brood.UserToken{}}
+	for _, userToken := range fake.state.Tokens {
+		if userToken.UserId == user.User.Id {
+			tokens.Tokens = append(tokens.Tokens, *userToken)
+		}
+	}
+	sort.Slice(tokens.Tokens, func(i, j int) bool { return tokens.Tokens[i].Id < tokens.Tokens[j].Id })
+	return tokens, nil
+}
+
+// RevokeToken removes the target token, so that it no longer shows up in ListTokens.
+func (fake *Fake) RevokeToken(token, targetToken string) (brood.UserToken, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserToken{}, err
+	}
+	userToken, exists := fake.state.Tokens[targetToken]
+	if !exists || userToken.UserId != user.User.Id {
+		return brood.UserToken{}, apiError(http.StatusNotFound, "Access token not found")
+	}
+	delete(fake.state.Tokens, targetToken)
+
+	revoked := *userToken
+	revoked.Active = false
+	revoked.UpdatedAt = fake.timestamp()
+	return revoked, nil
+}
+
+func (fake *Fake) FindUser(token string, queryParameters map[string]string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.findUser(token, queryParameters)
+}
+
+func (fake *Fake) FindApplicationUser(token, applicationId string, queryParameters map[string]string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	applicationParameters := map[string]string{"application_id": applicationId}
+	for k, v := range queryParameters {
+		if k != "application_id" {
+			applicationParameters[k] = v
+		}
+	}
+	return fake.findUser(token, applicationParameters)
+}
+
+func (fake *Fake) findUser(token string, queryParameters map[string]string) (brood.User, error) {
+	if _, err := fake.authenticate(token); err != nil {
+		return brood.User{}, err
+	}
+
+	for _, user := range fake.state.Users {
+		if userID, ok := queryParameters["user_id"]; ok && user.User.Id != userID {
+			continue
+		}
+		if username, ok := queryParameters["username"]; ok && user.User.Username != username {
+			continue
+		}
+		if email, ok := queryParameters["email"]; ok && user.User.NormalizedEmail != strings.ToLower(email) {
+			continue
+		}
+		if applicationID, ok := queryParameters["application_id"]; ok && user.User.ApplicationId != applicationID {
+			continue
+		}
+		return user.User, nil
+	}
+	return brood.User{}, apiError(http.StatusNotFound, "User not found")
+}
+
+func (fake *Fake) GetUser(token string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.User{}, err
+	}
+	return user.User, nil
+}
+
+func (fake *Fake) VerifyUser(token, code string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.User{}, err
+	}
+	if code != user.VerificationCode {
+		return brood.User{}, apiError(http.StatusBadRequest, "Incorrect verification code")
+	}
+	user.User.Verified = true
+	user.User.UpdatedAt = fake.timestamp()
+	return user.User, nil
+}
+
+func (fake *Fake) ChangePassword(token, currentPassword, newPassword string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.User{}, err
+	}
+	if user.Password != currentPassword {
+		return brood.User{}, apiError(http.StatusBadRequest, "Incorrect password")
+	}
+	user.Password = newPassword
+	user.User.UpdatedAt = fake.timestamp()
+	return user.User, nil
+}
+
+// RequestPasswordReset responds with an empty JSON object. Use PasswordResetID to get the reset ID
+// which Brood would have sent by email.
+func (fake *Fake) RequestPasswordReset(email string) (string, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	normalizedEmail := strings.ToLower(email)
+	for _, user := range fake.state.Users {
+		if user.User.ApplicationId == "" && user.User.NormalizedEmail == normalizedEmail {
+			user.PasswordResetID = fake.newID()
+			return "{}", nil
+		}
+	}
+	return "", apiError(http.StatusNotFound, "User not found")
+}
+
+func (fake *Fake) ConfirmPasswordReset(resetID, newPassword string) (brood.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if newPassword == "" {
+		return brood.User{}, apiError(http.StatusBadRequest, "New password is required")
+	}
+	for _, user := range fake.state.Users {
+		if resetID != "" && user.PasswordResetID == resetID {
+			user.Password = newPassword
+			user.PasswordResetID = ""
+			user.User.UpdatedAt = fake.timestamp()
+			return user.User, nil
+		}
+	}
+	return brood.User{}, apiError(http.StatusNotFound, "Password reset not found")
+}
+
+func (fake *Fake) CreateGroup(token, name string) (brood.Group, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	group := brood.Group{Id: fake.newID(), Name: name}
+	fake.state.Groups[group.Id] = &groupRecord{Group: group, Members: map[string]brood.GroupRole{user.User.Id: brood.GroupRoleOwner}}
+	return group, nil
+}
+
+func (fake *Fake) GetUserGroups(token string) (brood.UserGroupsList, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserGroupsList{}, err
+	}
+	groups := brood.UserGroupsList{Groups: []brood.UserGroup{}}
+	for _, groupID := range fake.userGroupIDs(user.User.Id) {
+		groups.Groups = append(groups.Groups, fake.membership(groupID, user.User.Id))
+	}
+	return groups, nil
+}
+
+func (fake *Fake) GetGroup(token, groupID string) (brood.Group, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoles...)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	return group.Group, nil
+}
+
+func (fake *Fake) ListGroupMembers(token, groupID string) (brood.GroupUsersList, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.GroupUsersList{}, err
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoles...)
+	if err != nil {
+		return brood.GroupUsersList{}, err
+	}
+	members := brood.GroupUsersList{Users: []brood.GroupUser{}}
+	for userID, role := range group.Members {
+		members.Users = append(members.Users, brood.GroupUser{
+			UserId:   userID,
+			Username: fake.state.Users[userID].User.Username,
+			UserType: role,
+		})
+	}
+	sort.Slice(members.Users, func(i, j int) bool { return members.Users[i].Username < members.Users[j].Username })
+	return members, nil
+}
+
+func (fake *Fake) DeleteGroup(token, groupID string) (brood.Group, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoleOwner)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	delete(fake.state.Groups, groupID)
+	for _, resource := range fake.state.Resources {
+		delete(resource.Holders, groupID)
+	}
+	return group.Group, nil
+}
+
+func (fake *Fake) RenameGroup(token, groupID, name string) (brood.Group, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoleOwner)
+	if err != nil {
+		return brood.Group{}, err
+	}
+	group.Group.Name = name
+	return group.Group, nil
+}
+
+func (fake *Fake) AddUserToGroup(token, groupID, username, role string) (brood.UserGroup, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	if !brood.GroupRole(role).Valid() {
+		return brood.UserGroup{}, apiError(http.StatusBadRequest, fmt.Sprintf("Invalid role: %s", role))
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoleOwner)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	member, exists := fake.findUserByUsername(username, "")
+	if !exists {
+		return brood.UserGroup{}, apiError(http.StatusNotFound, "User not found")
+	}
+	group.Members[member.User.Id] = brood.GroupRole(role)
+	return fake.membership(groupID, member.User.Id), nil
+}
+
+func (fake *Fake) SetUserGroupRole(token, groupID, username string, role brood.GroupRole) (brood.UserGroup, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	if !role.Valid() {
+		return brood.UserGroup{}, apiError(http.StatusBadRequest, fmt.Sprintf("Invalid role: %s", role))
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, brood.GroupRoleOwner)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	member, exists := fake.findUserByUsername(username, "")
+	if !exists {
+		return brood.UserGroup{}, apiError(http.StatusNotFound, "User not found")
+	}
+	if _, isMember := group.Members[member.User.Id]; !isMember {
+		return brood.UserGroup{}, apiError(http.StatusNotFound, "User is not a member of the group")
+	}
+	group.Members[member.User.Id] = role
+	return fake.membership(groupID, member.User.Id), nil
+}
+
+func (fake *Fake) RemoveUserFromGroup(token, groupID, username string) (brood.UserGroup, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	member, exists := fake.findUserByUsername(username, "")
+	if !exists {
+		return brood.UserGroup{}, apiError(http.StatusNotFound, "User not found")
+	}
+	// Users may always remove themselves from a group.
+	requiredRoles := []brood.GroupRole{brood.GroupRoleOwner}
+	if member.User.Id == user.User.Id {
+		requiredRoles = brood.GroupRoles
+	}
+	group, err := fake.requireGroupRole(groupID, user.User.Id, requiredRoles...)
+	if err != nil {
+		return brood.UserGroup{}, err
+	}
+	if _, isMember := group.Members[member.User.Id]; !isMember {
+		return brood.UserGroup{}, apiError(http.StatusNotFound, "User is not a member of the group")
+	}
+	membership := fake.membership(groupID, member.User.Id)
+	delete(group.Members, member.User.Id)
+	return membership, nil
+}
+
+func (fake *Fake) CreateResource(token, applicationId string, resourceData interface{}) (brood.Resource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.Resource{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoles...); err != nil {
+		return brood.Resource{}, err
+	}
+
+	resource := brood.Resource{Id: fake.newID(), ApplicationId: applicationId, ResourceData: resourceData}
+	fake.state.Resources[resource.Id] = &resourceRecord{
+		Resource: resource,
+		Holders: map[string]brood.ResourceHolder{
+			user.User.Id: {Id: user.User.Id, HolderType: "user", Permissions: append([]string{}, validResourcePermissions...)},
+		},
+	}
+	return resource, nil
+}
+
+func (fake *Fake) UpdateResource(token, resourceId string, update interface{}, dropKeys []string) (brood.Resource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "update")
+	if err != nil {
+		return brood.Resource{}, err
+	}
+
+	data, isMap := resource.Resource.ResourceData.(map[string]interface{})
+	if !isMap {
+		data = map[string]interface{}{}
+	}
+	updated := map[string]interface{}{}
+	for key, value := range data {
+		updated[key] = value
+	}
+	if updateMap, ok := update.(map[string]interface{}); ok {
+		for key, value := range updateMap {
+			updated[key] = value
+		}
+	}
+	for _, key := range dropKeys {
+		delete(updated, key)
+	}
+	resource.Resource.ResourceData = updated
+	return resource.Resource, nil
+}
+
+func (fake *Fake) GetResource(token, resourceId string) (brood.Resource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "read")
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	return resource.Resource, nil
+}
+
+func (fake *Fake) GetResources(token, applicationId string, queryParameters map[string]string) (brood.Resources, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Resources{}, err
+	}
+
+	resources := brood.Resources{Resources: []brood.Resource{}}
+	for _, resource := range fake.state.Resources {
+		if resource.Resource.ApplicationId != applicationId || !fake.hasResourcePermission(resource, user.User.Id, "read") {
+			continue
+		}
+		// Query parameters filter on top-level keys of the resource data.
+		data, _ := resource.Resource.ResourceData.(map[string]interface{})
+		matches := true
+		for key, value := range queryParameters {
+			if fmt.Sprintf("%v", data[key]) != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			resources.Resources = append(resources.Resources, resource.Resource)
+		}
+	}
+	sort.Slice(resources.Resources, func(i, j int) bool { return resources.Resources[i].Id < resources.Resources[j].Id })
+	return resources, nil
+}
+
+func (fake *Fake) DeleteResource(token, resourceId string) (brood.Resource, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "delete")
+	if err != nil {
+		return brood.Resource{}, err
+	}
+	delete(fake.state.Resources, resourceId)
+	return resource.Resource, nil
+}
+
+func (fake *Fake) GetResourceHolders(token, resourceId string) (brood.ResourceHolders, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "read")
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	return fake.resourceHolders(resource), nil
+}
+
+func (fake *Fake) validateHolder(resourceHolder brood.ResourceHolder) error {
+	switch resourceHolder.HolderType {
+	case "user":
+		if _, exists := fake.state.Users[resourceHolder.Id]; !exists {
+			return apiError(http.StatusNotFound, "User not found")
+		}
+	case "group":
+		if _, exists := fake.state.Groups[resourceHolder.Id]; !exists {
+			return apiError(http.StatusNotFound, "Group not found")
+		}
+	default:
+		return apiError(http.StatusBadRequest, fmt.Sprintf("Invalid holder type: %s", resourceHolder.HolderType))
+	}
+	for _, permission := range resourceHolder.Permissions {
+		if !contains(validResourcePermissions, permission) {
+			return apiError(http.StatusBadRequest, fmt.Sprintf("Invalid permission: %s", permission))
+		}
+	}
+	return nil
+}
+
+func (fake *Fake) AddResourceHolderPermissions(token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "admin")
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	if err := fake.validateHolder(resourceHolder); err != nil {
+		return brood.ResourceHolders{}, err
+	}
+
+	holder, exists := resource.Holders[resourceHolder.Id]
+	if !exists {
+		holder = brood.ResourceHolder{Id: resourceHolder.Id, HolderType: resourceHolder.HolderType, Permissions: []string{}}
+	}
+	for _, permission := range resourceHolder.Permissions {
+		if !contains(holder.Permissions, permission) {
+			holder.Permissions = append(holder.Permissions, permission)
+		}
+	}
+	resource.Holders[resourceHolder.Id] = holder
+	return fake.resourceHolders(resource), nil
+}
+
+func (fake *Fake) DeleteResourceHolderPermissions(token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	resource, err := fake.requireResourcePermission(resourceId, user.User.Id, "admin")
+	if err != nil {
+		return brood.ResourceHolders{}, err
+	}
+	holder, exists := resource.Holders[resourceHolder.Id]
+	if !exists {
+		return brood.ResourceHolders{}, apiError(http.StatusNotFound, "Holder not found")
+	}
+
+	remaining := []string{}
+	for _, permission := range holder.Permissions {
+		if !contains(resourceHolder.Permissions, permission) {
+			remaining = append(remaining, permission)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(resource.Holders, resourceHolder.Id)
+	} else {
+		holder.Permissions = remaining
+		resource.Holders[resourceHolder.Id] = holder
+	}
+	return fake.resourceHolders(resource), nil
+}
+
+func (fake *Fake) CreateApplication(token, groupId, name, description string) (brood.Application, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Application{}, err
+	}
+	if _, err := fake.requireGroupRole(groupId, user.User.Id, brood.GroupRoleOwner); err != nil {
+		return brood.Application{}, err
+	}
+	application := &brood.Application{Id: fake.newID(), GroupId: groupId, Name: name, Description: description}
+	fake.state.Applications[application.Id] = application
+	return *application, nil
+}
+
+func (fake *Fake) GetApplication(token, applicationId string) (brood.Application, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Application{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.Application{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoles...); err != nil {
+		return brood.Application{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	return *application, nil
+}
+
+func (fake *Fake) ListApplications(token, groupId string) (brood.ApplicationsList, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.ApplicationsList{}, err
+	}
+	groupIDs := fake.userGroupIDs(user.User.Id)
+
+	applications := brood.ApplicationsList{Applications: []brood.Application{}}
+	for _, application := range fake.state.Applications {
+		if !contains(groupIDs, application.GroupId) || (groupId != "" && application.GroupId != groupId) {
+			continue
+		}
+		applications.Applications = append(applications.Applications, *application)
+	}
+	sort.Slice(applications.Applications, func(i, j int) bool {
+		return applications.Applications[i].Id < applications.Applications[j].Id
+	})
+	return applications, nil
+}
+
+func (fake *Fake) DeleteApplication(token, applicationId string) (brood.Application, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Application{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.Application{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoleOwner); err != nil {
+		return brood.Application{}, err
+	}
+	delete(fake.state.Applications, applicationId)
+	for resourceID, resource := range fake.state.Resources {
+		if resource.Resource.ApplicationId == applicationId {
+			delete(fake.state.Resources, resourceID)
+		}
+	}
+	return *application, nil
+}
+
+func (fake *Fake) UpdateApplication(token, applicationId, name, description string) (brood.Application, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Application{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.Application{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoleOwner); err != nil {
+		return brood.Application{}, err
+	}
+	if name != "" {
+		application.Name = name
+	}
+	if description != "" {
+		application.Description = description
+	}
+	return *application, nil
+}
+
+func (fake *Fake) TransferApplication(token, applicationId, groupId string) (brood.Application, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.Application{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.Application{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoleOwner); err != nil {
+		return brood.Application{}, err
+	}
+	if _, err := fake.requireGroupRole(groupId, user.User.Id, brood.GroupRoleOwner); err != nil {
+		return brood.Application{}, err
+	}
+	application.GroupId = groupId
+	return *application, nil
+}
+
+func (fake *Fake) ListApplicationUsers(token, applicationId string) (brood.ApplicationUsersList, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user, err := fake.authenticate(token)
+	if err != nil {
+		return brood.ApplicationUsersList{}, err
+	}
+	application, exists := fake.state.Applications[applicationId]
+	if !exists {
+		return brood.ApplicationUsersList{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	if _, err := fake.requireGroupRole(application.GroupId, user.User.Id, brood.GroupRoles...); err != nil {
+		return brood.ApplicationUsersList{}, apiError(http.StatusNotFound, "Application not found")
+	}
+	users := brood.ApplicationUsersList{Users: []brood.User{}}
+	for _, record := range fake.state.Users {
+		if record.User.ApplicationId == applicationId {
+			users.Users = append(users.Users, record.User)
+		}
+	}
+	sort.Slice(users.Users, func(i, j int) bool { return users.Users[i].Username < users.Users[j].Username })
+	return users, nil
+}
@@ -1,14 +1,15 @@
 package brood
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bugout-dev/bugout-go/pkg/config"
 	"github.com/bugout-dev/bugout-go/pkg/utils"
 )
 
@@ -47,6 +48,103 @@ type BroodCaller interface {
 	DeleteApplication(token, applicationId string) (Application, error)
 }
 
+// BroodCallerContext mirrors BroodCaller, but every method accepts a context.Context which is
+// attached to the underlying HTTP requests. Use it to propagate cancellation and deadlines.
+type BroodCallerContext interface {
+	PingContext(ctx context.Context) (string, error)
+	VersionContext(ctx context.Context) (string, error)
+	AuthContext(ctx context.Context, token string) (AuthUser, error)
+	CreateUserContext(ctx context.Context, username, email, password string) (User, error)
+	GenerateTokenContext(ctx context.Context, username, password string) (string, error)
+	AnnotateTokenContext(ctx context.Context, token, tokenType, note string) (string, error)
+	ListTokensContext(ctx context.Context, token string) (UserTokensList, error)
+	FindUserContext(ctx context.Context, token string, queryParameters map[string]string) (User, error)
+	GetUserContext(ctx context.Context, token string) (User, error)
+	VerifyUserContext(ctx context.Context, token, code string) (User, error)
+	ChangePasswordContext(ctx context.Context, token, currentPassword, newPassword string) (User, error)
+	CreateGroupContext(ctx context.Context, token, name string) (Group, error)
+	GetUserGroupsContext(ctx context.Context, token string) (UserGroupsList, error)
+	DeleteGroupContext(ctx context.Context, token, groupID string) (Group, error)
+	RenameGroupContext(ctx context.Context, token, groupID, name string) (Group, error)
+	AddUserToGroupContext(ctx context.Context, token, groupID, username, role string) (UserGroup, error)
+	RemoveUserFromGroupContext(ctx context.Context, token, groupID, username string) (UserGroup, error)
+	CreateResourceContext(ctx context.Context, token, applicationId string, resourceData interface{}) (Resource, error)
+	UpdateResourceContext(ctx context.Context, token, resourceId string, update interface{}, dropKeys []string) (Resource, error)
+	GetResourceContext(ctx context.Context, token, resourceId string) (Resource, error)
+	GetResourcesContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (Resources, error)
+	DeleteResourceContext(ctx context.Context, token, resourceId string) (Resource, error)
+	GetResourceHoldersContext(ctx context.Context, token, resourceId string) (ResourceHolders, error)
+	AddResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error)
+	DeleteResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error)
+	CreateApplicationContext(ctx context.Context, token, groupId, name, description string) (Application, error)
+	GetApplicationContext(ctx context.Context, token, applicationId string) (Application, error)
+	ListApplicationsContext(ctx context.Context, token, groupId string) (ApplicationsList, error)
+	DeleteApplicationContext(ctx context.Context, token, applicationId string) (Application, error)
+}
+
+// The interfaces below hold the methods of BroodClient which are not part of BroodCaller. Keeping
+// them out of BroodCaller means that existing implementations of BroodCaller remain valid.
+// BroodClient implements all of them; use a type assertion to check whether another caller does.
+
+// ApplicationUserCaller registers and authenticates the users of applications.
+type ApplicationUserCaller interface {
+	CreateApplicationUser(applicationId, applicationToken, username, email, password string) (User, error)
+	GenerateApplicationToken(applicationId, applicationToken, username, password string) (string, error)
+	FindApplicationUser(token, applicationId string, queryParameters map[string]string) (User, error)
+}
+
+type ApplicationUserCallerContext interface {
+	CreateApplicationUserContext(ctx context.Context, applicationId, applicationToken, username, email, password string) (User, error)
+	GenerateApplicationTokenContext(ctx context.Context, applicationId, applicationToken, username, password string) (string, error)
+	FindApplicationUserContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (User, error)
+}
+
+// TokenRevocationCaller revokes access tokens.
+type TokenRevocationCaller interface {
+	RevokeToken(token, targetToken string) (UserToken, error)
+}
+
+type TokenRevocationCallerContext interface {
+	RevokeTokenContext(ctx context.Context, token, targetToken string) (UserToken, error)
+}
+
+// PasswordResetCaller resets forgotten passwords.
+type PasswordResetCaller interface {
+	RequestPasswordReset(email string) (string, error)
+	ConfirmPasswordReset(resetID, newPassword string) (User, error)
+}
+
+type PasswordResetCallerContext interface {
+	RequestPasswordResetContext(ctx context.Context, email string) (string, error)
+	ConfirmPasswordResetContext(ctx context.Context, resetID, newPassword string) (User, error)
+}
+
+// GroupRoleCaller inspects groups and changes the roles of their members.
+type GroupRoleCaller interface {
+	GetGroup(token, groupID string) (Group, error)
+	ListGroupMembers(token, groupID string) (GroupUsersList, error)
+	SetUserGroupRole(token, groupID, username string, role GroupRole) (UserGroup, error)
+}
+
+type GroupRoleCallerContext interface {
+	GetGroupContext(ctx context.Context, token, groupID string) (Group, error)
+	ListGroupMembersContext(ctx context.Context, token, groupID string) (GroupUsersList, error)
+	SetUserGroupRoleContext(ctx context.Context, token, groupID, username string, role GroupRole) (UserGroup, error)
+}
+
+// ApplicationCaller updates, transfers and lists the users of applications.
+type ApplicationCaller interface {
+	UpdateApplication(token, applicationId, name, description string) (Application, error)
+	TransferApplication(token, applicationId, groupId string) (Application, error)
+	ListApplicationUsers(token, applicationId string) (ApplicationUsersList, error)
+}
+
+type ApplicationCallerContext interface {
+	UpdateApplicationContext(ctx context.Context, token, applicationId, name, description string) (Application, error)
+	TransferApplicationContext(ctx context.Context, token, applicationId, groupId string) (Application, error)
+	ListApplicationUsersContext(ctx context.Context, token, applicationId string) (ApplicationUsersList, error)
+}
+
 type BroodRoutes struct {
 	Ping                string
 	Version             string
@@ -76,6 +174,7 @@ func RoutesFromURL(broodURL string) BroodRoutes {
 		FindUser:            fmt.Sprintf("%s/user/find", cleanURL),
 		Groups:              fmt.Sprintf("%s/groups", cleanURL),
 		Token:               fmt.Sprintf("%s/token", cleanURL),
+		RevokeToken:         fmt.Sprintf("%s/revoke", cleanURL),
 		ListTokens:          fmt.Sprintf("%s/tokens", cleanURL),
 		ConfirmRegistration: fmt.Sprintf("%s/confirm", cleanURL),
 		ChangePassword:      fmt.Sprintf("%s/profile/password", cleanURL),
@@ -86,15 +185,51 @@ func RoutesFromURL(broodURL string) BroodRoutes {
 	}
 }
 
+var _ BroodCaller = BroodClient{}
+var _ BroodCallerContext = BroodClient{}
+var _ ApplicationUserCaller = BroodClient{}
+var _ ApplicationUserCallerContext = BroodClient{}
+var _ TokenRevocationCaller = BroodClient{}
+var _ TokenRevocationCallerContext = BroodClient{}
+var _ PasswordResetCaller = BroodClient{}
+var _ PasswordResetCallerContext = BroodClient{}
+var _ GroupRoleCaller = BroodClient{}
+var _ GroupRoleCallerContext = BroodClient{}
+var _ ApplicationCaller = BroodClient{}
+var _ ApplicationCallerContext = BroodClient{}
+
 type BroodClient struct {
 	BroodURL   string
 	Routes     BroodRoutes
 	HTTPClient *http.Client
+	Retry      RetryPolicy
+	UserAgent  string
+	// Headers which are added to every request
+	Headers    http.Header
+	Middleware []Middleware
+}
+
+// Sends a request to Brood using the client's HTTPClient, retrying according to its Retry policy.
+// The operation is the name of the calling method, and is made available to middleware.
+func (client BroodClient) do(request *http.Request, operation string) (*http.Response, error) {
+	utils.ApplyDefaultHeaders(request, client.UserAgent, client.Headers)
+	return utils.DoWithMiddleware(request, operation, client.Middleware, func(request *http.Request) (*http.Response, error) {
+		return utils.DoWithRetry(client.HTTPClient, request, client.Retry)
+	})
 }
 
 func (client BroodClient) Ping() (string, error) {
+	return client.PingContext(context.Background())
+}
+
+func (client BroodClient) PingContext(ctx context.Context) (string, error) {
 	pingURL := client.Routes.Ping
-	response, err := client.HTTPClient.Get(pingURL)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", pingURL, nil)
+	if requestErr != nil {
+		return "", requestErr
+	}
+
+	response, err := client.do(request, "Ping")
 	if err != nil {
 		return "", err
 	}
@@ -113,8 +248,17 @@ func (client BroodClient) Ping() (string, error) {
 }
 
 func (client BroodClient) Version() (string, error) {
+	return client.VersionContext(context.Background())
+}
+
+func (client BroodClient) VersionContext(ctx context.Context) (string, error) {
 	versionURL := client.Routes.Version
-	response, err := client.HTTPClient.Get(versionURL)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", versionURL, nil)
+	if requestErr != nil {
+		return "", requestErr
+	}
+
+	response, err := client.do(request, "Version")
 	if err != nil {
 		return "", err
 	}
@@ -133,18 +277,44 @@ func (client BroodClient) Version() (string, error) {
 }
 
 func NewClient(broodURL string, timeout time.Duration) BroodClient {
+	return NewClientWithOptions(broodURL, WithTimeout(timeout))
+}
+
+func NewClientWithOptions(broodURL string, opts ...ClientOption) BroodClient {
 	routes := RoutesFromURL(broodURL)
-	httpClient := http.Client{Timeout: timeout}
-	return BroodClient{BroodURL: broodURL, Routes: routes, HTTPClient: &httpClient}
+	config := utils.NewClientConfig(opts...)
+	return BroodClient{
+		BroodURL:   broodURL,
+		Routes:     routes,
+		HTTPClient: config.BuildHTTPClient(),
+		Retry:      config.Retry,
+		UserAgent:  config.UserAgent,
+		Headers:    config.Headers,
+		Middleware: config.Middleware,
+	}
+}
+
+// ClientFromEnv creates a BroodClient configured by BUGOUT_* environment variables, falling back to
+// the active profile in the bugout configuration file. Options passed to it take precedence over
+// both.
+func ClientFromEnv(opts ...ClientOption) (BroodClient, error) {
+	env, envErr := config.LoadEnv()
+	if envErr != nil {
+		return BroodClient{}, envErr
+	}
+	return ClientFromConfig(env, opts...)
 }
 
-func ClientFromEnv() (BroodClient, error) {
-	broodURL := os.Getenv("BUGOUT_BROOD_URL")
+// ClientFromConfig is like ClientFromEnv, but takes settings from an already loaded configuration
+// profile.
+func ClientFromConfig(env config.Env, opts ...ClientOption) (BroodClient, error) {
+
+	broodURL := env.Getenv("BUGOUT_BROOD_URL")
 	if broodURL == "" {
 		broodURL = BugoutBroodURL
 	}
 
-	broodTimeoutSecondsRaw := os.Getenv("BUGOUT_TIMEOUT_SECONDS")
+	broodTimeoutSecondsRaw := env.Getenv("BUGOUT_TIMEOUT_SECONDS")
 	if broodTimeoutSecondsRaw == "" {
 		broodTimeoutSecondsRaw = "3"
 	}
@@ -154,7 +324,13 @@ func ClientFromEnv() (BroodClient, error) {
 	}
 	broodTimeout := time.Duration(broodTimeoutSeconds) * time.Second
 
-	client := NewClient(broodURL, broodTimeout)
+	retryPolicy, retryErr := utils.RetryPolicyFromConfig(env)
+	if retryErr != nil {
+		return BroodClient{}, retryErr
+	}
+
+	envOpts := []ClientOption{WithTimeout(broodTimeout), WithRetry(retryPolicy)}
+	client := NewClientWithOptions(broodURL, append(envOpts, opts...)...)
 
 	return client, nil
 }
@@ -2,6 +2,9 @@ package brood
 
 import (
 	"encoding/json"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
 )
 
 type AuthUserGroup struct {
@@ -13,29 +16,71 @@ type AuthUserGroup struct {
 }
 
 type AuthUser struct {
-	UserId          string `json:"user_id"`
-	Username        string `json:"username"`
-	Email           string `json:"email"`
-	NormalizedEmail string `json:"normalized_email"`
-	Verified        bool   `json:"verified"`
-	Autogenerated   bool   `json:"autogenerated"`
-	ApplicationId   string `json:"application_id"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
+	UserId          string    `json:"user_id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	NormalizedEmail string    `json:"normalized_email"`
+	Verified        bool      `json:"verified"`
+	Autogenerated   bool      `json:"autogenerated"`
+	ApplicationId   string    `json:"application_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 
 	Groups []AuthUserGroup `json:"groups"`
 }
 
+func (user *AuthUser) UnmarshalJSON(data []byte) error {
+	type Alias AuthUser
+	aux := &struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		*Alias
+	}{
+		Alias: (*Alias)(user),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var parseErr error
+	if user.CreatedAt, parseErr = utils.ParseTimestamp(aux.CreatedAt); parseErr != nil {
+		return parseErr
+	}
+	user.UpdatedAt, parseErr = utils.ParseTimestamp(aux.UpdatedAt)
+	return parseErr
+}
+
 type User struct {
-	Id              string `json:"id"`
-	Username        string `json:"username"`
-	Email           string `json:"email"`
-	NormalizedEmail string `json:"normalized_email"`
-	Verified        bool   `json:"verified"`
-	ApplicationId   string `json:"application_id"`
-	Autogenerated   bool   `json:"autogenerated"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
+	Id              string    `json:"id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	NormalizedEmail string    `json:"normalized_email"`
+	Verified        bool      `json:"verified"`
+	ApplicationId   string    `json:"application_id"`
+	Autogenerated   bool      `json:"autogenerated"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (user *User) UnmarshalJSON(data []byte) error {
+	type Alias User
+	aux := &struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		*Alias
+	}{
+		Alias: (*Alias)(user),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var parseErr error
+	if user.CreatedAt, parseErr = utils.ParseTimestamp(aux.CreatedAt); parseErr != nil {
+		return parseErr
+	}
+	user.UpdatedAt, parseErr = utils.ParseTimestamp(aux.UpdatedAt)
+	return parseErr
 }
 
 type UserGeneratedToken struct {
@@ -44,13 +89,34 @@ type UserGeneratedToken struct {
 }
 
 type UserToken struct {
-	Id        string `json:"id"`
-	TokenType string `json:"token_type"`
-	CreatedAt string `json:"created_at"`
-	UserId    string `json:"user_id"`
-	UpdatedAt string `json:"updated_at"`
-	Note      string `json:"note"`
-	Active    bool   `json:"active"`
+	Id        string    `json:"id"`
+	TokenType string    `json:"token_type"`
+	CreatedAt time.Time `json:"created_at"`
+	UserId    string    `json:"user_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Note      string    `json:"note"`
+	Active    bool      `json:"active"`
+}
+
+func (token *UserToken) UnmarshalJSON(data []byte) error {
+	type Alias UserToken
+	aux := &struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		*Alias
+	}{
+		Alias: (*Alias)(token),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var parseErr error
+	if token.CreatedAt, parseErr = utils.ParseTimestamp(aux.CreatedAt); parseErr != nil {
+		return parseErr
+	}
+	token.UpdatedAt, parseErr = utils.ParseTimestamp(aux.UpdatedAt)
+	return parseErr
 }
 
 type UserTokensList struct {
@@ -59,6 +125,27 @@ type UserTokensList struct {
 	Tokens   []UserToken `json:"token"`
 }
 
+// GroupRole is the role of a user in a group. Owners can manage the group and its members.
+type GroupRole string
+
+const (
+	GroupRoleOwner  GroupRole = "owner"
+	GroupRoleMember GroupRole = "member"
+)
+
+// Valid roles for members of a group
+var GroupRoles = []GroupRole{GroupRoleOwner, GroupRoleMember}
+
+// Valid reports whether role is one of GroupRoles.
+func (role GroupRole) Valid() bool {
+	for _, validRole := range GroupRoles {
+		if role == validRole {
+			return true
+		}
+	}
+	return false
+}
+
 type Group struct {
 	Id   string `json:"id"`
 	Name string `json:"group_name"`
@@ -76,6 +163,16 @@ type UserGroupsList struct {
 	Groups []UserGroup `json:"groups"`
 }
 
+type GroupUser struct {
+	UserId   string    `json:"user_id"`
+	Username string    `json:"username"`
+	UserType GroupRole `json:"user_type"`
+}
+
+type GroupUsersList struct {
+	Users []GroupUser `json:"users"`
+}
+
 // Applications
 
 type Application struct {
@@ -89,6 +186,10 @@ type ApplicationsList struct {
 	Applications []Application `json:"applications"`
 }
 
+type ApplicationUsersList struct {
+	Users []User `json:"users"`
+}
+
 // Resources
 type Resource struct {
 	Id            string      `json:"id"`
@@ -0,0 +1,248 @@
+package brood_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+)
+
+// Decodes payload into value, encodes value again and checks that the result holds the same JSON
+// as expected (or as payload itself, if expected is empty).
+func checkRoundTrip(t *testing.T, payload, expected string, value interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(payload), value); err != nil {
+		t.Fatalf("Could not decode payload: %v", err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Could not encode %#v: %v", value, err)
+	}
+
+	if expected == "" {
+		expected = payload
+	}
+	var expectedJSON, actualJSON interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedJSON); err != nil {
+		t.Fatalf("Invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &actualJSON); err != nil {
+		t.Fatalf("Invalid encoded JSON: %v", err)
+	}
+	if !reflect.DeepEqual(actualJSON, expectedJSON) {
+		t.Errorf("Round trip changed the payload\nexpected: %s\nencoded:  %s", expected, encoded)
+	}
+}
+
+func TestDataRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload string
+		// JSON which the payload is expected to be encoded as, if it differs from the payload
+		expected string
+		value    func() interface{}
+	}{
+		{
+			name: "authenticated user",
+			payload: `{
+				"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"username": "neeraj",
+				"email": "Neeraj@example.com",
+				"normalized_email": "neeraj@example.com",
+				"verified": true,
+				"autogenerated": false,
+				"application_id": "5d1c0b8e-3a7f-4b6e-9c2d-1e0f8a7b6c5d",
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-11T09:00:00.5Z",
+				"groups": [{
+					"group_id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10",
+					"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+					"user_type": "owner",
+					"autogenerated": true,
+					"group_name": "neeraj"
+				}]
+			}`,
+			value: func() interface{} { return &brood.AuthUser{} },
+		},
+		{
+			name: "user with space-separated timestamps without time zone",
+			payload: `{
+				"id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"username": "neeraj",
+				"email": "neeraj@example.com",
+				"normalized_email": "neeraj@example.com",
+				"verified": false,
+				"application_id": "",
+				"autogenerated": false,
+				"created_at": "2021-02-10 14:44:38.104949",
+				"updated_at": "2021-02-11 11:00:00+02:00"
+			}`,
+			expected: `{
+				"id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"username": "neeraj",
+				"email": "neeraj@example.com",
+				"normalized_email": "neeraj@example.com",
+				"verified": false,
+				"application_id": "",
+				"autogenerated": false,
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-11T09:00:00Z"
+			}`,
+			value: func() interface{} { return &brood.User{} },
+		},
+		{
+			name:    "generated token",
+			payload: `{"access_token": "0a9b8c7d-6e5f-4a3b-2c1d-0e9f8a7b6c5d", "token_type": "bugout"}`,
+			value:   func() interface{} { return &brood.UserGeneratedToken{} },
+		},
+		{
+			name: "tokens list",
+			payload: `{
+				"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"username": "neeraj",
+				"token": [{
+					"id": "0a9b8c7d-6e5f-4a3b-2c1d-0e9f8a7b6c5d",
+					"token_type": "bugout",
+					"created_at": "2021-02-10 14:44:38.104949",
+					"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+					"updated_at": "2021-02-10T14:44:38.104949+00:00",
+					"note": "CI",
+					"active": true
+				}]
+			}`,
+			expected: `{
+				"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"username": "neeraj",
+				"token": [{
+					"id": "0a9b8c7d-6e5f-4a3b-2c1d-0e9f8a7b6c5d",
+					"token_type": "bugout",
+					"created_at": "2021-02-10T14:44:38.104949Z",
+					"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+					"updated_at": "2021-02-10T14:44:38.104949Z",
+					"note": "CI",
+					"active": true
+				}]
+			}`,
+			value: func() interface{} { return &brood.UserTokensList{} },
+		},
+		{
+			name:    "group",
+			payload: `{"id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10", "group_name": "Platform team"}`,
+			value:   func() interface{} { return &brood.Group{} },
+		},
+		{
+			name: "groups of a user",
+			payload: `{"groups": [{
+				"group_id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10",
+				"group_name": "Platform team",
+				"autogenerated": false,
+				"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"user_type": "member"
+			}]}`,
+			value: func() interface{} { return &brood.UserGroupsList{} },
+		},
+		{
+			name: "group members",
+			payload: `{"users": [
+				{"user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21", "username": "neeraj", "user_type": "owner"},
+				{"user_id": "9e8d7c6b-5a4f-4e3d-2c1b-0a9f8e7d6c5b", "username": "zomglings", "user_type": "member"}
+			]}`,
+			value: func() interface{} { return &brood.GroupUsersList{} },
+		},
+		{
+			name: "applications",
+			payload: `{"applications": [
+				{"id": "5d1c0b8e-3a7f-4b6e-9c2d-1e0f8a7b6c5d", "group_id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10", "name": "Dashboard", "description": "Internal dashboard"},
+				{"id": "6e2d1c9f-4b8a-4c7f-8d3e-2f1a9b8c7d6e", "group_id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10", "name": "Bot"}
+			]}`,
+			value: func() interface{} { return &brood.ApplicationsList{} },
+		},
+		{
+			name: "application users",
+			payload: `{"users": [{
+				"id": "9e8d7c6b-5a4f-4e3d-2c1b-0a9f8e7d6c5b",
+				"username": "app-user",
+				"email": "app-user@example.com",
+				"normalized_email": "app-user@example.com",
+				"verified": true,
+				"application_id": "5d1c0b8e-3a7f-4b6e-9c2d-1e0f8a7b6c5d",
+				"autogenerated": false,
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-10T14:44:38.104949Z"
+			}]}`,
+			value: func() interface{} { return &brood.ApplicationUsersList{} },
+		},
+		{
+			name: "resources",
+			payload: `{"resources": [{
+				"id": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+				"application_id": "5d1c0b8e-3a7f-4b6e-9c2d-1e0f8a7b6c5d",
+				"resource_data": {"type": "dashboard", "layout": {"columns": 2, "widgets": ["a", "b"]}, "public": false}
+			}]}`,
+			value: func() interface{} { return &brood.Resources{} },
+		},
+		{
+			name: "resource holders",
+			payload: `{"resource_id": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "holders": [
+				{"holder_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21", "holder_type": "user", "permissions": ["admin", "read"]}
+			]}`,
+			value: func() interface{} { return &brood.ResourceHolders{} },
+		},
+		{
+			name: "resource holders with id",
+			payload: `{"resource_id": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "holders": [
+				{"id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10", "holder_type": "group", "permissions": ["read"]}
+			]}`,
+			// Some endpoints return the holder's ID as "id" instead of "holder_id".
+			expected: `{"resource_id": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "holders": [
+				{"holder_id": "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10", "holder_type": "group", "permissions": ["read"]}
+			]}`,
+			value: func() interface{} { return &brood.ResourceHolders{} },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkRoundTrip(t, c.payload, c.expected, c.value())
+		})
+	}
+}
+
+func TestUserTimestamps(t *testing.T) {
+	expected := time.Date(2021, 2, 10, 14, 44, 38, 104949000, time.UTC)
+	timestamps := []string{
+		"2021-02-10T14:44:38.104949Z",
+		"2021-02-10T16:44:38.104949+02:00",
+		"2021-02-10T14:44:38.104949",
+		"2021-02-10 14:44:38.104949",
+		"2021-02-10 14:44:38.104949+00:00",
+		"2021-02-10 09:44:38.104949-0500",
+	}
+
+	for _, timestamp := range timestamps {
+		t.Run(timestamp, func(t *testing.T) {
+			payload := []byte(`{"created_at": "` + timestamp + `", "updated_at": "` + timestamp + `"}`)
+			var user brood.User
+			var authUser brood.AuthUser
+			var token brood.UserToken
+			for _, value := range []interface{}{&user, &authUser, &token} {
+				if err := json.Unmarshal(payload, value); err != nil {
+					t.Fatalf("Could not decode %T: %v", value, err)
+				}
+			}
+			for _, parsed := range []time.Time{user.CreatedAt, user.UpdatedAt, authUser.CreatedAt, authUser.UpdatedAt, token.CreatedAt, token.UpdatedAt} {
+				if !parsed.Equal(expected) || parsed.Location() != time.UTC {
+					t.Errorf("Expected %s, got %s", expected, parsed)
+				}
+			}
+		})
+	}
+
+	for _, value := range []interface{}{&brood.User{}, &brood.AuthUser{}, &brood.UserToken{}} {
+		if err := json.Unmarshal([]byte(`{"created_at": "yesterday"}`), value); err == nil {
+			t.Errorf("Expected an error decoding %T with an invalid timestamp", value)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package brood
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,12 +13,16 @@ import (
 )
 
 func (client BroodClient) CreateGroup(token, name string) (Group, error) {
+	return client.CreateGroupContext(context.Background(), token, name)
+}
+
+func (client BroodClient) CreateGroupContext(ctx context.Context, token, name string) (Group, error) {
 	groupsRoute := client.Routes.Groups
 	data := url.Values{}
 	data.Add("group_name", name)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", groupsRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", groupsRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return Group{}, requestErr
 	}
@@ -26,7 +31,7 @@ func (client BroodClient) CreateGroup(token, name string) (Group, error) {
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "CreateGroup")
 	if err != nil {
 		return Group{}, err
 	}
@@ -43,15 +48,19 @@ func (client BroodClient) CreateGroup(token, name string) (Group, error) {
 }
 
 func (client BroodClient) GetUserGroups(token string) (UserGroupsList, error) {
+	return client.GetUserGroupsContext(context.Background(), token)
+}
+
+func (client BroodClient) GetUserGroupsContext(ctx context.Context, token string) (UserGroupsList, error) {
 	groupsRoute := client.Routes.Groups
-	request, requestErr := http.NewRequest("GET", groupsRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", groupsRoute, nil)
 	if requestErr != nil {
 		return UserGroupsList{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "GetUserGroups")
 	if err != nil {
 		return UserGroupsList{}, err
 	}
@@ -67,18 +76,84 @@ func (client BroodClient) GetUserGroups(token string) (UserGroupsList, error) {
 	return userGroups, decodeErr
 }
 
+func (client BroodClient) GetGroup(token, groupID string) (Group, error) {
+	return client.GetGroupContext(context.Background(), token, groupID)
+}
+
+func (client BroodClient) GetGroupContext(ctx context.Context, token, groupID string) (Group, error) {
+	groupsRoute := client.Routes.Groups
+	groupRoute := fmt.Sprintf("%s/%s", groupsRoute, groupID)
+
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", groupRoute, nil)
+	if requestErr != nil {
+		return Group{}, requestErr
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "GetGroup")
+	if err != nil {
+		return Group{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return Group{}, statusErr
+	}
+
+	var group Group
+	decodeErr := json.NewDecoder(response.Body).Decode(&group)
+	return group, decodeErr
+}
+
+func (client BroodClient) ListGroupMembers(token, groupID string) (GroupUsersList, error) {
+	return client.ListGroupMembersContext(context.Background(), token, groupID)
+}
+
+func (client BroodClient) ListGroupMembersContext(ctx context.Context, token, groupID string) (GroupUsersList, error) {
+	groupsRoute := client.Routes.Groups
+	membersRoute := fmt.Sprintf("%s/%s/users", groupsRoute, groupID)
+
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", membersRoute, nil)
+	if requestErr != nil {
+		return GroupUsersList{}, requestErr
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "ListGroupMembers")
+	if err != nil {
+		return GroupUsersList{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return GroupUsersList{}, statusErr
+	}
+
+	var members GroupUsersList
+	decodeErr := json.NewDecoder(response.Body).Decode(&members)
+	return members, decodeErr
+}
+
 func (client BroodClient) DeleteGroup(token, groupID string) (Group, error) {
+	return client.DeleteGroupContext(context.Background(), token, groupID)
+}
+
+func (client BroodClient) DeleteGroupContext(ctx context.Context, token, groupID string) (Group, error) {
 	groupsRoute := client.Routes.Groups
 	deletionRoute := fmt.Sprintf("%s/%s", groupsRoute, groupID)
 
-	request, requestErr := http.NewRequest("DELETE", deletionRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", deletionRoute, nil)
 	if requestErr != nil {
 		return Group{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "DeleteGroup")
 	if err != nil {
 		return Group{}, err
 	}
@@ -95,13 +170,17 @@ func (client BroodClient) DeleteGroup(token, groupID string) (Group, error) {
 }
 
 func (client BroodClient) RenameGroup(token, groupID, name string) (Group, error) {
+	return client.RenameGroupContext(context.Background(), token, groupID, name)
+}
+
+func (client BroodClient) RenameGroupContext(ctx context.Context, token, groupID, name string) (Group, error) {
 	groupsRoute := client.Routes.Groups
 	renameRoute := fmt.Sprintf("%s/%s/name", groupsRoute, groupID)
 	data := url.Values{}
 	data.Add("group_name", name)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", renameRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", renameRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return Group{}, requestErr
 	}
@@ -110,7 +189,7 @@ func (client BroodClient) RenameGroup(token, groupID, name string) (Group, error
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "RenameGroup")
 	if err != nil {
 		return Group{}, err
 	}
@@ -127,6 +206,12 @@ func (client BroodClient) RenameGroup(token, groupID, name string) (Group, error
 }
 
 func (client BroodClient) AddUserToGroup(token, groupID, username, role string) (UserGroup, error) {
+	return client.AddUserToGroupContext(context.Background(), token, groupID, username, role)
+}
+
+// AddUserToGroupContext adds a user to a group with the given role (see GroupRoles). If the user is
+// already a member of the group, their role is changed.
+func (client BroodClient) AddUserToGroupContext(ctx context.Context, token, groupID, username, role string) (UserGroup, error) {
 	groupsRoute := client.Routes.Groups
 	addUserRoute := fmt.Sprintf("%s/%s/role", groupsRoute, groupID)
 	data := url.Values{}
@@ -134,7 +219,7 @@ func (client BroodClient) AddUserToGroup(token, groupID, username, role string)
 	data.Add("user_type", role)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", addUserRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", addUserRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return UserGroup{}, requestErr
 	}
@@ -143,7 +228,7 @@ func (client BroodClient) AddUserToGroup(token, groupID, username, role string)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "AddUserToGroup")
 	if err != nil {
 		return UserGroup{}, err
 	}
@@ -159,14 +244,43 @@ func (client BroodClient) AddUserToGroup(token, groupID, username, role string)
 	return membership, decodeErr
 }
 
+func (client BroodClient) SetUserGroupRole(token, groupID, username string, role GroupRole) (UserGroup, error) {
+	return client.SetUserGroupRoleContext(context.Background(), token, groupID, username, role)
+}
+
+// SetUserGroupRoleContext changes the role of a user who is already a member of a group. Unlike
+// AddUserToGroupContext, it fails instead of adding users who are not members.
+func (client BroodClient) SetUserGroupRoleContext(ctx context.Context, token, groupID, username string, role GroupRole) (UserGroup, error) {
+	members, membersErr := client.ListGroupMembersContext(ctx, token, groupID)
+	if membersErr != nil {
+		return UserGroup{}, membersErr
+	}
+	isMember := false
+	for _, member := range members.Users {
+		if member.Username == username {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return UserGroup{}, fmt.Errorf("User %s is not a member of group %s", username, groupID)
+	}
+
+	return client.AddUserToGroupContext(ctx, token, groupID, username, string(role))
+}
+
 func (client BroodClient) RemoveUserFromGroup(token, groupID, username string) (UserGroup, error) {
+	return client.RemoveUserFromGroupContext(context.Background(), token, groupID, username)
+}
+
+func (client BroodClient) RemoveUserFromGroupContext(ctx context.Context, token, groupID, username string) (UserGroup, error) {
 	groupsRoute := client.Routes.Groups
 	removeUserRoute := fmt.Sprintf("%s/%s/role", groupsRoute, groupID)
 	data := url.Values{}
 	data.Add("username", username)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("DELETE", removeUserRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", removeUserRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return UserGroup{}, requestErr
 	}
@@ -175,7 +289,7 @@ func (client BroodClient) RemoveUserFromGroup(token, groupID, username string) (
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "RemoveUserFromGroup")
 	if err != nil {
 		return UserGroup{}, err
 	}
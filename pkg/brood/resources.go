@@ -2,6 +2,7 @@ package brood
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,10 @@ import (
 )
 
 func (client BroodClient) CreateResource(token, applicationId string, resourceData interface{}) (Resource, error) {
+	return client.CreateResourceContext(context.Background(), token, applicationId, resourceData)
+}
+
+func (client BroodClient) CreateResourceContext(ctx context.Context, token, applicationId string, resourceData interface{}) (Resource, error) {
 	resourcesRoute := client.Routes.Resources
 	requestBody := resourceCreateRequest{
 		ApplicationId: applicationId,
@@ -20,7 +25,7 @@ func (client BroodClient) CreateResource(token, applicationId string, resourceDa
 	if encodeErr != nil {
 		return Resource{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("POST", resourcesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", resourcesRoute, requestBuffer)
 	if requestErr != nil {
 		return Resource{}, requestErr
 	}
@@ -28,7 +33,7 @@ func (client BroodClient) CreateResource(token, applicationId string, resourceDa
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "CreateResource")
 	if responseErr != nil {
 		return Resource{}, responseErr
 	}
@@ -45,6 +50,10 @@ func (client BroodClient) CreateResource(token, applicationId string, resourceDa
 }
 
 func (client BroodClient) UpdateResource(token, resourceId string, update interface{}, dropKeys []string) (Resource, error) {
+	return client.UpdateResourceContext(context.Background(), token, resourceId, update, dropKeys)
+}
+
+func (client BroodClient) UpdateResourceContext(ctx context.Context, token, resourceId string, update interface{}, dropKeys []string) (Resource, error) {
 	requestBody := resourceUpdateRequest{
 		Update:   make(map[string]interface{}),
 		DropKeys: []string{},
@@ -61,14 +70,14 @@ func (client BroodClient) UpdateResource(token, resourceId string, update interf
 		return Resource{}, encodeErr
 	}
 	resourcesRoute := fmt.Sprintf("%s/%s", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("PUT", resourcesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "PUT", resourcesRoute, requestBuffer)
 	if requestErr != nil {
 		return Resource{}, requestErr
 	}
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "UpdateResource")
 	if responseErr != nil {
 		return Resource{}, responseErr
 	}
@@ -85,15 +94,19 @@ func (client BroodClient) UpdateResource(token, resourceId string, update interf
 }
 
 func (client BroodClient) GetResource(token, resourceId string) (Resource, error) {
+	return client.GetResourceContext(context.Background(), token, resourceId)
+}
+
+func (client BroodClient) GetResourceContext(ctx context.Context, token, resourceId string) (Resource, error) {
 	resourcesRoute := fmt.Sprintf("%s/%s", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("GET", resourcesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", resourcesRoute, nil)
 	if requestErr != nil {
 		return Resource{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "GetResource")
 	if responseErr != nil {
 		return Resource{}, responseErr
 	}
@@ -110,8 +123,12 @@ func (client BroodClient) GetResource(token, resourceId string) (Resource, error
 }
 
 func (client BroodClient) GetResources(token, applicationId string, queryParameters map[string]string) (Resources, error) {
+	return client.GetResourcesContext(context.Background(), token, applicationId, queryParameters)
+}
+
+func (client BroodClient) GetResourcesContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (Resources, error) {
 	resourcesRoute := client.Routes.Resources
-	request, requestErr := http.NewRequest("GET", resourcesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", resourcesRoute, nil)
 	if requestErr != nil {
 		return Resources{}, requestErr
 	}
@@ -125,7 +142,7 @@ func (client BroodClient) GetResources(token, applicationId string, queryParamet
 	}
 	request.URL.RawQuery = query.Encode()
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "GetResources")
 	if responseErr != nil {
 		return Resources{}, responseErr
 	}
@@ -142,15 +159,19 @@ func (client BroodClient) GetResources(token, applicationId string, queryParamet
 }
 
 func (client BroodClient) DeleteResource(token, resourceId string) (Resource, error) {
+	return client.DeleteResourceContext(context.Background(), token, resourceId)
+}
+
+func (client BroodClient) DeleteResourceContext(ctx context.Context, token, resourceId string) (Resource, error) {
 	resourcesRoute := fmt.Sprintf("%s/%s", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("DELETE", resourcesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", resourcesRoute, nil)
 	if requestErr != nil {
 		return Resource{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "DeleteResource")
 	if responseErr != nil {
 		return Resource{}, responseErr
 	}
@@ -168,15 +189,19 @@ func (client BroodClient) DeleteResource(token, resourceId string) (Resource, er
 }
 
 func (client BroodClient) GetResourceHolders(token, resourceId string) (ResourceHolders, error) {
+	return client.GetResourceHoldersContext(context.Background(), token, resourceId)
+}
+
+func (client BroodClient) GetResourceHoldersContext(ctx context.Context, token, resourceId string) (ResourceHolders, error) {
 	resourcesRoute := fmt.Sprintf("%s/%s/holders", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("GET", resourcesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", resourcesRoute, nil)
 	if requestErr != nil {
 		return ResourceHolders{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "GetResourceHolders")
 	if responseErr != nil {
 		return ResourceHolders{}, responseErr
 	}
@@ -193,6 +218,10 @@ func (client BroodClient) GetResourceHolders(token, resourceId string) (Resource
 }
 
 func (client BroodClient) AddResourceHolderPermissions(token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
+	return client.AddResourceHolderPermissionsContext(context.Background(), token, resourceId, resourceHolder)
+}
+
+func (client BroodClient) AddResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
 	requestBody := ResourceHolder(resourceHolder)
 	requestBuffer := new(bytes.Buffer)
 	encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
@@ -200,14 +229,14 @@ func (client BroodClient) AddResourceHolderPermissions(token, resourceId string,
 		return ResourceHolders{}, encodeErr
 	}
 	resourcesRoute := fmt.Sprintf("%s/%s/holders", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("POST", resourcesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", resourcesRoute, requestBuffer)
 	if requestErr != nil {
 		return ResourceHolders{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "AddResourceHolderPermissions")
 	if responseErr != nil {
 		return ResourceHolders{}, responseErr
 	}
@@ -224,6 +253,10 @@ func (client BroodClient) AddResourceHolderPermissions(token, resourceId string,
 }
 
 func (client BroodClient) DeleteResourceHolderPermissions(token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
+	return client.DeleteResourceHolderPermissionsContext(context.Background(), token, resourceId, resourceHolder)
+}
+
+func (client BroodClient) DeleteResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
 	requestBody := ResourceHolder(resourceHolder)
 	requestBuffer := new(bytes.Buffer)
 	encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
@@ -231,14 +264,14 @@ func (client BroodClient) DeleteResourceHolderPermissions(token, resourceId stri
 		return ResourceHolders{}, encodeErr
 	}
 	resourcesRoute := fmt.Sprintf("%s/%s/holders", client.Routes.Resources, resourceId)
-	request, requestErr := http.NewRequest("DELETE", resourcesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", resourcesRoute, requestBuffer)
 	if requestErr != nil {
 		return ResourceHolders{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "DeleteResourceHolderPermissions")
 	if responseErr != nil {
 		return ResourceHolders{}, responseErr
 	}
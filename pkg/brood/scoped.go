@@ -0,0 +1,350 @@
+package brood
+
+import (
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// TokenSource supplies access tokens to a ScopedClient. See utils.TokenSource.
+type TokenSource = utils.TokenSource
+
+// ScopedClient wraps a BroodCaller and a TokenSource. Its methods mirror those of BroodCaller, but
+// take the access token from the TokenSource instead of as their first argument.
+type ScopedClient struct {
+	Caller BroodCaller
+	Tokens TokenSource
+}
+
+func NewScopedClient(caller BroodCaller, tokens TokenSource) ScopedClient {
+	return ScopedClient{Caller: caller, Tokens: tokens}
+}
+
+// WithToken returns a ScopedClient which authenticates every request with the given token.
+func (client BroodClient) WithToken(token string) ScopedClient {
+	return NewScopedClient(client, utils.NewStaticTokenSource(token))
+}
+
+// WithTokenSource returns a ScopedClient which authenticates every request with a token from
+// the given TokenSource.
+func (client BroodClient) WithTokenSource(tokens TokenSource) ScopedClient {
+	return NewScopedClient(client, tokens)
+}
+
+func (client ScopedClient) Ping() (string, error) {
+	return client.Caller.Ping()
+}
+
+func (client ScopedClient) Version() (string, error) {
+	return client.Caller.Version()
+}
+
+func (client ScopedClient) Auth() (AuthUser, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return AuthUser{}, tokenErr
+	}
+	return client.Caller.Auth(token)
+}
+
+func (client ScopedClient) AnnotateToken(tokenType, note string) (string, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return "", tokenErr
+	}
+	return client.Caller.AnnotateToken(token, tokenType, note)
+}
+
+func (client ScopedClient) ListTokens() (UserTokensList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserTokensList{}, tokenErr
+	}
+	return client.Caller.ListTokens(token)
+}
+
+func (client ScopedClient) RevokeToken(targetToken string) (UserToken, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserToken{}, tokenErr
+	}
+	caller, ok := client.Caller.(TokenRevocationCaller)
+	if !ok {
+		return UserToken{}, utils.UnsupportedError(client.Caller, "RevokeToken")
+	}
+	return caller.RevokeToken(token, targetToken)
+}
+
+func (client ScopedClient) FindUser(queryParameters map[string]string) (User, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return User{}, tokenErr
+	}
+	return client.Caller.FindUser(token, queryParameters)
+}
+
+func (client ScopedClient) FindApplicationUser(applicationId string, queryParameters map[string]string) (User, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return User{}, tokenErr
+	}
+	caller, ok := client.Caller.(ApplicationUserCaller)
+	if !ok {
+		return User{}, utils.UnsupportedError(client.Caller, "FindApplicationUser")
+	}
+	return caller.FindApplicationUser(token, applicationId, queryParameters)
+}
+
+func (client ScopedClient) GetUser() (User, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return User{}, tokenErr
+	}
+	return client.Caller.GetUser(token)
+}
+
+func (client ScopedClient) VerifyUser(code string) (User, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return User{}, tokenErr
+	}
+	return client.Caller.VerifyUser(token, code)
+}
+
+func (client ScopedClient) ChangePassword(currentPassword, newPassword string) (User, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return User{}, tokenErr
+	}
+	return client.Caller.ChangePassword(token, currentPassword, newPassword)
+}
+
+func (client ScopedClient) RequestPasswordReset(email string) (string, error) {
+	caller, ok := client.Caller.(PasswordResetCaller)
+	if !ok {
+		return "", utils.UnsupportedError(client.Caller, "RequestPasswordReset")
+	}
+	return caller.RequestPasswordReset(email)
+}
+
+func (client ScopedClient) ConfirmPasswordReset(resetID, newPassword string) (User, error) {
+	caller, ok := client.Caller.(PasswordResetCaller)
+	if !ok {
+		return User{}, utils.UnsupportedError(client.Caller, "ConfirmPasswordReset")
+	}
+	return caller.ConfirmPasswordReset(resetID, newPassword)
+}
+
+func (client ScopedClient) CreateGroup(name string) (Group, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Group{}, tokenErr
+	}
+	return client.Caller.CreateGroup(token, name)
+}
+
+func (client ScopedClient) GetUserGroups() (UserGroupsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserGroupsList{}, tokenErr
+	}
+	return client.Caller.GetUserGroups(token)
+}
+
+func (client ScopedClient) GetGroup(groupID string) (Group, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Group{}, tokenErr
+	}
+	caller, ok := client.Caller.(GroupRoleCaller)
+	if !ok {
+		return Group{}, utils.UnsupportedError(client.Caller, "GetGroup")
+	}
+	return caller.GetGroup(token, groupID)
+}
+
+func (client ScopedClient) ListGroupMembers(groupID string) (GroupUsersList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return GroupUsersList{}, tokenErr
+	}
+	caller, ok := client.Caller.(GroupRoleCaller)
+	if !ok {
+		return GroupUsersList{}, utils.UnsupportedError(client.Caller, "ListGroupMembers")
+	}
+	return caller.ListGroupMembers(token, groupID)
+}
+
+func (client ScopedClient) DeleteGroup(groupID string) (Group, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Group{}, tokenErr
+	}
+	return client.Caller.DeleteGroup(token, groupID)
+}
+
+func (client ScopedClient) RenameGroup(groupID, name string) (Group, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Group{}, tokenErr
+	}
+	return client.Caller.RenameGroup(token, groupID, name)
+}
+
+func (client ScopedClient) AddUserToGroup(groupID, username, role string) (UserGroup, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserGroup{}, tokenErr
+	}
+	return client.Caller.AddUserToGroup(token, groupID, username, role)
+}
+
+func (client ScopedClient) SetUserGroupRole(groupID, username string, role GroupRole) (UserGroup, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserGroup{}, tokenErr
+	}
+	caller, ok := client.Caller.(GroupRoleCaller)
+	if !ok {
+		return UserGroup{}, utils.UnsupportedError(client.Caller, "SetUserGroupRole")
+	}
+	return caller.SetUserGroupRole(token, groupID, username, role)
+}
+
+func (client ScopedClient) RemoveUserFromGroup(groupID, username string) (UserGroup, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return UserGroup{}, tokenErr
+	}
+	return client.Caller.RemoveUserFromGroup(token, groupID, username)
+}
+
+func (client ScopedClient) CreateResource(applicationId string, resourceData interface{}) (Resource, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Resource{}, tokenErr
+	}
+	return client.Caller.CreateResource(token, applicationId, resourceData)
+}
+
+func (client ScopedClient) UpdateResource(resourceId string, update interface{}, dropKeys []string) (Resource, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Resource{}, tokenErr
+	}
+	return client.Caller.UpdateResource(token, resourceId, update, dropKeys)
+}
+
+func (client ScopedClient) GetResource(resourceId string) (Resource, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Resource{}, tokenErr
+	}
+	return client.Caller.GetResource(token, resourceId)
+}
+
+func (client ScopedClient) GetResources(applicationId string, queryParameters map[string]string) (Resources, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Resources{}, tokenErr
+	}
+	return client.Caller.GetResources(token, applicationId, queryParameters)
+}
+
+func (client ScopedClient) DeleteResource(resourceId string) (Resource, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Resource{}, tokenErr
+	}
+	return client.Caller.DeleteResource(token, resourceId)
+}
+
+func (client ScopedClient) GetResourceHolders(resourceId string) (ResourceHolders, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return ResourceHolders{}, tokenErr
+	}
+	return client.Caller.GetResourceHolders(token, resourceId)
+}
+
+func (client ScopedClient) AddResourceHolderPermissions(resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return ResourceHolders{}, tokenErr
+	}
+	return client.Caller.AddResourceHolderPermissions(token, resourceId, resourceHolder)
+}
+
+func (client ScopedClient) DeleteResourceHolderPermissions(resourceId string, resourceHolder ResourceHolder) (ResourceHolders, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return ResourceHolders{}, tokenErr
+	}
+	return client.Caller.DeleteResourceHolderPermissions(token, resourceId, resourceHolder)
+}
+
+func (client ScopedClient) CreateApplication(groupId, name, description string) (Application, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Application{}, tokenErr
+	}
+	return client.Caller.CreateApplication(token, groupId, name, description)
+}
+
+func (client ScopedClient) GetApplication(applicationId string) (Application, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Application{}, tokenErr
+	}
+	return client.Caller.GetApplication(token, applicationId)
+}
+
+func (client ScopedClient) ListApplications(groupId string) (ApplicationsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return ApplicationsList{}, tokenErr
+	}
+	return client.Caller.ListApplications(token, groupId)
+}
+
+func (client ScopedClient) DeleteApplication(applicationId string) (Application, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Application{}, tokenErr
+	}
+	return client.Caller.DeleteApplication(token, applicationId)
+}
+
+func (client ScopedClient) UpdateApplication(applicationId, name, description string) (Application, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Application{}, tokenErr
+	}
+	caller, ok := client.Caller.(ApplicationCaller)
+	if !ok {
+		return Application{}, utils.UnsupportedError(client.Caller, "UpdateApplication")
+	}
+	return caller.UpdateApplication(token, applicationId, name, description)
+}
+
+func (client ScopedClient) TransferApplication(applicationId, groupId string) (Application, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Application{}, tokenErr
+	}
+	caller, ok := client.Caller.(ApplicationCaller)
+	if !ok {
+		return Application{}, utils.UnsupportedError(client.Caller, "TransferApplication")
+	}
+	return caller.TransferApplication(token, applicationId, groupId)
+}
+
+func (client ScopedClient) ListApplicationUsers(applicationId string) (ApplicationUsersList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return ApplicationUsersList{}, tokenErr
+	}
+	caller, ok := client.Caller.(ApplicationCaller)
+	if !ok {
+		return ApplicationUsersList{}, utils.UnsupportedError(client.Caller, "ListApplicationUsers")
+	}
+	return caller.ListApplicationUsers(token, applicationId)
+}
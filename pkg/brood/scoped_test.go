@@ -0,0 +1,78 @@
+package brood_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/brood/broodtest"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// Hides the optional methods of the Fake.
+type basicCaller struct {
+	brood.BroodCaller
+}
+
+type basicCallerContext struct {
+	brood.BroodCallerContext
+}
+
+func TestScopedClientOptionalMethods(t *testing.T) {
+	fake := broodtest.NewFake()
+	owner, ownerToken, ownerErr := fake.SeedUser("owner", "owner@example.com", "password")
+	if ownerErr != nil {
+		t.Fatal(ownerErr)
+	}
+	if _, _, err := fake.SeedUser("member", "member@example.com", "password"); err != nil {
+		t.Fatal(err)
+	}
+	group, groupErr := fake.SeedGroup(owner.Id, "group")
+	if groupErr != nil {
+		t.Fatal(groupErr)
+	}
+
+	client := brood.NewScopedClient(fake, utils.NewStaticTokenSource(ownerToken))
+	if _, err := client.AddUserToGroup(group.Id, "member", string(brood.GroupRoleMember)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	membership, err := client.SetUserGroupRole(group.Id, "member", brood.GroupRoleOwner)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if membership.UserType != string(brood.GroupRoleOwner) {
+		t.Errorf("Expected the member to become an owner, got %#v", membership)
+	}
+
+	client = brood.NewScopedClient(basicCaller{fake}, utils.NewStaticTokenSource(ownerToken))
+	if _, err := client.AddUserToGroup(group.Id, "member", string(brood.GroupRoleMember)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.SetUserGroupRole(group.Id, "member", brood.GroupRoleOwner); !errors.Is(err, brood.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from SetUserGroupRole, got %v", err)
+	}
+	if _, err := client.RevokeToken(ownerToken); !errors.Is(err, brood.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from RevokeToken, got %v", err)
+	}
+}
+
+func TestRotateTokenWithoutRevocation(t *testing.T) {
+	fake := broodtest.NewFake()
+	_, token, seedErr := fake.SeedUser("user", "user@example.com", "password")
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+
+	_, err := brood.RotateToken(context.Background(), basicCallerContext{fake}, token, "user", "password")
+	if !errors.Is(err, brood.ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported, got %v", err)
+	}
+	tokens, listErr := fake.ListTokens(token)
+	if listErr != nil {
+		t.Fatal(listErr)
+	}
+	if len(tokens.Tokens) != 1 {
+		t.Errorf("Expected no new tokens to be generated, got %d tokens", len(tokens.Tokens))
+	}
+}
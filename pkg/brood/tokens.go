@@ -0,0 +1,56 @@
+package brood
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// RotateToken replaces an access token with a new one. The new token is generated for the user with
+// the given username and password and gets the type and note of the old token, and then the old
+// token is revoked.
+//
+// If the old token could not be revoked, the new token is returned along with the error, so that it
+// can still be used (and the old token revoked later). The client must implement
+// TokenRevocationCallerContext.
+func RotateToken(ctx context.Context, client BroodCallerContext, token, username, password string) (string, error) {
+	revoker, ok := client.(TokenRevocationCallerContext)
+	if !ok {
+		return "", utils.UnsupportedError(client, "RevokeTokenContext")
+	}
+	tokens, listErr := client.ListTokensContext(ctx, token)
+	if listErr != nil {
+		return "", listErr
+	}
+	var oldToken *UserToken
+	for i := range tokens.Tokens {
+		if tokens.Tokens[i].Id == token {
+			oldToken = &tokens.Tokens[i]
+			break
+		}
+	}
+	if oldToken == nil {
+		return "", fmt.Errorf("Could not find the token to rotate among the tokens of user %s", tokens.Username)
+	}
+	if tokens.Username != username {
+		return "", fmt.Errorf("Token belongs to user %s, not %s", tokens.Username, username)
+	}
+
+	newToken, generateErr := client.GenerateTokenContext(ctx, username, password)
+	if generateErr != nil {
+		return "", generateErr
+	}
+	if oldToken.TokenType != "" || oldToken.Note != "" {
+		_, annotateErr := client.AnnotateTokenContext(ctx, newToken, oldToken.TokenType, oldToken.Note)
+		if annotateErr != nil {
+			return newToken, fmt.Errorf("Could not annotate new token: %s", annotateErr.Error())
+		}
+	}
+
+	_, revokeErr := revoker.RevokeTokenContext(ctx, newToken, token)
+	if revokeErr != nil {
+		return newToken, fmt.Errorf("Could not revoke old token: %s", revokeErr.Error())
+	}
+	return newToken, nil
+}
@@ -2,6 +2,7 @@ package brood
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -23,15 +24,19 @@ func getUserID(decoder *json.Decoder) (string, error) {
 }
 
 func (client BroodClient) Auth(token string) (AuthUser, error) {
+	return client.AuthContext(context.Background(), token)
+}
+
+func (client BroodClient) AuthContext(ctx context.Context, token string) (AuthUser, error) {
 	authRoute := client.Routes.Auth
-	request, requestErr := http.NewRequest("GET", authRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", authRoute, nil)
 	if requestErr != nil {
 		return AuthUser{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "Auth")
 	if err != nil {
 		return AuthUser{}, err
 	}
@@ -55,12 +60,47 @@ func (client BroodClient) Auth(token string) (AuthUser, error) {
 }
 
 func (client BroodClient) CreateUser(username, email, password string) (User, error) {
+	return client.CreateUserContext(context.Background(), username, email, password)
+}
+
+func (client BroodClient) CreateUserContext(ctx context.Context, username, email, password string) (User, error) {
+	return client.createUser(ctx, "", "", username, email, password, "CreateUser")
+}
+
+func (client BroodClient) CreateApplicationUser(applicationId, applicationToken, username, email, password string) (User, error) {
+	return client.CreateApplicationUserContext(context.Background(), applicationId, applicationToken, username, email, password)
+}
+
+// CreateApplicationUserContext registers a user inside a Brood application. Users of different
+// applications may share usernames and email addresses. applicationToken is optional; if it is set,
+// it is sent as the access token of the request, which applications that do not accept open
+// registration require.
+func (client BroodClient) CreateApplicationUserContext(ctx context.Context, applicationId, applicationToken, username, email, password string) (User, error) {
+	return client.createUser(ctx, applicationId, applicationToken, username, email, password, "CreateApplicationUser")
+}
+
+func (client BroodClient) createUser(ctx context.Context, applicationId, applicationToken, username, email, password, operation string) (User, error) {
 	userRoute := client.Routes.User
 	data := url.Values{}
 	data.Add("username", username)
 	data.Add("email", email)
 	data.Add("password", password)
-	response, err := client.HTTPClient.PostForm(userRoute, data)
+	if applicationId != "" {
+		data.Add("application_id", applicationId)
+	}
+	encodedData := data.Encode()
+
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", userRoute, strings.NewReader(encodedData))
+	if requestErr != nil {
+		return User{}, requestErr
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
+	if applicationToken != "" {
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", applicationToken))
+	}
+
+	response, err := client.do(request, operation)
 	if err != nil {
 		return User{}, err
 	}
@@ -91,12 +131,45 @@ func (client BroodClient) CreateUser(username, email, password string) (User, er
 }
 
 func (client BroodClient) GenerateToken(username, password string) (string, error) {
+	return client.GenerateTokenContext(context.Background(), username, password)
+}
+
+func (client BroodClient) GenerateTokenContext(ctx context.Context, username, password string) (string, error) {
+	return client.generateToken(ctx, "", "", username, password, "GenerateToken")
+}
+
+func (client BroodClient) GenerateApplicationToken(applicationId, applicationToken, username, password string) (string, error) {
+	return client.GenerateApplicationTokenContext(context.Background(), applicationId, applicationToken, username, password)
+}
+
+// GenerateApplicationTokenContext logs in a user of a Brood application (see
+// CreateApplicationUserContext) and returns a new access token for them. applicationToken is
+// optional, as for CreateApplicationUserContext.
+func (client BroodClient) GenerateApplicationTokenContext(ctx context.Context, applicationId, applicationToken, username, password string) (string, error) {
+	return client.generateToken(ctx, applicationId, applicationToken, username, password, "GenerateApplicationToken")
+}
+
+func (client BroodClient) generateToken(ctx context.Context, applicationId, applicationToken, username, password, operation string) (string, error) {
 	tokenRoute := client.Routes.Token
 	data := url.Values{}
 	data.Add("username", username)
 	data.Add("password", password)
+	if applicationId != "" {
+		data.Add("application_id", applicationId)
+	}
+	encodedData := data.Encode()
 
-	response, err := client.HTTPClient.PostForm(tokenRoute, data)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", tokenRoute, strings.NewReader(encodedData))
+	if requestErr != nil {
+		return "", requestErr
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
+	if applicationToken != "" {
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", applicationToken))
+	}
+
+	response, err := client.do(request, operation)
 	if err != nil {
 		return "", err
 	}
@@ -117,6 +190,10 @@ func (client BroodClient) GenerateToken(username, password string) (string, erro
 }
 
 func (client BroodClient) AnnotateToken(token, tokenType, note string) (string, error) {
+	return client.AnnotateTokenContext(context.Background(), token, tokenType, note)
+}
+
+func (client BroodClient) AnnotateTokenContext(ctx context.Context, token, tokenType, note string) (string, error) {
 	tokenRoute := client.Routes.Token
 	data := url.Values{}
 	data.Add("access_token", token)
@@ -124,14 +201,14 @@ func (client BroodClient) AnnotateToken(token, tokenType, note string) (string,
 	data.Add("token_note", note)
 	encodedData := data.Encode()
 
-	request, err := http.NewRequest("PUT", tokenRoute, strings.NewReader(encodedData))
+	request, err := http.NewRequestWithContext(ctx, "PUT", tokenRoute, strings.NewReader(encodedData))
 	if err != nil {
 		return "", err
 	}
 	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "AnnotateToken")
 	if err != nil {
 		return "", err
 	}
@@ -150,15 +227,19 @@ func (client BroodClient) AnnotateToken(token, tokenType, note string) (string,
 }
 
 func (client BroodClient) ListTokens(token string) (UserTokensList, error) {
+	return client.ListTokensContext(context.Background(), token)
+}
+
+func (client BroodClient) ListTokensContext(ctx context.Context, token string) (UserTokensList, error) {
 	listTokensRoute := client.Routes.ListTokens
-	request, requestErr := http.NewRequest("GET", listTokensRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", listTokensRoute, nil)
 	if requestErr != nil {
 		return UserTokensList{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "ListTokens")
 	if err != nil {
 		return UserTokensList{}, err
 	}
@@ -174,6 +255,37 @@ func (client BroodClient) ListTokens(token string) (UserTokensList, error) {
 	return result, decodeErr
 }
 
+func (client BroodClient) RevokeToken(token, targetToken string) (UserToken, error) {
+	return client.RevokeTokenContext(context.Background(), token, targetToken)
+}
+
+// RevokeTokenContext revokes targetToken, which must belong to the same user as token. A token may
+// revoke itself.
+func (client BroodClient) RevokeTokenContext(ctx context.Context, token, targetToken string) (UserToken, error) {
+	revokeTokenRoute := fmt.Sprintf("%s/%s", client.Routes.RevokeToken, targetToken)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", revokeTokenRoute, nil)
+	if requestErr != nil {
+		return UserToken{}, requestErr
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "RevokeToken")
+	if err != nil {
+		return UserToken{}, err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return UserToken{}, statusErr
+	}
+
+	var result UserToken
+	decodeErr := json.NewDecoder(response.Body).Decode(&result)
+	return result, decodeErr
+}
+
 /*
 	Find Brood user if exists
 
@@ -184,8 +296,32 @@ query parameters:
 - **application_id** (UUID): Application user belongs to
 */
 func (client BroodClient) FindUser(token string, queryParameters map[string]string) (User, error) {
+	return client.FindUserContext(context.Background(), token, queryParameters)
+}
+
+func (client BroodClient) FindUserContext(ctx context.Context, token string, queryParameters map[string]string) (User, error) {
+	return client.findUser(ctx, token, queryParameters, "FindUser")
+}
+
+func (client BroodClient) FindApplicationUser(token, applicationId string, queryParameters map[string]string) (User, error) {
+	return client.FindApplicationUserContext(context.Background(), token, applicationId, queryParameters)
+}
+
+// FindApplicationUserContext looks for a user of a Brood application. It accepts the same query
+// parameters as FindUserContext.
+func (client BroodClient) FindApplicationUserContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (User, error) {
+	applicationParameters := map[string]string{"application_id": applicationId}
+	for k, v := range queryParameters {
+		if k != "application_id" {
+			applicationParameters[k] = v
+		}
+	}
+	return client.findUser(ctx, token, applicationParameters, "FindApplicationUser")
+}
+
+func (client BroodClient) findUser(ctx context.Context, token string, queryParameters map[string]string, operation string) (User, error) {
 	findUserRoute := client.Routes.FindUser
-	request, requestErr := http.NewRequest("GET", findUserRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", findUserRoute, nil)
 	if requestErr != nil {
 		return User{}, requestErr
 	}
@@ -198,7 +334,7 @@ func (client BroodClient) FindUser(token string, queryParameters map[string]stri
 	}
 	request.URL.RawQuery = query.Encode()
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, operation)
 	if err != nil {
 		return User{}, err
 	}
@@ -229,15 +365,19 @@ func (client BroodClient) FindUser(token string, queryParameters map[string]stri
 }
 
 func (client BroodClient) GetUser(token string) (User, error) {
+	return client.GetUserContext(context.Background(), token)
+}
+
+func (client BroodClient) GetUserContext(ctx context.Context, token string) (User, error) {
 	userRoute := client.Routes.User
-	request, requestErr := http.NewRequest("GET", userRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", userRoute, nil)
 	if requestErr != nil {
 		return User{}, requestErr
 	}
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "GetUser")
 	if err != nil {
 		return User{}, err
 	}
@@ -268,12 +408,16 @@ func (client BroodClient) GetUser(token string) (User, error) {
 }
 
 func (client BroodClient) VerifyUser(token, code string) (User, error) {
+	return client.VerifyUserContext(context.Background(), token, code)
+}
+
+func (client BroodClient) VerifyUserContext(ctx context.Context, token, code string) (User, error) {
 	confirmRoute := client.Routes.ConfirmRegistration
 	data := url.Values{}
 	data.Add("verification_code", code)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", confirmRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", confirmRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return User{}, requestErr
 	}
@@ -282,7 +426,7 @@ func (client BroodClient) VerifyUser(token, code string) (User, error) {
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "VerifyUser")
 	if err != nil {
 		return User{}, err
 	}
@@ -313,13 +457,17 @@ func (client BroodClient) VerifyUser(token, code string) (User, error) {
 }
 
 func (client BroodClient) ChangePassword(token, currentPassword, newPassword string) (User, error) {
+	return client.ChangePasswordContext(context.Background(), token, currentPassword, newPassword)
+}
+
+func (client BroodClient) ChangePasswordContext(ctx context.Context, token, currentPassword, newPassword string) (User, error) {
 	changePasswordRoute := client.Routes.ChangePassword
 	data := url.Values{}
 	data.Add("current_password", currentPassword)
 	data.Add("new_password", newPassword)
 	encodedData := data.Encode()
 
-	request, requestErr := http.NewRequest("POST", changePasswordRoute, strings.NewReader(encodedData))
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", changePasswordRoute, strings.NewReader(encodedData))
 	if requestErr != nil {
 		return User{}, requestErr
 	}
@@ -328,7 +476,97 @@ func (client BroodClient) ChangePassword(token, currentPassword, newPassword str
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Add("Accept", "application/json")
 
-	response, err := client.HTTPClient.Do(request)
+	response, err := client.do(request, "ChangePassword")
+	if err != nil {
+		return User{}, err
+	}
+	defer response.Body.Close()
+
+	var buf bytes.Buffer
+	bodyReader := io.TeeReader(response.Body, &buf)
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return User{}, statusErr
+	}
+
+	var user User
+	decodeErr := json.NewDecoder(bodyReader).Decode(&user)
+	if decodeErr != nil {
+		return user, decodeErr
+	}
+	if user.Id == "" {
+		userID, decodeErr := getUserID(json.NewDecoder(&buf))
+		if decodeErr != nil {
+			return user, decodeErr
+		}
+		user.Id = userID
+	}
+
+	return user, nil
+}
+
+// RequestPasswordReset asks Brood to send a password reset email to the user with the given email
+// address. The email contains the reset ID to pass to ConfirmPasswordReset. Returns the body of the
+// response.
+func (client BroodClient) RequestPasswordReset(email string) (string, error) {
+	return client.RequestPasswordResetContext(context.Background(), email)
+}
+
+func (client BroodClient) RequestPasswordResetContext(ctx context.Context, email string) (string, error) {
+	requestResetRoute := client.Routes.RequestReset
+	data := url.Values{}
+	data.Add("email", email)
+	encodedData := data.Encode()
+
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", requestResetRoute, strings.NewReader(encodedData))
+	if requestErr != nil {
+		return "", requestErr
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "RequestPasswordReset")
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return "", statusErr
+	}
+
+	responseBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(responseBytes), nil
+}
+
+// ConfirmPasswordReset sets a new password for a user using the reset ID from a password reset
+// email (see RequestPasswordReset).
+func (client BroodClient) ConfirmPasswordReset(resetID, newPassword string) (User, error) {
+	return client.ConfirmPasswordResetContext(context.Background(), resetID, newPassword)
+}
+
+func (client BroodClient) ConfirmPasswordResetContext(ctx context.Context, resetID, newPassword string) (User, error) {
+	confirmResetRoute := client.Routes.ConfirmReset
+	data := url.Values{}
+	data.Add("reset_id", resetID)
+	data.Add("new_password", newPassword)
+	encodedData := data.Encode()
+
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", confirmResetRoute, strings.NewReader(encodedData))
+	if requestErr != nil {
+		return User{}, requestErr
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.do(request, "ConfirmPasswordReset")
 	if err != nil {
 		return User{}, err
 	}
@@ -4,9 +4,15 @@ import (
 	"time"
 
 	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/config"
 	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
 )
 
+// ClientOption configures the Brood and Spire clients. Options from the spire and brood packages
+// (e.g. spire.WithUserAgent) can be used interchangeably.
+type ClientOption = utils.ClientOption
+
 type BugoutClient struct {
 	Brood brood.BroodCaller
 	Spire spire.SpireCaller
@@ -22,16 +28,42 @@ func ClientSpire(spireURL string, timeout time.Duration) BugoutClient {
 	return BugoutClient{Spire: spireClient}
 }
 
-func ClientFromEnv() (BugoutClient, error) {
-	broodClient, err := brood.ClientFromEnv()
+func ClientFromEnv(opts ...ClientOption) (BugoutClient, error) {
+	env, err := config.LoadEnv()
+	if err != nil {
+		return BugoutClient{}, err
+	}
+
+	broodClient, err := brood.ClientFromConfig(env, opts...)
 	if err != nil {
 		return BugoutClient{}, err
 	}
 
-	spireClient, err := spire.ClientFromEnv()
+	spireClient, err := spire.ClientFromConfig(env, opts...)
 	if err != nil {
 		return BugoutClient{}, err
 	}
 
 	return BugoutClient{Brood: broodClient, Spire: spireClient}, nil
 }
+
+// ScopedClient bundles Brood and Spire clients which are bound to the same access token source, so
+// their methods do not take a token argument.
+type ScopedClient struct {
+	Brood brood.ScopedClient
+	Spire spire.ScopedClient
+}
+
+// WithToken returns a ScopedClient which authenticates every request with the given token.
+func (client BugoutClient) WithToken(token string) ScopedClient {
+	return client.WithTokenSource(utils.NewStaticTokenSource(token))
+}
+
+// WithTokenSource returns a ScopedClient which authenticates every request with a token from the
+// given TokenSource.
+func (client BugoutClient) WithTokenSource(tokens utils.TokenSource) ScopedClient {
+	return ScopedClient{
+		Brood: brood.NewScopedClient(client.Brood, tokens),
+		Spire: spire.NewScopedClient(client.Spire, tokens),
+	}
+}
@@ -0,0 +1,61 @@
+package bugout_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+func TestClientFromEnvWithoutConfigFile(t *testing.T) {
+	values := map[string]string{
+		"HOME":                      "",
+		"XDG_CONFIG_HOME":           "",
+		"BUGOUT_CONFIG_FILE":        "",
+		"BUGOUT_PROFILE":            "",
+		"BUGOUT_SPIRE_URL":          "http://spire.test",
+		"BUGOUT_BROOD_URL":          "http://brood.test",
+		"BUGOUT_TIMEOUT_SECONDS":    "7",
+		"BUGOUT_RETRY_MAX_ATTEMPTS": "4",
+	}
+	for key, value := range values {
+		previous, wasSet := os.LookupEnv(key)
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+		key := key
+		defer func() {
+			if wasSet {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		}()
+	}
+
+	client, err := bugout.ClientFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	spireClient := client.Spire.(spire.SpireClient)
+	if spireClient.SpireURL != "http://spire.test" {
+		t.Errorf("Expected Spire URL from the environment, got %s", spireClient.SpireURL)
+	}
+	if spireClient.HTTPClient.Timeout != 7*time.Second || spireClient.Retry.MaxAttempts != 4 {
+		t.Errorf("Expected timeout and retries from the environment, got %s and %d", spireClient.HTTPClient.Timeout, spireClient.Retry.MaxAttempts)
+	}
+
+	broodClient := client.Brood.(brood.BroodClient)
+	if broodClient.BroodURL != "http://brood.test" {
+		t.Errorf("Expected Brood URL from the environment, got %s", broodClient.BroodURL)
+	}
+	if broodClient.HTTPClient.Timeout != 7*time.Second || broodClient.Retry.MaxAttempts != 4 {
+		t.Errorf("Expected timeout and retries from the environment, got %s and %d", broodClient.HTTPClient.Timeout, broodClient.Retry.MaxAttempts)
+	}
+}
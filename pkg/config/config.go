@@ -0,0 +1,211 @@
+// Package config manages the bugout configuration file, which holds named profiles of settings.
+//
+// Each profile maps the names of BUGOUT_* environment variables to values. Settings are resolved
+// in the following order of precedence:
+//  1. Command line flags (for the bugout tool)
+//  2. Environment variables
+//  3. The active profile in the configuration file
+//  4. Defaults
+//
+// The active profile is the one selected with UseProfile (the --profile flag of the bugout tool),
+// or else by the BUGOUT_PROFILE environment variable, or else by the current_profile setting in
+// the configuration file, or else the profile named "default".
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const EnvKeyProfile string = "BUGOUT_PROFILE"
+
+// Overrides the location of the configuration file
+const EnvKeyConfigFile string = "BUGOUT_CONFIG_FILE"
+
+const DefaultProfile string = "default"
+
+// Profile maps the names of BUGOUT_* environment variables to their values.
+type Profile map[string]string
+
+type Config struct {
+	CurrentProfile string             `json:"current_profile,omitempty"`
+	Profiles       map[string]Profile `json:"profiles"`
+}
+
+var (
+	profileOverrideMu sync.Mutex
+	profileOverride   string
+)
+
+// UseProfile makes the given profile active for the rest of the process, regardless of
+// BUGOUT_PROFILE and the current_profile setting. Passing an empty string removes the override.
+func UseProfile(name string) {
+	profileOverrideMu.Lock()
+	defer profileOverrideMu.Unlock()
+	profileOverride = name
+}
+
+// Path returns the location of the configuration file: $BUGOUT_CONFIG_FILE if it is set, and
+// otherwise bugout/config in the user's configuration directory (e.g. ~/.config/bugout/config).
+func Path() (string, error) {
+	if path := os.Getenv(EnvKeyConfigFile); path != "" {
+		return path, nil
+	}
+	configDir, configDirErr := os.UserConfigDir()
+	if configDirErr != nil {
+		return "", configDirErr
+	}
+	return filepath.Join(configDir, "bugout", "config"), nil
+}
+
+// Load reads the configuration file. A missing file is treated as an empty configuration, and so
+// is a configuration file whose location cannot be determined (e.g. because $HOME is not set, as is
+// common for containers and system services).
+func Load() (Config, error) {
+	path, pathErr := Path()
+	if pathErr != nil {
+		return Config{Profiles: map[string]Profile{}}, nil
+	}
+	return LoadFile(path)
+}
+
+func LoadFile(path string) (Config, error) {
+	config := Config{Profiles: map[string]Profile{}}
+	contents, readErr := ioutil.ReadFile(path)
+	if os.IsNotExist(readErr) {
+		return config, nil
+	} else if readErr != nil {
+		return config, readErr
+	}
+
+	decodeErr := json.Unmarshal(contents, &config)
+	if decodeErr != nil {
+		return config, fmt.Errorf("Could not parse configuration file (%s): %s", path, decodeErr.Error())
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	return config, nil
+}
+
+// Save writes the configuration file. Since profiles may contain access tokens, the file is only
+// readable by the current user.
+func (config Config) Save() error {
+	path, pathErr := Path()
+	if pathErr != nil {
+		return pathErr
+	}
+	return config.SaveFile(path)
+}
+
+func (config Config) SaveFile(path string) error {
+	contents, encodeErr := json.MarshalIndent(config, "", "  ")
+	if encodeErr != nil {
+		return encodeErr
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0700); mkdirErr != nil {
+		return mkdirErr
+	}
+	return ioutil.WriteFile(path, append(contents, '\n'), 0600)
+}
+
+// ActiveProfile returns the name of the active profile.
+func (config Config) ActiveProfile() string {
+	profileOverrideMu.Lock()
+	override := profileOverride
+	profileOverrideMu.Unlock()
+
+	if override != "" {
+		return override
+	}
+	if profile := os.Getenv(EnvKeyProfile); profile != "" {
+		return profile
+	}
+	if config.CurrentProfile != "" {
+		return config.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+// ProfileNames returns the names of all profiles, sorted.
+func (config Config) ProfileNames() []string {
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set stores a value in a profile, creating the profile if necessary. An empty value removes the
+// setting.
+func (config *Config) Set(profile, key, value string) {
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	if config.Profiles[profile] == nil {
+		config.Profiles[profile] = Profile{}
+	}
+	if value == "" {
+		delete(config.Profiles[profile], key)
+		return
+	}
+	config.Profiles[profile][key] = value
+}
+
+// Env resolves settings from environment variables, falling back to the active profile.
+type Env struct {
+	ProfileName string
+	Profile     Profile
+}
+
+// LoadEnv loads the configuration file and returns an Env for its active profile. An active profile
+// which does not exist is an error, unless it is the default profile.
+func LoadEnv() (Env, error) {
+	config, loadErr := Load()
+	if loadErr != nil {
+		return Env{}, loadErr
+	}
+	name := config.ActiveProfile()
+	profile, exists := config.Profiles[name]
+	if !exists && name != DefaultProfile {
+		return Env{}, fmt.Errorf("Profile not found in configuration file: %s", name)
+	}
+	return Env{ProfileName: name, Profile: profile}, nil
+}
+
+// Getenv returns the value of the environment variable if it is set, and otherwise the value from
+// the profile.
+func (env Env) Getenv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return env.Profile[key]
+}
+
+// Source describes where the value returned by Getenv comes from: "env", "profile" or "" if the
+// setting is not set at all.
+func (env Env) Source(key string) string {
+	if os.Getenv(key) != "" {
+		return "env"
+	}
+	if _, exists := env.Profile[key]; exists {
+		return "profile"
+	}
+	return ""
+}
+
+// Getenv is a shorthand for LoadEnv followed by Env.Getenv. If the configuration cannot be loaded,
+// only the environment is consulted.
+func Getenv(key string) string {
+	env, envErr := LoadEnv()
+	if envErr != nil {
+		return os.Getenv(key)
+	}
+	return env.Getenv(key)
+}
@@ -0,0 +1,94 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/config"
+)
+
+// Sets environment variables for the duration of the test. An empty value unsets the variable.
+func setenv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for key, value := range values {
+		previous, wasSet := os.LookupEnv(key)
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+		key := key
+		t.Cleanup(func() {
+			if wasSet {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutConfigDirectory(t *testing.T) {
+	setenv(t, map[string]string{
+		"HOME":                  "",
+		"XDG_CONFIG_HOME":       "",
+		"AppData":               "",
+		config.EnvKeyConfigFile: "",
+		config.EnvKeyProfile:    "",
+		"BUGOUT_SPIRE_URL":      "http://spire.test",
+	})
+	if _, pathErr := config.Path(); pathErr == nil {
+		t.Skip("The configuration directory can be determined without HOME on this platform")
+	}
+
+	loaded, loadErr := config.Load()
+	if loadErr != nil {
+		t.Fatalf("Unexpected error: %v", loadErr)
+	}
+	if len(loaded.Profiles) != 0 {
+		t.Errorf("Expected an empty configuration, got %#v", loaded)
+	}
+
+	env, envErr := config.LoadEnv()
+	if envErr != nil {
+		t.Fatalf("Unexpected error: %v", envErr)
+	}
+	if value := env.Getenv("BUGOUT_SPIRE_URL"); value != "http://spire.test" {
+		t.Errorf("Expected the setting from the environment, got %q", value)
+	}
+}
+
+func TestLoadEnvFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	contents := `{"current_profile": "staging", "profiles": {"staging": {"BUGOUT_SPIRE_URL": "http://staging.test", "BUGOUT_TIMEOUT_SECONDS": "10"}}}`
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	setenv(t, map[string]string{
+		config.EnvKeyConfigFile:  path,
+		config.EnvKeyProfile:     "",
+		"BUGOUT_SPIRE_URL":       "",
+		"BUGOUT_TIMEOUT_SECONDS": "5",
+	})
+
+	env, envErr := config.LoadEnv()
+	if envErr != nil {
+		t.Fatalf("Unexpected error: %v", envErr)
+	}
+	if env.ProfileName != "staging" {
+		t.Errorf("Expected the current profile, got %q", env.ProfileName)
+	}
+	if value := env.Getenv("BUGOUT_SPIRE_URL"); value != "http://staging.test" {
+		t.Errorf("Expected the setting from the profile, got %q", value)
+	}
+	if value := env.Getenv("BUGOUT_TIMEOUT_SECONDS"); value != "5" {
+		t.Errorf("Expected the environment to take precedence, got %q", value)
+	}
+
+	setenv(t, map[string]string{config.EnvKeyProfile: "missing"})
+	if _, err := config.LoadEnv(); err == nil {
+		t.Errorf("Expected an error for a missing profile")
+	}
+}
@@ -0,0 +1,293 @@
+package devserver
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+)
+
+type resourceRequest struct {
+	ApplicationId string      `json:"application_id"`
+	ResourceData  interface{} `json:"resource_data"`
+}
+
+type resourceUpdateRequest struct {
+	Update   interface{} `json:"update"`
+	DropKeys []string    `json:"drop_keys"`
+}
+
+// Routes:
+//
+//	GET /ping
+//	GET /version
+//	GET /auth
+//	GET, POST /user
+//	GET /user/find
+//	POST, PUT /token
+//	GET /tokens
+//	DELETE /revoke/{token}
+//	POST /confirm
+//	POST /profile/password
+//	POST /reset
+//	POST /reset_password
+//	GET, POST /groups
+//	GET, DELETE /groups/{groupID}
+//	GET /groups/{groupID}/users
+//	POST /groups/{groupID}/name
+//	POST, DELETE /groups/{groupID}/role
+//	GET, POST /applications
+//	GET, PUT, DELETE /applications/{applicationID}
+//	POST /applications/{applicationID}/group
+//	GET /applications/{applicationID}/users
+//	GET, POST /resources
+//	GET, PUT, DELETE /resources/{resourceID}
+//	GET, POST, DELETE /resources/{resourceID}/holders
+func (server *Server) serveBrood(w http.ResponseWriter, r *http.Request, segments []string) {
+	fake := server.Brood
+	token := bearerToken(r)
+
+	// Most Brood endpoints accept form-encoded bodies.
+	var form url.Values
+	if r.Method != "GET" && r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		values, formErr := formValues(r)
+		if formErr != nil {
+			writeDetail(w, http.StatusBadRequest, formErr.Error())
+			return
+		}
+		form = values
+	}
+
+	route := ""
+	if len(segments) > 0 {
+		route = segments[0]
+	}
+
+	switch {
+	case len(segments) == 1 && route == "ping":
+		ping, err := fake.Ping()
+		writeRaw(w, ping, err)
+
+	case len(segments) == 1 && route == "version":
+		version, err := fake.Version()
+		writeRaw(w, version, err)
+
+	case len(segments) == 1 && route == "auth":
+		user, err := fake.Auth(token)
+		respond(w, user, err)
+
+	case len(segments) == 1 && route == "user":
+		switch r.Method {
+		case "GET":
+			user, err := fake.GetUser(token)
+			respond(w, user, err)
+		case "POST":
+			if applicationID := form.Get("application_id"); applicationID != "" {
+				user, err := fake.CreateApplicationUser(applicationID, token, form.Get("username"), form.Get("email"), form.Get("password"))
+				respond(w, user, err)
+				return
+			}
+			user, err := fake.CreateUser(form.Get("username"), form.Get("email"), form.Get("password"))
+			respond(w, user, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 2 && route == "user" && segments[1] == "find":
+		user, err := fake.FindUser(token, queryParameters(r))
+		respond(w, user, err)
+
+	case len(segments) == 1 && route == "token":
+		switch r.Method {
+		case "POST":
+			var accessToken string
+			var err error
+			if applicationID := form.Get("application_id"); applicationID != "" {
+				accessToken, err = fake.GenerateApplicationToken(applicationID, token, form.Get("username"), form.Get("password"))
+			} else {
+				accessToken, err = fake.GenerateToken(form.Get("username"), form.Get("password"))
+			}
+			respond(w, brood.UserGeneratedToken{Id: accessToken, TokenType: "bugout"}, err)
+		case "PUT":
+			accessToken, err := fake.AnnotateToken(form.Get("access_token"), form.Get("token_type"), form.Get("token_note"))
+			respond(w, accessToken, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 1 && route == "tokens":
+		tokens, err := fake.ListTokens(token)
+		respond(w, tokens, err)
+
+	case len(segments) == 2 && route == "revoke":
+		if r.Method != "DELETE" {
+			methodNotAllowed(w)
+			return
+		}
+		revoked, err := fake.RevokeToken(token, segments[1])
+		respond(w, revoked, err)
+
+	case len(segments) == 1 && route == "confirm":
+		user, err := fake.VerifyUser(token, form.Get("verification_code"))
+		respond(w, user, err)
+
+	case len(segments) == 2 && route == "profile" && segments[1] == "password":
+		user, err := fake.ChangePassword(token, form.Get("current_password"), form.Get("new_password"))
+		respond(w, user, err)
+
+	case len(segments) == 1 && route == "reset":
+		body, err := fake.RequestPasswordReset(form.Get("email"))
+		writeRaw(w, body, err)
+
+	case len(segments) == 1 && route == "reset_password":
+		user, err := fake.ConfirmPasswordReset(form.Get("reset_id"), form.Get("new_password"))
+		respond(w, user, err)
+
+	case len(segments) == 1 && route == "groups":
+		switch r.Method {
+		case "GET":
+			groups, err := fake.GetUserGroups(token)
+			respond(w, groups, err)
+		case "POST":
+			group, err := fake.CreateGroup(token, form.Get("group_name"))
+			respond(w, group, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 2 && route == "groups":
+		switch r.Method {
+		case "GET":
+			group, err := fake.GetGroup(token, segments[1])
+			respond(w, group, err)
+		case "DELETE":
+			group, err := fake.DeleteGroup(token, segments[1])
+			respond(w, group, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 3 && route == "groups" && segments[2] == "users":
+		members, err := fake.ListGroupMembers(token, segments[1])
+		respond(w, members, err)
+
+	case len(segments) == 3 && route == "groups" && segments[2] == "name":
+		group, err := fake.RenameGroup(token, segments[1], form.Get("group_name"))
+		respond(w, group, err)
+
+	case len(segments) == 3 && route == "groups" && segments[2] == "role":
+		switch r.Method {
+		case "POST":
+			membership, err := fake.AddUserToGroup(token, segments[1], form.Get("username"), form.Get("user_type"))
+			respond(w, membership, err)
+		case "DELETE":
+			membership, err := fake.RemoveUserFromGroup(token, segments[1], form.Get("username"))
+			respond(w, membership, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 1 && route == "applications":
+		switch r.Method {
+		case "GET":
+			applications, err := fake.ListApplications(token, r.URL.Query().Get("group_id"))
+			respond(w, applications, err)
+		case "POST":
+			application, err := fake.CreateApplication(token, form.Get("group_id"), form.Get("name"), form.Get("description"))
+			respond(w, application, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 2 && route == "applications":
+		switch r.Method {
+		case "GET":
+			application, err := fake.GetApplication(token, segments[1])
+			respond(w, application, err)
+		case "PUT":
+			application, err := fake.UpdateApplication(token, segments[1], form.Get("name"), form.Get("description"))
+			respond(w, application, err)
+		case "DELETE":
+			application, err := fake.DeleteApplication(token, segments[1])
+			respond(w, application, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 3 && route == "applications" && segments[2] == "group":
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		application, err := fake.TransferApplication(token, segments[1], form.Get("group_id"))
+		respond(w, application, err)
+
+	case len(segments) == 3 && route == "applications" && segments[2] == "users":
+		users, err := fake.ListApplicationUsers(token, segments[1])
+		respond(w, users, err)
+
+	case len(segments) == 1 && route == "resources":
+		switch r.Method {
+		case "GET":
+			resources, err := fake.GetResources(token, r.URL.Query().Get("application_id"), queryParameters(r, "application_id"))
+			respond(w, resources, err)
+		case "POST":
+			var body resourceRequest
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+			resource, err := fake.CreateResource(token, body.ApplicationId, body.ResourceData)
+			respond(w, resource, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 2 && route == "resources":
+		resourceID := segments[1]
+		switch r.Method {
+		case "GET":
+			resource, err := fake.GetResource(token, resourceID)
+			respond(w, resource, err)
+		case "PUT":
+			var body resourceUpdateRequest
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+			resource, err := fake.UpdateResource(token, resourceID, body.Update, body.DropKeys)
+			respond(w, resource, err)
+		case "DELETE":
+			resource, err := fake.DeleteResource(token, resourceID)
+			respond(w, resource, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 3 && route == "resources" && segments[2] == "holders":
+		resourceID := segments[1]
+		var holder brood.ResourceHolder
+		if r.Method == "POST" || r.Method == "DELETE" {
+			if decodeErr := decodeJSON(r, &holder); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+		}
+		switch r.Method {
+		case "GET":
+			holders, err := fake.GetResourceHolders(token, resourceID)
+			respond(w, holders, err)
+		case "POST":
+			holders, err := fake.AddResourceHolderPermissions(token, resourceID, holder)
+			respond(w, holders, err)
+		case "DELETE":
+			holders, err := fake.DeleteResourceHolderPermissions(token, resourceID, holder)
+			respond(w, holders, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	default:
+		notFound(w)
+	}
+}
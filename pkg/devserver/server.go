@@ -0,0 +1,255 @@
+// Package devserver emulates the Spire and Brood APIs locally, on top of the in-memory fakes from
+// the spiretest and broodtest packages. Point BUGOUT_SPIRE_URL and BUGOUT_BROOD_URL at it to use
+// it in place of https://spire.bugout.dev and https://auth.bugout.dev.
+package devserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood/broodtest"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// Server serves the Spire and Brood APIs. Spire authenticates tokens against Brood, so users,
+// tokens and groups created through the Brood API can be used with Spire.
+type Server struct {
+	Brood *broodtest.Fake
+	Spire *spiretest.Fake
+	// If StatePath is not empty, the state of the server is written to this file after every
+	// request which changes it.
+	StatePath string
+
+	saveMu sync.Mutex
+}
+
+// persistedState is the format of the file at StatePath.
+type persistedState struct {
+	Brood json.RawMessage `json:"brood"`
+	Spire json.RawMessage `json:"spire"`
+}
+
+// New creates a server. If statePath is not empty and the file exists, the server starts from the
+// state saved in it.
+func New(statePath string) (*Server, error) {
+	server := &Server{
+		Brood:     broodtest.NewFake(),
+		Spire:     spiretest.NewFake(),
+		StatePath: statePath,
+	}
+	server.Spire.Authenticator = server.Brood
+
+	if statePath == "" {
+		return server, nil
+	}
+	contents, readErr := ioutil.ReadFile(statePath)
+	if os.IsNotExist(readErr) {
+		return server, nil
+	} else if readErr != nil {
+		return nil, readErr
+	}
+
+	var state persistedState
+	decodeErr := json.Unmarshal(contents, &state)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	if len(state.Brood) > 0 {
+		if loadErr := server.Brood.LoadState(bytes.NewReader(state.Brood)); loadErr != nil {
+			return nil, loadErr
+		}
+	}
+	if len(state.Spire) > 0 {
+		if loadErr := server.Spire.LoadState(bytes.NewReader(state.Spire)); loadErr != nil {
+			return nil, loadErr
+		}
+	}
+	return server, nil
+}
+
+// Save writes the state of the server to StatePath. It does nothing if StatePath is empty.
+func (server *Server) Save() error {
+	if server.StatePath == "" {
+		return nil
+	}
+	server.saveMu.Lock()
+	defer server.saveMu.Unlock()
+
+	var broodState, spireState bytes.Buffer
+	if saveErr := server.Brood.SaveState(&broodState); saveErr != nil {
+		return saveErr
+	}
+	if saveErr := server.Spire.SaveState(&spireState); saveErr != nil {
+		return saveErr
+	}
+	contents, encodeErr := json.Marshal(persistedState{Brood: broodState.Bytes(), Spire: spireState.Bytes()})
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	// Write to a temporary file first, so that a crash never leaves a truncated state file behind.
+	temporaryFile, tempErr := ioutil.TempFile(filepath.Dir(server.StatePath), ".devserver-state-")
+	if tempErr != nil {
+		return tempErr
+	}
+	defer os.Remove(temporaryFile.Name())
+	if _, writeErr := temporaryFile.Write(contents); writeErr != nil {
+		temporaryFile.Close()
+		return writeErr
+	}
+	if closeErr := temporaryFile.Close(); closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(temporaryFile.Name(), server.StatePath)
+}
+
+// Handler serves both APIs from the same origin, so that BUGOUT_SPIRE_URL and BUGOUT_BROOD_URL may
+// both point at it. Their routes do not overlap, except for /ping.
+func (server *Server) Handler() http.Handler {
+	return server.persisting(func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r.URL.Path)
+		if len(segments) > 0 && segments[0] == "journals" {
+			server.serveSpire(w, r, segments)
+			return
+		}
+		server.serveBrood(w, r, segments)
+	})
+}
+
+// SpireHandler serves only the Spire API.
+func (server *Server) SpireHandler() http.Handler {
+	return server.persisting(func(w http.ResponseWriter, r *http.Request) {
+		server.serveSpire(w, r, pathSegments(r.URL.Path))
+	})
+}
+
+// BroodHandler serves only the Brood API.
+func (server *Server) BroodHandler() http.Handler {
+	return server.persisting(func(w http.ResponseWriter, r *http.Request) {
+		server.serveBrood(w, r, pathSegments(r.URL.Path))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (recorder *statusRecorder) WriteHeader(statusCode int) {
+	recorder.statusCode = statusCode
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Saves the state after every successful request which may have changed it.
+func (server *Server) persisting(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		handler(recorder, r)
+		if r.Method == "GET" || r.Method == "HEAD" || recorder.statusCode >= 400 {
+			return
+		}
+		if saveErr := server.Save(); saveErr != nil {
+			// The response has already been written, so the best we can do is report the failure.
+			os.Stderr.WriteString("devserver: could not save state: " + saveErr.Error() + "\n")
+		}
+	})
+}
+
+func pathSegments(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return []string{}
+	}
+	return strings.Split(trimmed, "/")
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeDetail(w http.ResponseWriter, statusCode int, detail string) {
+	writeJSON(w, statusCode, map[string]string{"detail": detail})
+}
+
+// Writes the result of a fake method call, translating API errors into responses with the same
+// status code and detail.
+func respond(w http.ResponseWriter, value interface{}, err error) {
+	if err != nil {
+		var apiErr *utils.APIError
+		if errors.As(err, &apiErr) {
+			writeDetail(w, apiErr.StatusCode, apiErr.Detail)
+			return
+		}
+		writeDetail(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, value)
+}
+
+// Writes the plain string results of Ping and Version as they are.
+func writeRaw(w http.ResponseWriter, body string, err error) {
+	if err != nil {
+		respond(w, nil, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+func notFound(w http.ResponseWriter) {
+	writeDetail(w, http.StatusNotFound, "Not Found")
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeDetail(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+}
+
+func bearerToken(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// Parses a form-encoded request body. Unlike http.Request.ParseForm, this also handles DELETE
+// requests, which Brood accepts form bodies on.
+func formValues(r *http.Request) (url.Values, error) {
+	body, readErr := ioutil.ReadAll(r.Body)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return url.ParseQuery(string(body))
+}
+
+func decodeJSON(r *http.Request, value interface{}) error {
+	return json.NewDecoder(r.Body).Decode(value)
+}
+
+// Returns every query parameter other than the excluded ones, as the fakes expect them.
+func queryParameters(r *http.Request, excluded ...string) map[string]string {
+	parameters := map[string]string{}
+	for key, values := range r.URL.Query() {
+		isExcluded := false
+		for _, excludedKey := range excluded {
+			if key == excludedKey {
+				isExcluded = true
+			}
+		}
+		if !isExcluded && len(values) > 0 {
+			parameters[key] = values[0]
+		}
+	}
+	return parameters
+}
@@ -0,0 +1,209 @@
+package devserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+type journalRequest struct {
+	Name string `json:"name"`
+}
+
+type journalScopesRequest struct {
+	HolderID    string   `json:"holder_id"`
+	HolderType  string   `json:"holder_type"`
+	Permissions []string `json:"permission_list"`
+}
+
+type entryRequest struct {
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags"`
+	ContextType string   `json:"context_type"`
+	ContextURL  string   `json:"context_url"`
+	ContextID   string   `json:"context_id"`
+}
+
+type entryTagsRequest struct {
+	Tags []string `json:"tags"`
+	Tag  string   `json:"tag"`
+}
+
+// Routes:
+//
+//	GET /ping
+//	GET, POST /journals
+//	GET, PUT, DELETE /journals/{journalID}
+//	GET, POST, DELETE /journals/{journalID}/scopes
+//	GET /journals/{journalID}/permissions
+//	POST /journals/{journalID}/entries
+//	GET, PUT, DELETE /journals/{journalID}/entries/{entryID}
+//	POST, DELETE /journals/{journalID}/entries/{entryID}/tags
+//	GET /journals/{journalID}/search
+func (server *Server) serveSpire(w http.ResponseWriter, r *http.Request, segments []string) {
+	fake := server.Spire
+	token := bearerToken(r)
+
+	switch {
+	case len(segments) == 1 && segments[0] == "ping":
+		ping, err := fake.Ping()
+		writeRaw(w, ping, err)
+
+	case len(segments) == 0 || segments[0] != "journals":
+		notFound(w)
+
+	case len(segments) == 1:
+		switch r.Method {
+		case "GET":
+			journals, err := fake.ListJournals(token)
+			respond(w, journals, err)
+		case "POST":
+			var body journalRequest
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+			journal, err := fake.CreateJournal(token, body.Name)
+			respond(w, journal, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 2:
+		journalID := segments[1]
+		switch r.Method {
+		case "GET":
+			journal, err := fake.GetJournal(token, journalID)
+			respond(w, journal, err)
+		case "PUT":
+			var body journalRequest
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+			journal, err := fake.UpdateJournal(token, journalID, body.Name)
+			respond(w, journal, err)
+		case "DELETE":
+			journal, err := fake.DeleteJournal(token, journalID)
+			respond(w, journal, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 3 && segments[2] == "scopes":
+		var body journalScopesRequest
+		if r.Method == "POST" || r.Method == "DELETE" {
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+		}
+		switch r.Method {
+		case "GET":
+			scopes, err := fake.ListJournalMembers(token, segments[1])
+			respond(w, scopes, err)
+		case "POST":
+			scopes, err := fake.AddJournalMember(token, segments[1], body.HolderID, body.HolderType, body.Permissions)
+			respond(w, scopes, err)
+		case "DELETE":
+			scopes, err := fake.RemoveJournalMember(token, segments[1], body.HolderID, body.HolderType, body.Permissions)
+			respond(w, scopes, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 3 && segments[2] == "permissions":
+		if r.Method != "GET" {
+			methodNotAllowed(w)
+			return
+		}
+		userPermissions := spire.JournalUserPermissions{JournalID: segments[1], Permissions: []string{}}
+		for _, permission := range spire.ValidJournalPermissions() {
+			held, err := fake.CheckJournalPermission(token, segments[1], permission)
+			if err != nil {
+				respond(w, nil, err)
+				return
+			}
+			if held {
+				userPermissions.Permissions = append(userPermissions.Permissions, permission)
+			}
+		}
+		respond(w, userPermissions, nil)
+
+	case len(segments) == 3 && segments[2] == "entries":
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		var body entryRequest
+		if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+			writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+			return
+		}
+		entryContext := spire.EntryContext{ContextType: body.ContextType, ContextID: body.ContextID, ContextURL: body.ContextURL}
+		entry, err := fake.CreateEntry(token, segments[1], body.Title, body.Content, body.Tags, entryContext)
+		respond(w, entry, err)
+
+	case len(segments) == 3 && segments[2] == "search":
+		if r.Method != "GET" {
+			methodNotAllowed(w)
+			return
+		}
+		query := r.URL.Query()
+		limit, offset := 10, 0
+		if limitRaw := query.Get("limit"); limitRaw != "" {
+			limit, _ = strconv.Atoi(limitRaw)
+		}
+		if offsetRaw := query.Get("offset"); offsetRaw != "" {
+			offset, _ = strconv.Atoi(offsetRaw)
+		}
+		page, err := fake.SearchEntries(token, segments[1], query.Get("q"), limit, offset, queryParameters(r, "q", "limit", "offset"))
+		respond(w, page, err)
+
+	case len(segments) == 4 && segments[2] == "entries":
+		journalID, entryID := segments[1], segments[3]
+		switch r.Method {
+		case "GET":
+			entry, err := fake.GetEntry(token, journalID, entryID)
+			respond(w, entry, err)
+		case "PUT":
+			var body entryRequest
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+			entry, err := fake.ReplaceEntry(token, journalID, entryID, body.Title, body.Content)
+			respond(w, entry, err)
+		case "DELETE":
+			entry, err := fake.DeleteEntry(token, journalID, entryID)
+			respond(w, entry, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	case len(segments) == 5 && segments[2] == "entries" && segments[4] == "tags":
+		journalID, entryID := segments[1], segments[3]
+		var body entryTagsRequest
+		if r.Method == "POST" || r.Method == "DELETE" {
+			if decodeErr := decodeJSON(r, &body); decodeErr != nil {
+				writeDetail(w, http.StatusBadRequest, decodeErr.Error())
+				return
+			}
+		}
+		switch r.Method {
+		case "POST":
+			entry, err := fake.TagEntry(token, journalID, entryID, body.Tags)
+			respond(w, entry, err)
+		case "DELETE":
+			entry, err := fake.UntagEntry(token, journalID, entryID, []string{body.Tag})
+			respond(w, entry, err)
+		default:
+			methodNotAllowed(w)
+		}
+
+	default:
+		notFound(w)
+	}
+}
@@ -0,0 +1,168 @@
+package journalsync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"gopkg.in/yaml.v2"
+)
+
+const frontMatterDelimiter = "---"
+
+// The keys allowed in the front matter. ParseDocument rejects any others.
+type frontMatterFields struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Tags        []string `yaml:"tags"`
+	ContextType string   `yaml:"context_type"`
+	ContextID   string   `yaml:"context_id"`
+	ContextURL  string   `yaml:"context_url"`
+}
+
+// Document is the local representation of a journal entry: a Markdown file whose YAML front matter
+// holds the entry's ID, title, tags and context, followed by the entry's content.
+//
+//	---
+//	id: 9c1c5e8a-...
+//	title: "Deploying to production"
+//	tags:
+//	  - runbook
+//	  - deploy
+//	---
+//	The content of the entry, in Markdown.
+//
+// Files for new entries may leave out the ID. The front matter may use any YAML syntax, but only the
+// keys above (and context_type, context_id and context_url) are allowed.
+type Document struct {
+	ID          string
+	Title       string
+	Tags        []string
+	ContextType string
+	ContextID   string
+	ContextURL  string
+	Content     string
+}
+
+func DocumentFromEntry(entry spire.Entry) Document {
+	return Document{
+		ID:          entry.Id,
+		Title:       entry.Title,
+		Tags:        append([]string{}, entry.Tags...),
+		ContextType: entry.ContextType,
+		ContextID:   entry.ContextID,
+		ContextURL:  entry.ContextUrl,
+		Content:     entry.Content,
+	}
+}
+
+// Hash identifies the parts of a document which are synchronized: its title, tags and content.
+// Line endings (ParseDocument turns CRLF into LF) and trailing newlines in the content and the
+// order of the tags do not affect the hash.
+func (doc Document) Hash() string {
+	tags := append([]string{}, doc.Tags...)
+	sort.Strings(tags)
+	content := strings.TrimRight(strings.Replace(doc.Content, "\r\n", "\n", -1), "\n")
+	encoded, _ := json.Marshal([]interface{}{doc.Title, tags, content})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Render returns the contents of the Markdown file for the document.
+func (doc Document) Render() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(frontMatterDelimiter + "\n")
+	if doc.ID != "" {
+		buffer.WriteString("id: " + quote(doc.ID) + "\n")
+	}
+	buffer.WriteString("title: " + quote(doc.Title) + "\n")
+	if len(doc.Tags) == 0 {
+		buffer.WriteString("tags: []\n")
+	} else {
+		buffer.WriteString("tags:\n")
+		for _, tag := range doc.Tags {
+			buffer.WriteString("  - " + quote(tag) + "\n")
+		}
+	}
+	for _, field := range []struct{ key, value string }{
+		{"context_type", doc.ContextType},
+		{"context_id", doc.ContextID},
+		{"context_url", doc.ContextURL},
+	} {
+		if field.value != "" {
+			buffer.WriteString(field.key + ": " + quote(field.value) + "\n")
+		}
+	}
+	buffer.WriteString(frontMatterDelimiter + "\n")
+	buffer.WriteString(doc.Content)
+	if doc.Content != "" && !strings.HasSuffix(doc.Content, "\n") {
+		buffer.WriteString("\n")
+	}
+	return buffer.Bytes()
+}
+
+// ParseDocument parses the contents of a Markdown file with front matter.
+func ParseDocument(contents []byte) (Document, error) {
+	text := strings.Replace(string(contents), "\r\n", "\n", -1)
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return Document{}, fmt.Errorf("File does not start with front matter (%s)", frontMatterDelimiter)
+	}
+	rest := text[len(frontMatterDelimiter)+1:]
+
+	frontMatter := []string{}
+	closed := false
+	for rest != "" {
+		line := rest
+		if newline := strings.Index(rest, "\n"); newline >= 0 {
+			line, rest = rest[:newline], rest[newline+1:]
+		} else {
+			rest = ""
+		}
+		if line == frontMatterDelimiter {
+			closed = true
+			break
+		}
+		frontMatter = append(frontMatter, line)
+	}
+	if !closed {
+		return Document{}, fmt.Errorf("Front matter is not closed (%s)", frontMatterDelimiter)
+	}
+
+	// The leading newline stands in for the opening delimiter, so that the line numbers in YAML
+	// errors are those of the file.
+	var fields frontMatterFields
+	if yamlErr := yaml.UnmarshalStrict([]byte("\n"+strings.Join(frontMatter, "\n")+"\n"), &fields); yamlErr != nil {
+		return Document{}, fmt.Errorf("Invalid front matter: %s", yamlErr.Error())
+	}
+	if fields.Title == "" {
+		return Document{}, fmt.Errorf("Front matter does not set a title")
+	}
+
+	doc := Document{
+		ID:          fields.ID,
+		Title:       fields.Title,
+		Tags:        fields.Tags,
+		ContextType: fields.ContextType,
+		ContextID:   fields.ContextID,
+		ContextURL:  fields.ContextURL,
+		Content:     rest,
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	return doc, nil
+}
+
+// Double-quoted JSON strings are valid double-quoted YAML scalars.
+func quote(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,192 @@
+package journalsync_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/journalsync"
+)
+
+func TestParseDocument(t *testing.T) {
+	cases := []struct {
+		name     string
+		contents string
+		expected journalsync.Document
+	}{
+		{
+			name:     "plain values",
+			contents: "---\nid: 1234\ntitle: Deploying to production\ntags:\n  - runbook\n  - deploy\n---\nContent\n",
+			expected: journalsync.Document{ID: "1234", Title: "Deploying to production", Tags: []string{"runbook", "deploy"}, Content: "Content\n"},
+		},
+		{
+			name:     "double-quoted title",
+			contents: "---\ntitle: \"Say \\\"hello\\\": a \\u00e9 tab\\there\"\ntags: []\n---\n",
+			expected: journalsync.Document{Title: "Say \"hello\": a é tab\there", Tags: []string{}},
+		},
+		{
+			name:     "single-quoted title",
+			contents: "---\ntitle: 'It''s: #1'\n---\n",
+			expected: journalsync.Document{Title: "It's: #1", Tags: []string{}},
+		},
+		{
+			name:     "tags with colons and hashes",
+			contents: "---\ntitle: t\ntags:\n  - os:linux\n  - \"key: value\"\n  - 'c#'\n  - \"#urgent\"\n---\n",
+			expected: journalsync.Document{Title: "t", Tags: []string{"os:linux", "key: value", "c#", "#urgent"}},
+		},
+		{
+			name:     "flow sequence of tags",
+			contents: "---\ntitle: t\ntags: [os:linux, \"#urgent\", 'key: value']\n---\n",
+			expected: journalsync.Document{Title: "t", Tags: []string{"os:linux", "#urgent", "key: value"}},
+		},
+		{
+			name:     "context and comments",
+			contents: "---\n# A comment\ntitle: t\n\ncontext_type: github\ncontext_id: \"bugout-dev/bugout-go#12\"\ncontext_url: https://github.com/bugout-dev/bugout-go/issues/12\n---\n",
+			expected: journalsync.Document{
+				Title:       "t",
+				Tags:        []string{},
+				ContextType: "github",
+				ContextID:   "bugout-dev/bugout-go#12",
+				ContextURL:  "https://github.com/bugout-dev/bugout-go/issues/12",
+			},
+		},
+		{
+			name:     "comments and quoted colons",
+			contents: "---\ntitle: \"Incident: database # 2\" # the title\n# tags: [ignored]\ntags: ['a: b'] # trailing comment\n---\n",
+			expected: journalsync.Document{Title: "Incident: database # 2", Tags: []string{"a: b"}},
+		},
+		{
+			name:     "block scalars",
+			contents: "---\ntitle: >-\n  A title folded\n  over two lines\ncontext_id: |\n  line one\n  line two\n---\n",
+			expected: journalsync.Document{Title: "A title folded over two lines", Tags: []string{}, ContextID: "line one\nline two\n"},
+		},
+		{
+			name:     "non-string scalars",
+			contents: "---\nid: 1234\ntitle: yes\ntags: [1.5, true, null]\n---\n",
+			expected: journalsync.Document{ID: "1234", Title: "yes", Tags: []string{"1.5", "true", ""}},
+		},
+		{
+			name:     "CRLF line endings",
+			contents: "---\r\nid: 1234\r\ntitle: \"Windows\"\r\ntags:\r\n  - a\r\n---\r\nFirst line\r\nSecond line\r\n",
+			expected: journalsync.Document{ID: "1234", Title: "Windows", Tags: []string{"a"}, Content: "First line\nSecond line\n"},
+		},
+		{
+			name:     "delimiter in content",
+			contents: "---\ntitle: t\n---\nAbove\n---\nBelow",
+			expected: journalsync.Document{Title: "t", Tags: []string{}, Content: "Above\n---\nBelow"},
+		},
+		{
+			name:     "closing delimiter at end of file",
+			contents: "---\ntitle: t\n---",
+			expected: journalsync.Document{Title: "t", Tags: []string{}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			doc, err := journalsync.ParseDocument([]byte(c.contents))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(doc, c.expected) {
+				t.Errorf("Expected %#v, got %#v", c.expected, doc)
+			}
+		})
+	}
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		contents string
+		message  string
+	}{
+		{"no front matter", "title: t\n", "does not start with front matter"},
+		{"missing closing delimiter", "---\ntitle: t\nContent\n", "Front matter is not closed"},
+		{"missing closing delimiter with CRLF", "---\r\ntitle: t\r\n--- \r\nContent\r\n", "Front matter is not closed"},
+		{"empty file", "", "does not start with front matter"},
+		{"missing title", "---\ntags: [a]\n---\n", "does not set a title"},
+		{"unknown key", "---\ntitle: t\nauthor: me\n---\n", "line 3: field author not found"},
+		{"duplicate key", "---\ntitle: t\ntitle: u\n---\n", "line 3: field title already set"},
+		{"indented key", "---\ntitle: t\n  id: 1\n---\n", "Invalid front matter: yaml: line 3"},
+		{"line without key", "---\ntitle: t\njust text\n---\n", "Invalid front matter: yaml: line 4"},
+		{"unterminated double quote", "---\ntitle: \"t\n---\n", "Invalid front matter"},
+		{"unterminated single quote", "---\ntitle: 't\n---\n", "Invalid front matter"},
+		{"tags which are not a list", "---\ntitle: t\ntags: a\n---\n", "line 3: cannot unmarshal !!str `a` into []string"},
+		{"tags which are not strings", "---\ntitle: t\ntags:\n  - {a: b}\n---\n", "cannot unmarshal !!map into string"},
+		{"front matter which is not a mapping", "---\n- title\n---\n", "Invalid front matter"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := journalsync.ParseDocument([]byte(c.contents))
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if !strings.Contains(err.Error(), c.message) {
+				t.Errorf("Expected error containing %q, got %q", c.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestDocumentRenderRoundTrip(t *testing.T) {
+	docs := []journalsync.Document{
+		{ID: "1234", Title: "Plain", Tags: []string{}, Content: "Content\n"},
+		{Title: "No ID, no content", Tags: []string{}},
+		{
+			ID:          "5678",
+			Title:       "Quotes \" and ' and: colons # hashes\tand tabs",
+			Tags:        []string{"os:linux", "#urgent", "key: value", "- dash", "'quoted'", "[bracket]"},
+			ContextType: "github",
+			ContextID:   "bugout-dev/bugout-go#12",
+			ContextURL:  "https://github.com/bugout-dev/bugout-go/issues/12?a=1&b=2",
+			Content:     "---\nLooks like front matter\n---\n",
+		},
+		{Title: "<html> & unicode é☃", Tags: []string{"é"}, Content: "Line\r\n"},
+	}
+
+	for _, doc := range docs {
+		t.Run(doc.Title, func(t *testing.T) {
+			parsed, err := journalsync.ParseDocument(doc.Render())
+			if err != nil {
+				t.Fatalf("Could not parse rendered document: %v\n%s", err, doc.Render())
+			}
+			// ParseDocument turns CRLF line endings into LF.
+			expected := doc
+			expected.Content = strings.Replace(doc.Content, "\r\n", "\n", -1)
+			if !reflect.DeepEqual(parsed, expected) {
+				t.Errorf("Expected %#v, got %#v", expected, parsed)
+			}
+			if parsed.Hash() != doc.Hash() {
+				t.Errorf("Hash changed on round trip")
+			}
+		})
+	}
+}
+
+func TestDocumentHash(t *testing.T) {
+	base := journalsync.Document{Title: "t", Tags: []string{"a", "b"}, Content: "Content"}
+
+	same := []journalsync.Document{
+		{ID: "other", Title: "t", Tags: []string{"b", "a"}, Content: "Content\n\n", ContextType: "github"},
+		{Title: "t", Tags: []string{"a", "b"}, Content: "Content\r\n"},
+	}
+	for _, doc := range same {
+		if doc.Hash() != base.Hash() {
+			t.Errorf("Expected %#v to have the same hash as %#v", doc, base)
+		}
+	}
+
+	different := []journalsync.Document{
+		{Title: "T", Tags: []string{"a", "b"}, Content: "Content"},
+		{Title: "t", Tags: []string{"a"}, Content: "Content"},
+		{Title: "t", Tags: []string{"a", "b"}, Content: ""},
+		{Title: "t", Tags: []string{"a", "b"}, Content: " Content"},
+	}
+	for _, doc := range different {
+		if doc.Hash() == base.Hash() {
+			t.Errorf("Expected %#v to have a different hash from %#v", doc, base)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package journalsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+// Name of the file, in the synchronized directory, which records the state of the last sync
+const StateFileName string = ".bugout-sync.json"
+
+// Actions reported by Sync
+const (
+	// The entry was written to its file
+	ActionPull string = "pull"
+	// The entry was updated from its file
+	ActionPush string = "push"
+	// An entry was created from a file without an ID
+	ActionCreate string = "create"
+	// The entry was deleted from the journal, so its file was deleted
+	ActionDeleteLocal string = "delete-local"
+	// The entry and its file both changed since the last sync. Neither was modified.
+	ActionConflict string = "conflict"
+	// The file could not be synchronized (for example, because it could not be parsed)
+	ActionSkip string = "skip"
+)
+
+// Client is the part of the Spire API which Sync uses. spire.SpireClient implements it.
+type Client interface {
+	spire.SpireCallerContext
+	spire.EntryReplaceCallerContext
+	spire.EntryTagsCallerContext
+}
+
+type Options struct {
+	// If true, the changes are reported but not made
+	DryRun bool
+}
+
+// Change describes something Sync did (or, for a dry run, would do) to an entry or a file.
+type Change struct {
+	Action  string `json:"action"`
+	EntryID string `json:"entry_id,omitempty"`
+	File    string `json:"file,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	// Set if the change could not be made
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Changes []Change `json:"changes"`
+	// Number of entries which were already in sync with their files
+	Unchanged int `json:"unchanged"`
+}
+
+// Conflicts returns the number of conflicting and skipped entries and files.
+func (report Report) Conflicts() int {
+	conflicts := 0
+	for _, change := range report.Changes {
+		if change.Action == ActionConflict || change.Action == ActionSkip {
+			conflicts++
+		}
+	}
+	return conflicts
+}
+
+// Errors returns the number of changes which could not be made.
+func (report Report) Errors() int {
+	errors := 0
+	for _, change := range report.Changes {
+		if change.Error != "" {
+			errors++
+		}
+	}
+	return errors
+}
+
+type syncState struct {
+	JournalID string                 `json:"journal_id"`
+	Entries   map[string]syncedEntry `json:"entries"`
+}
+
+// The state of an entry and its file as of the last sync
+type syncedEntry struct {
+	File      string    `json:"file"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Hash      string    `json:"hash"`
+}
+
+type localFile struct {
+	name string
+	doc  Document
+	hash string
+}
+
+type syncer struct {
+	ctx       context.Context
+	client    Client
+	token     string
+	journalID string
+	dir       string
+	options   Options
+	state     syncState
+	report    Report
+	// Names of the files in the directory, including those created during the sync
+	names map[string]bool
+}
+
+// Sync brings a journal and a directory of Markdown files (see Document) into agreement. Each entry
+// in the journal corresponds to a file in the directory, identified by the ID in its front matter.
+//
+// The state of each entry as of the last sync is recorded in StateFileName in the directory. An
+// entry has changed remotely if both its UpdatedAt and its hash differ from the recorded ones, and
+// its file has changed locally if its hash differs from the recorded one. Then:
+//   - entries which changed remotely are pulled into their files,
+//   - files which changed locally are pushed to their entries,
+//   - files without an ID are created as new entries, and the new ID is written to the file,
+//   - files whose entries were deleted are deleted, unless they changed locally,
+//   - entries whose files were deleted are pulled again, as Sync never deletes entries.
+//
+// If an entry and its file both changed (or, on the first sync, differ), Sync reports a conflict
+// and leaves both alone. The conflict is resolved once they have the same title, tags and content,
+// or by deleting the file, in which case the entry is pulled again. Context fields are written to
+// new files and used for new entries, but changes to them are not synchronized.
+//
+// Failures to update individual entries or files are reported in the returned Report. The error is
+// only set if the journal or the directory could not be read.
+func Sync(ctx context.Context, client Client, token, journalID, dir string, options Options) (Report, error) {
+	s := &syncer{
+		ctx:       ctx,
+		client:    client,
+		token:     token,
+		journalID: journalID,
+		dir:       dir,
+		options:   options,
+		report:    Report{Changes: []Change{}},
+		names:     map[string]bool{},
+	}
+
+	stateErr := s.loadState()
+	if stateErr != nil {
+		return s.report, stateErr
+	}
+
+	remote := map[string]spire.Entry{}
+	remoteOrder := []string{}
+	iterator := spire.NewEntryIteratorContext(ctx, client, token, journalID, "", map[string]string{"order": spire.SearchOrderAscending})
+	for iterator.Next() {
+		entry := iterator.Entry()
+		remote[entry.Id] = entry
+		remoteOrder = append(remoteOrder, entry.Id)
+	}
+	if iteratorErr := iterator.Err(); iteratorErr != nil {
+		return s.report, iteratorErr
+	}
+
+	local, newFiles, scanErr := s.scan()
+	if scanErr != nil {
+		return s.report, scanErr
+	}
+
+	for _, entryID := range remoteOrder {
+		file, hasFile := local[entryID]
+		if hasFile && file == nil {
+			// Duplicated ID, already reported
+			continue
+		}
+		s.syncEntry(remote[entryID], file)
+	}
+
+	for entryID, file := range local {
+		if file == nil {
+			continue
+		}
+		if _, exists := remote[entryID]; exists {
+			continue
+		}
+		record, synced := s.state.Entries[entryID]
+		switch {
+		case !synced:
+			s.record(Change{Action: ActionSkip, EntryID: entryID, File: file.name, Reason: "no entry with this ID in the journal"})
+		case file.hash != record.Hash:
+			s.record(Change{Action: ActionConflict, EntryID: entryID, File: file.name, Reason: "entry was deleted but the file changed; remove the ID from the file to create a new entry"})
+		default:
+			s.deleteLocal(entryID, file)
+		}
+	}
+
+	for entryID := range s.state.Entries {
+		if _, exists := remote[entryID]; !exists {
+			if _, exists := local[entryID]; !exists {
+				delete(s.state.Entries, entryID)
+			}
+		}
+	}
+
+	for _, file := range newFiles {
+		s.create(file)
+	}
+
+	if options.DryRun {
+		return s.report, nil
+	}
+	return s.report, s.saveState()
+}
+
+func (s *syncer) loadState() error {
+	s.state = syncState{JournalID: s.journalID, Entries: map[string]syncedEntry{}}
+	contents, readErr := ioutil.ReadFile(filepath.Join(s.dir, StateFileName))
+	if os.IsNotExist(readErr) {
+		return nil
+	} else if readErr != nil {
+		return readErr
+	}
+
+	var state syncState
+	if decodeErr := json.Unmarshal(contents, &state); decodeErr != nil {
+		return fmt.Errorf("Could not parse sync state (%s): %s", StateFileName, decodeErr.Error())
+	}
+	if state.JournalID != s.journalID {
+		return fmt.Errorf("Directory is synchronized with a different journal: %s", state.JournalID)
+	}
+	if state.Entries != nil {
+		s.state.Entries = state.Entries
+	}
+	return nil
+}
+
+func (s *syncer) saveState() error {
+	contents, encodeErr := json.MarshalIndent(s.state, "", "  ")
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return ioutil.WriteFile(filepath.Join(s.dir, StateFileName), append(contents, '\n'), 0644)
+}
+
+// Reads the Markdown files in the directory (not its subdirectories). Files are indexed by the IDs
+// in their front matter; IDs which appear in several files map to nil. Files without IDs are
+// returned separately.
+func (s *syncer) scan() (map[string]*localFile, []*localFile, error) {
+	local := map[string]*localFile{}
+	newFiles := []*localFile{}
+
+	infos, readErr := ioutil.ReadDir(s.dir)
+	if readErr != nil {
+		return local, newFiles, readErr
+	}
+	duplicates := map[string][]string{}
+	for _, info := range infos {
+		s.names[info.Name()] = true
+		if info.IsDir() || filepath.Ext(info.Name()) != ".md" {
+			continue
+		}
+
+		contents, fileErr := ioutil.ReadFile(filepath.Join(s.dir, info.Name()))
+		if fileErr != nil {
+			return local, newFiles, fileErr
+		}
+		doc, parseErr := ParseDocument(contents)
+		if parseErr != nil {
+			s.record(Change{Action: ActionSkip, File: info.Name(), Reason: "could not parse file", Error: parseErr.Error()})
+			continue
+		}
+
+		file := &localFile{name: info.Name(), doc: doc, hash: doc.Hash()}
+		if doc.ID == "" {
+			newFiles = append(newFiles, file)
+			continue
+		}
+		if existing, exists := local[doc.ID]; exists {
+			if existing != nil {
+				duplicates[doc.ID] = append(duplicates[doc.ID], existing.name)
+			}
+			duplicates[doc.ID] = append(duplicates[doc.ID], file.name)
+			local[doc.ID] = nil
+			continue
+		}
+		local[doc.ID] = file
+	}
+
+	for entryID, names := range duplicates {
+		for _, name := range names {
+			s.record(Change{Action: ActionSkip, EntryID: entryID, File: name, Reason: fmt.Sprintf("ID is used by %d files", len(names))})
+		}
+	}
+	return local, newFiles, nil
+}
+
+// Synchronizes an entry with its file, which is nil if there is no file with the entry's ID.
+func (s *syncer) syncEntry(entry spire.Entry, file *localFile) {
+	remoteDoc := DocumentFromEntry(entry)
+	remoteHash := remoteDoc.Hash()
+	record, synced := s.state.Entries[entry.Id]
+
+	if file == nil {
+		reason, name := "new entry", ""
+		if synced {
+			reason = "file is missing"
+			// The name may have been taken by another file since the last sync.
+			if !s.names[record.File] {
+				name = record.File
+			}
+		}
+		s.pull(entry, remoteDoc, remoteHash, name, reason)
+		return
+	}
+
+	inSync := syncedEntry{File: file.name, UpdatedAt: entry.UpdatedAt, Hash: remoteHash}
+	if file.hash == remoteHash {
+		s.state.Entries[entry.Id] = inSync
+		s.report.Unchanged++
+		return
+	}
+	if !synced {
+		s.record(Change{Action: ActionConflict, EntryID: entry.Id, File: file.name, Reason: "entry and file differ and have not been synced before"})
+		return
+	}
+
+	remoteChanged := !entry.UpdatedAt.Equal(record.UpdatedAt) && remoteHash != record.Hash
+	localChanged := file.hash != record.Hash
+	switch {
+	case remoteChanged && localChanged:
+		s.record(Change{Action: ActionConflict, EntryID: entry.Id, File: file.name, Reason: "entry and file both changed since the last sync"})
+	case remoteChanged:
+		s.pull(entry, remoteDoc, remoteHash, file.name, "entry changed")
+	case localChanged:
+		s.push(entry, file)
+	default:
+		// The entry was changed and changed back
+		s.state.Entries[entry.Id] = inSync
+		s.report.Unchanged++
+	}
+}
+
+// Writes the entry to the named file, or to a new file if name is empty.
+func (s *syncer) pull(entry spire.Entry, doc Document, hash, name, reason string) {
+	if name == "" {
+		name = s.newFileName(entry)
+	}
+	s.names[name] = true
+	change := Change{Action: ActionPull, EntryID: entry.Id, File: name, Reason: reason}
+	if !s.options.DryRun {
+		if writeErr := ioutil.WriteFile(filepath.Join(s.dir, name), doc.Render(), 0644); writeErr != nil {
+			change.Error = writeErr.Error()
+			s.record(change)
+			return
+		}
+	}
+	s.state.Entries[entry.Id] = syncedEntry{File: name, UpdatedAt: entry.UpdatedAt, Hash: hash}
+	s.record(change)
+}
+
+// Updates the entry from its file. Only the title, content and tags are updated.
+func (s *syncer) push(entry spire.Entry, file *localFile) {
+	change := Change{Action: ActionPush, EntryID: entry.Id, File: file.name, Reason: "file changed"}
+	if s.options.DryRun {
+		s.record(change)
+		return
+	}
+
+	// The title and content, and the tags, are updated separately. The state is recorded after each
+	// update, so that if the second one fails, the next sync sees the first one as already synced
+	// instead of as a remote change which conflicts with the file.
+	if file.doc.Title != entry.Title || strings.TrimRight(file.doc.Content, "\n") != strings.TrimRight(entry.Content, "\n") {
+		// The file holds the whole entry, so empty content in the file clears the content of the entry.
+		updated, updateErr := s.client.ReplaceEntryContext(s.ctx, s.token, s.journalID, entry.Id, file.doc.Title, file.doc.Content)
+		if updateErr != nil {
+			change.Error = updateErr.Error()
+			s.record(change)
+			return
+		}
+		entry.Title, entry.Content, entry.UpdatedAt = updated.Title, updated.Content, updated.UpdatedAt
+		s.state.Entries[entry.Id] = syncedEntry{File: file.name, UpdatedAt: entry.UpdatedAt, Hash: DocumentFromEntry(entry).Hash()}
+	}
+	toAdd, toRemove := spire.DiffTags(entry.Tags, file.doc.Tags)
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		tagged, tagsErr := s.client.SetEntryTagsContext(s.ctx, s.token, s.journalID, entry.Id, file.doc.Tags)
+		if tagsErr != nil {
+			change.Error = tagsErr.Error()
+			s.record(change)
+			return
+		}
+		entry.UpdatedAt = tagged.UpdatedAt
+	}
+
+	s.state.Entries[entry.Id] = syncedEntry{File: file.name, UpdatedAt: entry.UpdatedAt, Hash: file.hash}
+	s.record(change)
+}
+
+// Creates an entry from a file without an ID, and writes the ID to the file.
+func (s *syncer) create(file *localFile) {
+	change := Change{Action: ActionCreate, File: file.name}
+	if s.options.DryRun {
+		s.record(change)
+		return
+	}
+
+	tags := file.doc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	entryContext := spire.EntryContext{ContextType: file.doc.ContextType, ContextID: file.doc.ContextID, ContextURL: file.doc.ContextURL}
+	entry, createErr := s.client.CreateEntryContext(s.ctx, s.token, s.journalID, file.doc.Title, file.doc.Content, tags, entryContext)
+	if createErr != nil {
+		change.Error = createErr.Error()
+		s.record(change)
+		return
+	}
+	change.EntryID = entry.Id
+
+	file.doc.ID = entry.Id
+	if writeErr := ioutil.WriteFile(filepath.Join(s.dir, file.name), file.doc.Render(), 0644); writeErr != nil {
+		// The entry exists, but the next sync would create it again.
+		change.Error = fmt.Sprintf("Could not write ID %s to file: %s", entry.Id, writeErr.Error())
+		s.record(change)
+		return
+	}
+	s.state.Entries[entry.Id] = syncedEntry{File: file.name, UpdatedAt: entry.UpdatedAt, Hash: file.hash}
+	s.record(change)
+}
+
+func (s *syncer) deleteLocal(entryID string, file *localFile) {
+	change := Change{Action: ActionDeleteLocal, EntryID: entryID, File: file.name, Reason: "entry was deleted"}
+	if !s.options.DryRun {
+		if removeErr := os.Remove(filepath.Join(s.dir, file.name)); removeErr != nil {
+			change.Error = removeErr.Error()
+			s.record(change)
+			return
+		}
+	}
+	delete(s.state.Entries, entryID)
+	s.record(change)
+}
+
+func (s *syncer) record(change Change) {
+	s.report.Changes = append(s.report.Changes, change)
+}
+
+// Names the file for an entry after its title. The start of the entry's ID is added if another file
+// already has the name.
+func (s *syncer) newFileName(entry spire.Entry) string {
+	slug := slugify(entry.Title)
+	if slug == "" {
+		return entry.Id + ".md"
+	}
+	name := slug + ".md"
+	if s.names[name] {
+		shortID := entry.Id
+		if len(shortID) > 8 {
+			shortID = shortID[:8]
+		}
+		name = slug + "-" + shortID + ".md"
+	}
+	if s.names[name] {
+		name = slug + "-" + entry.Id + ".md"
+	}
+	return name
+}
+
+const maxSlugLength int = 60
+
+func slugify(title string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			dash = false
+		} else if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+		if builder.Len() >= maxSlugLength {
+			break
+		}
+	}
+	return strings.TrimRight(builder.String(), "-")
+}
@@ -0,0 +1,409 @@
+package journalsync_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/journalsync"
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+type syncFixture struct {
+	t         *testing.T
+	fake      *spiretest.Fake
+	journalID string
+	dir       string
+}
+
+// Sets up a journal and an empty directory. The fake's clock advances by a second on every
+// change, so that updates are always visible in UpdatedAt.
+func newSyncFixture(t *testing.T) *syncFixture {
+	fake := spiretest.NewFake()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake.Now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	fake.SeedUser("token", "user")
+	journal := fake.SeedJournal("user", "journal")
+	return &syncFixture{t: t, fake: fake, journalID: journal.Id, dir: t.TempDir()}
+}
+
+func (f *syncFixture) sync(dryRun bool) journalsync.Report {
+	f.t.Helper()
+	report, err := journalsync.Sync(context.Background(), f.fake, "token", f.journalID, f.dir, journalsync.Options{DryRun: dryRun})
+	if err != nil {
+		f.t.Fatalf("Sync failed: %v", err)
+	}
+	// Files which cannot be parsed are skipped with an error, which tests check for themselves.
+	for _, change := range report.Changes {
+		if change.Error != "" && change.Action != journalsync.ActionSkip {
+			f.t.Fatalf("Sync reported an error: %+v", change)
+		}
+	}
+	return report
+}
+
+func (f *syncFixture) seedEntry(title, content string, tags ...string) spire.Entry {
+	f.t.Helper()
+	if tags == nil {
+		tags = []string{}
+	}
+	entry, err := f.fake.CreateEntry("token", f.journalID, title, content, tags, spire.EntryContext{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return entry
+}
+
+func (f *syncFixture) entry(entryID string) spire.Entry {
+	f.t.Helper()
+	entry, err := f.fake.GetEntry("token", f.journalID, entryID)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return entry
+}
+
+func (f *syncFixture) readFile(name string) journalsync.Document {
+	f.t.Helper()
+	contents, err := ioutil.ReadFile(filepath.Join(f.dir, name))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	doc, err := journalsync.ParseDocument(contents)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return doc
+}
+
+func (f *syncFixture) writeFile(name string, doc journalsync.Document) {
+	f.t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(f.dir, name), doc.Render(), 0644); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// Markdown files in the directory, sorted
+func (f *syncFixture) files() []string {
+	f.t.Helper()
+	names, err := filepath.Glob(filepath.Join(f.dir, "*.md"))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	for i, name := range names {
+		names[i] = filepath.Base(name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Checks the actions of a report, in any order.
+func checkActions(t *testing.T, report journalsync.Report, expected ...string) {
+	t.Helper()
+	actions := []string{}
+	for _, change := range report.Changes {
+		actions = append(actions, change.Action)
+	}
+	sort.Strings(actions)
+	sort.Strings(expected)
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected actions %v, got %+v", expected, report.Changes)
+	}
+	for i := range actions {
+		if actions[i] != expected[i] {
+			t.Fatalf("Expected actions %v, got %+v", expected, report.Changes)
+		}
+	}
+}
+
+func TestSyncPullsNewEntries(t *testing.T) {
+	f := newSyncFixture(t)
+	first := f.seedEntry("Deploying to production", "Run the deploy script.", "runbook")
+	second := f.seedEntry("Deploying to production", "A second entry with the same title.")
+
+	checkActions(t, f.sync(false), journalsync.ActionPull, journalsync.ActionPull)
+
+	// The second file gets the start of the entry's ID to tell it apart.
+	files := f.files()
+	expectedFiles := []string{"deploying-to-production-" + second.Id[:8] + ".md", "deploying-to-production.md"}
+	if len(files) != 2 || files[0] != expectedFiles[0] || files[1] != expectedFiles[1] {
+		t.Fatalf("Expected files %v, got %v", expectedFiles, files)
+	}
+	doc := f.readFile("deploying-to-production.md")
+	if doc.ID != first.Id || doc.Content != "Run the deploy script.\n" || len(doc.Tags) != 1 || doc.Tags[0] != "runbook" {
+		t.Errorf("Unexpected document: %#v", doc)
+	}
+	if _, err := os.Stat(filepath.Join(f.dir, journalsync.StateFileName)); err != nil {
+		t.Errorf("State file was not written: %v", err)
+	}
+
+	// Nothing changed, so the next sync does nothing.
+	report := f.sync(false)
+	checkActions(t, report)
+	if report.Unchanged != 2 {
+		t.Errorf("Expected 2 unchanged entries, got %d", report.Unchanged)
+	}
+}
+
+func TestSyncPushesLocalChanges(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Old content", "a", "b")
+	f.sync(false)
+
+	doc := f.readFile("title.md")
+	doc.Title = "New title"
+	doc.Content = "New content\n"
+	doc.Tags = []string{"b", "c"}
+	f.writeFile("title.md", doc)
+
+	checkActions(t, f.sync(false), journalsync.ActionPush)
+	updated := f.entry(entry.Id)
+	if updated.Title != "New title" || updated.Content != "New content\n" {
+		t.Errorf("Entry was not updated: %#v", updated)
+	}
+	tags := append([]string{}, updated.Tags...)
+	sort.Strings(tags)
+	if len(tags) != 2 || tags[0] != "b" || tags[1] != "c" {
+		t.Errorf("Expected tags [b c], got %v", updated.Tags)
+	}
+
+	checkActions(t, f.sync(false))
+}
+
+func TestSyncPushesEmptyContent(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Content to remove")
+	f.sync(false)
+
+	doc := f.readFile("title.md")
+	doc.Content = ""
+	f.writeFile("title.md", doc)
+
+	checkActions(t, f.sync(false), journalsync.ActionPush)
+	if updated := f.entry(entry.Id); updated.Content != "" {
+		t.Errorf("Expected the content of the entry to be cleared, got %q", updated.Content)
+	}
+
+	// The entry and the file agree, so nothing is pushed or pulled back.
+	checkActions(t, f.sync(false))
+	if doc := f.readFile("title.md"); doc.Content != "" {
+		t.Errorf("Expected the file to stay empty, got %q", doc.Content)
+	}
+}
+
+// Fails SetEntryTags until failTags is cleared.
+type tagFailingFake struct {
+	*spiretest.Fake
+	failTags bool
+}
+
+func (client *tagFailingFake) SetEntryTagsContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	if client.failTags {
+		return spire.Entry{}, errors.New("tags could not be set")
+	}
+	return client.Fake.SetEntryTagsContext(ctx, token, journalID, entryID, tags)
+}
+
+func TestSyncPushWithFailedTagUpdate(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Old content", "a")
+	f.sync(false)
+
+	doc := f.readFile("title.md")
+	doc.Content = "New content\n"
+	doc.Tags = []string{"b"}
+	f.writeFile("title.md", doc)
+
+	client := &tagFailingFake{Fake: f.fake, failTags: true}
+	report, err := journalsync.Sync(context.Background(), client, "token", f.journalID, f.dir, journalsync.Options{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkActions(t, report, journalsync.ActionPush)
+	if report.Errors() != 1 {
+		t.Fatalf("Expected the push to fail, got %+v", report.Changes)
+	}
+	if updated := f.entry(entry.Id); updated.Content != "New content\n" || len(updated.Tags) != 1 || updated.Tags[0] != "a" {
+		t.Fatalf("Expected only the content to be updated, got %#v", updated)
+	}
+
+	// The content update is not mistaken for a remote change, so the tags are pushed on the next
+	// sync instead of being reported as a conflict.
+	client.failTags = false
+	report, err = journalsync.Sync(context.Background(), client, "token", f.journalID, f.dir, journalsync.Options{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkActions(t, report, journalsync.ActionPush)
+	if report.Errors() != 0 {
+		t.Fatalf("Unexpected errors: %+v", report.Changes)
+	}
+	if updated := f.entry(entry.Id); len(updated.Tags) != 1 || updated.Tags[0] != "b" {
+		t.Errorf("Expected the tags to be updated, got %v", updated.Tags)
+	}
+	checkActions(t, f.sync(false))
+}
+
+func TestSyncPullsRemoteChanges(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Old content")
+	f.sync(false)
+
+	if _, err := f.fake.UpdateEntry("token", f.journalID, entry.Id, "", "New content"); err != nil {
+		t.Fatal(err)
+	}
+	checkActions(t, f.sync(false), journalsync.ActionPull)
+	if doc := f.readFile("title.md"); doc.Content != "New content\n" {
+		t.Errorf("File was not updated: %q", doc.Content)
+	}
+}
+
+func TestSyncConflicts(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Old content")
+	f.sync(false)
+
+	if _, err := f.fake.UpdateEntry("token", f.journalID, entry.Id, "", "Remote content"); err != nil {
+		t.Fatal(err)
+	}
+	doc := f.readFile("title.md")
+	doc.Content = "Local content\n"
+	f.writeFile("title.md", doc)
+
+	report := f.sync(false)
+	checkActions(t, report, journalsync.ActionConflict)
+	if report.Conflicts() != 1 {
+		t.Errorf("Expected 1 conflict, got %d", report.Conflicts())
+	}
+	if updated := f.entry(entry.Id); updated.Content != "Remote content" {
+		t.Errorf("Entry was changed despite the conflict: %q", updated.Content)
+	}
+	if doc := f.readFile("title.md"); doc.Content != "Local content\n" {
+		t.Errorf("File was changed despite the conflict: %q", doc.Content)
+	}
+
+	// Making the file agree with the entry resolves the conflict.
+	doc.Content = "Remote content"
+	f.writeFile("title.md", doc)
+	report = f.sync(false)
+	checkActions(t, report)
+	if report.Unchanged != 1 {
+		t.Errorf("Expected the entry to be in sync, got %+v", report)
+	}
+}
+
+func TestSyncCreatesEntriesFromNewFiles(t *testing.T) {
+	f := newSyncFixture(t)
+	f.writeFile("notes.md", journalsync.Document{
+		Title:       "Notes",
+		Tags:        []string{"draft"},
+		ContextType: "github",
+		ContextID:   "42",
+		Content:     "Some notes\n",
+	})
+
+	report := f.sync(false)
+	checkActions(t, report, journalsync.ActionCreate)
+	doc := f.readFile("notes.md")
+	if doc.ID == "" || doc.ID != report.Changes[0].EntryID {
+		t.Fatalf("Expected the ID of the new entry in the file, got %q", doc.ID)
+	}
+	entry := f.entry(doc.ID)
+	if entry.Title != "Notes" || entry.Content != "Some notes\n" || entry.ContextType != "github" || entry.ContextID != "42" {
+		t.Errorf("Unexpected entry: %#v", entry)
+	}
+
+	checkActions(t, f.sync(false))
+}
+
+func TestSyncDeletions(t *testing.T) {
+	f := newSyncFixture(t)
+	deleted := f.seedEntry("Deleted entry", "Content")
+	f.seedEntry("Deleted file", "Content")
+	f.sync(false)
+
+	if _, err := f.fake.DeleteEntry("token", f.journalID, deleted.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(f.dir, "deleted-file.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	report := f.sync(false)
+	checkActions(t, report, journalsync.ActionDeleteLocal, journalsync.ActionPull)
+	files := f.files()
+	if len(files) != 1 || files[0] != "deleted-file.md" {
+		t.Errorf("Expected only the deleted file to be pulled again, got %v", files)
+	}
+}
+
+func TestSyncDeletedEntryWithChangedFile(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Content")
+	f.sync(false)
+
+	if _, err := f.fake.DeleteEntry("token", f.journalID, entry.Id); err != nil {
+		t.Fatal(err)
+	}
+	doc := f.readFile("title.md")
+	doc.Content = "Changed\n"
+	f.writeFile("title.md", doc)
+
+	checkActions(t, f.sync(false), journalsync.ActionConflict)
+	if files := f.files(); len(files) != 1 {
+		t.Errorf("Expected the changed file to be kept, got %v", files)
+	}
+}
+
+func TestSyncSkipsInvalidFiles(t *testing.T) {
+	f := newSyncFixture(t)
+	entry := f.seedEntry("Title", "Content")
+	if err := ioutil.WriteFile(filepath.Join(f.dir, "broken.md"), []byte("---\ntitle: unclosed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"one.md", "two.md"} {
+		f.writeFile(name, journalsync.Document{ID: entry.Id, Title: "Title", Tags: []string{}, Content: "Content"})
+	}
+
+	report := f.sync(false)
+	checkActions(t, report, journalsync.ActionSkip, journalsync.ActionSkip, journalsync.ActionSkip)
+	if report.Conflicts() != 3 || report.Errors() != 1 {
+		t.Errorf("Expected 3 conflicts and 1 error, got %d and %d", report.Conflicts(), report.Errors())
+	}
+}
+
+func TestSyncDryRun(t *testing.T) {
+	f := newSyncFixture(t)
+	f.seedEntry("Remote", "Content")
+	f.writeFile("local.md", journalsync.Document{Title: "Local", Tags: []string{}})
+
+	checkActions(t, f.sync(true), journalsync.ActionPull, journalsync.ActionCreate)
+	if files := f.files(); len(files) != 1 || files[0] != "local.md" {
+		t.Errorf("Dry run changed the directory: %v", files)
+	}
+	if entries := f.fake.Entries(f.journalID); len(entries) != 1 {
+		t.Errorf("Dry run changed the journal: %d entries", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(f.dir, journalsync.StateFileName)); !os.IsNotExist(err) {
+		t.Errorf("Dry run wrote the state file")
+	}
+}
+
+func TestSyncRejectsOtherJournal(t *testing.T) {
+	f := newSyncFixture(t)
+	f.sync(false)
+
+	other := f.fake.SeedJournal("user", "other")
+	if _, err := journalsync.Sync(context.Background(), f.fake, "token", other.Id, f.dir, journalsync.Options{}); err == nil {
+		t.Errorf("Expected an error when syncing the directory with a different journal")
+	}
+}
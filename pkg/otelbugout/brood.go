@@ -0,0 +1,540 @@
+package otelbugout
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// TracedBrood wraps a brood.BroodCallerContext, creating a span for every operation.
+type TracedBrood struct {
+	Caller brood.BroodCallerContext
+	tracer trace.Tracer
+}
+
+var _ brood.BroodCaller = TracedBrood{}
+var _ brood.BroodCallerContext = TracedBrood{}
+var _ brood.ApplicationUserCaller = TracedBrood{}
+var _ brood.ApplicationUserCallerContext = TracedBrood{}
+var _ brood.TokenRevocationCaller = TracedBrood{}
+var _ brood.TokenRevocationCallerContext = TracedBrood{}
+var _ brood.PasswordResetCaller = TracedBrood{}
+var _ brood.PasswordResetCallerContext = TracedBrood{}
+var _ brood.GroupRoleCaller = TracedBrood{}
+var _ brood.GroupRoleCallerContext = TracedBrood{}
+var _ brood.ApplicationCaller = TracedBrood{}
+var _ brood.ApplicationCallerContext = TracedBrood{}
+
+// InstrumentBrood wraps a Brood caller so that every operation creates a span. To also propagate
+// trace context to Brood, register TraceContextMiddleware on the underlying client (or use
+// InstrumentBroodClient).
+func InstrumentBrood(caller brood.BroodCallerContext, opts ...Option) TracedBrood {
+	return TracedBrood{Caller: caller, tracer: newConfig(opts).tracer()}
+}
+
+func (client TracedBrood) start(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return client.tracer.Start(ctx, "brood."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+func (client TracedBrood) Ping() (string, error) {
+	return client.PingContext(context.Background())
+}
+
+func (client TracedBrood) PingContext(ctx context.Context) (string, error) {
+	ctx, span := client.start(ctx, "Ping")
+	result, err := client.Caller.PingContext(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) Version() (string, error) {
+	return client.VersionContext(context.Background())
+}
+
+func (client TracedBrood) VersionContext(ctx context.Context) (string, error) {
+	ctx, span := client.start(ctx, "Version")
+	result, err := client.Caller.VersionContext(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) Auth(token string) (brood.AuthUser, error) {
+	return client.AuthContext(context.Background(), token)
+}
+
+func (client TracedBrood) AuthContext(ctx context.Context, token string) (brood.AuthUser, error) {
+	ctx, span := client.start(ctx, "Auth")
+	result, err := client.Caller.AuthContext(ctx, token)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) CreateUser(username, email, password string) (brood.User, error) {
+	return client.CreateUserContext(context.Background(), username, email, password)
+}
+
+func (client TracedBrood) CreateUserContext(ctx context.Context, username, email, password string) (brood.User, error) {
+	ctx, span := client.start(ctx, "CreateUser")
+	result, err := client.Caller.CreateUserContext(ctx, username, email, password)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GenerateToken(username, password string) (string, error) {
+	return client.GenerateTokenContext(context.Background(), username, password)
+}
+
+func (client TracedBrood) GenerateTokenContext(ctx context.Context, username, password string) (string, error) {
+	ctx, span := client.start(ctx, "GenerateToken")
+	result, err := client.Caller.GenerateTokenContext(ctx, username, password)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) CreateApplicationUser(applicationId, applicationToken, username, email, password string) (brood.User, error) {
+	return client.CreateApplicationUserContext(context.Background(), applicationId, applicationToken, username, email, password)
+}
+
+func (client TracedBrood) CreateApplicationUserContext(ctx context.Context, applicationId, applicationToken, username, email, password string) (brood.User, error) {
+	caller, ok := client.Caller.(brood.ApplicationUserCallerContext)
+	if !ok {
+		return brood.User{}, utils.UnsupportedError(client.Caller, "CreateApplicationUserContext")
+	}
+	ctx, span := client.start(ctx, "CreateApplicationUser", ApplicationIDKey.String(applicationId))
+	result, err := caller.CreateApplicationUserContext(ctx, applicationId, applicationToken, username, email, password)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GenerateApplicationToken(applicationId, applicationToken, username, password string) (string, error) {
+	return client.GenerateApplicationTokenContext(context.Background(), applicationId, applicationToken, username, password)
+}
+
+func (client TracedBrood) GenerateApplicationTokenContext(ctx context.Context, applicationId, applicationToken, username, password string) (string, error) {
+	caller, ok := client.Caller.(brood.ApplicationUserCallerContext)
+	if !ok {
+		return "", utils.UnsupportedError(client.Caller, "GenerateApplicationTokenContext")
+	}
+	ctx, span := client.start(ctx, "GenerateApplicationToken", ApplicationIDKey.String(applicationId))
+	result, err := caller.GenerateApplicationTokenContext(ctx, applicationId, applicationToken, username, password)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) AnnotateToken(token, tokenType, note string) (string, error) {
+	return client.AnnotateTokenContext(context.Background(), token, tokenType, note)
+}
+
+func (client TracedBrood) AnnotateTokenContext(ctx context.Context, token, tokenType, note string) (string, error) {
+	ctx, span := client.start(ctx, "AnnotateToken")
+	result, err := client.Caller.AnnotateTokenContext(ctx, token, tokenType, note)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ListTokens(token string) (brood.UserTokensList, error) {
+	return client.ListTokensContext(context.Background(), token)
+}
+
+func (client TracedBrood) ListTokensContext(ctx context.Context, token string) (brood.UserTokensList, error) {
+	ctx, span := client.start(ctx, "ListTokens")
+	result, err := client.Caller.ListTokensContext(ctx, token)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) RevokeToken(token, targetToken string) (brood.UserToken, error) {
+	return client.RevokeTokenContext(context.Background(), token, targetToken)
+}
+
+func (client TracedBrood) RevokeTokenContext(ctx context.Context, token, targetToken string) (brood.UserToken, error) {
+	caller, ok := client.Caller.(brood.TokenRevocationCallerContext)
+	if !ok {
+		return brood.UserToken{}, utils.UnsupportedError(client.Caller, "RevokeTokenContext")
+	}
+	ctx, span := client.start(ctx, "RevokeToken")
+	result, err := caller.RevokeTokenContext(ctx, token, targetToken)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) FindUser(token string, queryParameters map[string]string) (brood.User, error) {
+	return client.FindUserContext(context.Background(), token, queryParameters)
+}
+
+func (client TracedBrood) FindUserContext(ctx context.Context, token string, queryParameters map[string]string) (brood.User, error) {
+	ctx, span := client.start(ctx, "FindUser")
+	result, err := client.Caller.FindUserContext(ctx, token, queryParameters)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) FindApplicationUser(token, applicationId string, queryParameters map[string]string) (brood.User, error) {
+	return client.FindApplicationUserContext(context.Background(), token, applicationId, queryParameters)
+}
+
+func (client TracedBrood) FindApplicationUserContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (brood.User, error) {
+	caller, ok := client.Caller.(brood.ApplicationUserCallerContext)
+	if !ok {
+		return brood.User{}, utils.UnsupportedError(client.Caller, "FindApplicationUserContext")
+	}
+	ctx, span := client.start(ctx, "FindApplicationUser", ApplicationIDKey.String(applicationId))
+	result, err := caller.FindApplicationUserContext(ctx, token, applicationId, queryParameters)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetUser(token string) (brood.User, error) {
+	return client.GetUserContext(context.Background(), token)
+}
+
+func (client TracedBrood) GetUserContext(ctx context.Context, token string) (brood.User, error) {
+	ctx, span := client.start(ctx, "GetUser")
+	result, err := client.Caller.GetUserContext(ctx, token)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) VerifyUser(token, code string) (brood.User, error) {
+	return client.VerifyUserContext(context.Background(), token, code)
+}
+
+func (client TracedBrood) VerifyUserContext(ctx context.Context, token, code string) (brood.User, error) {
+	ctx, span := client.start(ctx, "VerifyUser")
+	result, err := client.Caller.VerifyUserContext(ctx, token, code)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ChangePassword(token, currentPassword, newPassword string) (brood.User, error) {
+	return client.ChangePasswordContext(context.Background(), token, currentPassword, newPassword)
+}
+
+func (client TracedBrood) ChangePasswordContext(ctx context.Context, token, currentPassword, newPassword string) (brood.User, error) {
+	ctx, span := client.start(ctx, "ChangePassword")
+	result, err := client.Caller.ChangePasswordContext(ctx, token, currentPassword, newPassword)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) RequestPasswordReset(email string) (string, error) {
+	return client.RequestPasswordResetContext(context.Background(), email)
+}
+
+func (client TracedBrood) RequestPasswordResetContext(ctx context.Context, email string) (string, error) {
+	caller, ok := client.Caller.(brood.PasswordResetCallerContext)
+	if !ok {
+		return "", utils.UnsupportedError(client.Caller, "RequestPasswordResetContext")
+	}
+	ctx, span := client.start(ctx, "RequestPasswordReset")
+	result, err := caller.RequestPasswordResetContext(ctx, email)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ConfirmPasswordReset(resetID, newPassword string) (brood.User, error) {
+	return client.ConfirmPasswordResetContext(context.Background(), resetID, newPassword)
+}
+
+func (client TracedBrood) ConfirmPasswordResetContext(ctx context.Context, resetID, newPassword string) (brood.User, error) {
+	caller, ok := client.Caller.(brood.PasswordResetCallerContext)
+	if !ok {
+		return brood.User{}, utils.UnsupportedError(client.Caller, "ConfirmPasswordResetContext")
+	}
+	ctx, span := client.start(ctx, "ConfirmPasswordReset")
+	result, err := caller.ConfirmPasswordResetContext(ctx, resetID, newPassword)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) CreateGroup(token, name string) (brood.Group, error) {
+	return client.CreateGroupContext(context.Background(), token, name)
+}
+
+func (client TracedBrood) CreateGroupContext(ctx context.Context, token, name string) (brood.Group, error) {
+	ctx, span := client.start(ctx, "CreateGroup")
+	result, err := client.Caller.CreateGroupContext(ctx, token, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetUserGroups(token string) (brood.UserGroupsList, error) {
+	return client.GetUserGroupsContext(context.Background(), token)
+}
+
+func (client TracedBrood) GetUserGroupsContext(ctx context.Context, token string) (brood.UserGroupsList, error) {
+	ctx, span := client.start(ctx, "GetUserGroups")
+	result, err := client.Caller.GetUserGroupsContext(ctx, token)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetGroup(token, groupID string) (brood.Group, error) {
+	return client.GetGroupContext(context.Background(), token, groupID)
+}
+
+func (client TracedBrood) GetGroupContext(ctx context.Context, token, groupID string) (brood.Group, error) {
+	caller, ok := client.Caller.(brood.GroupRoleCallerContext)
+	if !ok {
+		return brood.Group{}, utils.UnsupportedError(client.Caller, "GetGroupContext")
+	}
+	ctx, span := client.start(ctx, "GetGroup", GroupIDKey.String(groupID))
+	result, err := caller.GetGroupContext(ctx, token, groupID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ListGroupMembers(token, groupID string) (brood.GroupUsersList, error) {
+	return client.ListGroupMembersContext(context.Background(), token, groupID)
+}
+
+func (client TracedBrood) ListGroupMembersContext(ctx context.Context, token, groupID string) (brood.GroupUsersList, error) {
+	caller, ok := client.Caller.(brood.GroupRoleCallerContext)
+	if !ok {
+		return brood.GroupUsersList{}, utils.UnsupportedError(client.Caller, "ListGroupMembersContext")
+	}
+	ctx, span := client.start(ctx, "ListGroupMembers", GroupIDKey.String(groupID))
+	result, err := caller.ListGroupMembersContext(ctx, token, groupID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) DeleteGroup(token, groupID string) (brood.Group, error) {
+	return client.DeleteGroupContext(context.Background(), token, groupID)
+}
+
+func (client TracedBrood) DeleteGroupContext(ctx context.Context, token, groupID string) (brood.Group, error) {
+	ctx, span := client.start(ctx, "DeleteGroup", GroupIDKey.String(groupID))
+	result, err := client.Caller.DeleteGroupContext(ctx, token, groupID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) RenameGroup(token, groupID, name string) (brood.Group, error) {
+	return client.RenameGroupContext(context.Background(), token, groupID, name)
+}
+
+func (client TracedBrood) RenameGroupContext(ctx context.Context, token, groupID, name string) (brood.Group, error) {
+	ctx, span := client.start(ctx, "RenameGroup", GroupIDKey.String(groupID))
+	result, err := client.Caller.RenameGroupContext(ctx, token, groupID, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) AddUserToGroup(token, groupID, username, role string) (brood.UserGroup, error) {
+	return client.AddUserToGroupContext(context.Background(), token, groupID, username, role)
+}
+
+func (client TracedBrood) AddUserToGroupContext(ctx context.Context, token, groupID, username, role string) (brood.UserGroup, error) {
+	ctx, span := client.start(ctx, "AddUserToGroup", GroupIDKey.String(groupID))
+	result, err := client.Caller.AddUserToGroupContext(ctx, token, groupID, username, role)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) SetUserGroupRole(token, groupID, username string, role brood.GroupRole) (brood.UserGroup, error) {
+	return client.SetUserGroupRoleContext(context.Background(), token, groupID, username, role)
+}
+
+func (client TracedBrood) SetUserGroupRoleContext(ctx context.Context, token, groupID, username string, role brood.GroupRole) (brood.UserGroup, error) {
+	caller, ok := client.Caller.(brood.GroupRoleCallerContext)
+	if !ok {
+		return brood.UserGroup{}, utils.UnsupportedError(client.Caller, "SetUserGroupRoleContext")
+	}
+	ctx, span := client.start(ctx, "SetUserGroupRole", GroupIDKey.String(groupID))
+	result, err := caller.SetUserGroupRoleContext(ctx, token, groupID, username, role)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) RemoveUserFromGroup(token, groupID, username string) (brood.UserGroup, error) {
+	return client.RemoveUserFromGroupContext(context.Background(), token, groupID, username)
+}
+
+func (client TracedBrood) RemoveUserFromGroupContext(ctx context.Context, token, groupID, username string) (brood.UserGroup, error) {
+	ctx, span := client.start(ctx, "RemoveUserFromGroup", GroupIDKey.String(groupID))
+	result, err := client.Caller.RemoveUserFromGroupContext(ctx, token, groupID, username)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) CreateResource(token, applicationId string, resourceData interface{}) (brood.Resource, error) {
+	return client.CreateResourceContext(context.Background(), token, applicationId, resourceData)
+}
+
+func (client TracedBrood) CreateResourceContext(ctx context.Context, token, applicationId string, resourceData interface{}) (brood.Resource, error) {
+	ctx, span := client.start(ctx, "CreateResource", ApplicationIDKey.String(applicationId))
+	result, err := client.Caller.CreateResourceContext(ctx, token, applicationId, resourceData)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) UpdateResource(token, resourceId string, update interface{}, dropKeys []string) (brood.Resource, error) {
+	return client.UpdateResourceContext(context.Background(), token, resourceId, update, dropKeys)
+}
+
+func (client TracedBrood) UpdateResourceContext(ctx context.Context, token, resourceId string, update interface{}, dropKeys []string) (brood.Resource, error) {
+	ctx, span := client.start(ctx, "UpdateResource", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.UpdateResourceContext(ctx, token, resourceId, update, dropKeys)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetResource(token, resourceId string) (brood.Resource, error) {
+	return client.GetResourceContext(context.Background(), token, resourceId)
+}
+
+func (client TracedBrood) GetResourceContext(ctx context.Context, token, resourceId string) (brood.Resource, error) {
+	ctx, span := client.start(ctx, "GetResource", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.GetResourceContext(ctx, token, resourceId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetResources(token, applicationId string, queryParameters map[string]string) (brood.Resources, error) {
+	return client.GetResourcesContext(context.Background(), token, applicationId, queryParameters)
+}
+
+func (client TracedBrood) GetResourcesContext(ctx context.Context, token, applicationId string, queryParameters map[string]string) (brood.Resources, error) {
+	ctx, span := client.start(ctx, "GetResources", ApplicationIDKey.String(applicationId))
+	result, err := client.Caller.GetResourcesContext(ctx, token, applicationId, queryParameters)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) DeleteResource(token, resourceId string) (brood.Resource, error) {
+	return client.DeleteResourceContext(context.Background(), token, resourceId)
+}
+
+func (client TracedBrood) DeleteResourceContext(ctx context.Context, token, resourceId string) (brood.Resource, error) {
+	ctx, span := client.start(ctx, "DeleteResource", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.DeleteResourceContext(ctx, token, resourceId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetResourceHolders(token, resourceId string) (brood.ResourceHolders, error) {
+	return client.GetResourceHoldersContext(context.Background(), token, resourceId)
+}
+
+func (client TracedBrood) GetResourceHoldersContext(ctx context.Context, token, resourceId string) (brood.ResourceHolders, error) {
+	ctx, span := client.start(ctx, "GetResourceHolders", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.GetResourceHoldersContext(ctx, token, resourceId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) AddResourceHolderPermissions(token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	return client.AddResourceHolderPermissionsContext(context.Background(), token, resourceId, resourceHolder)
+}
+
+func (client TracedBrood) AddResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	ctx, span := client.start(ctx, "AddResourceHolderPermissions", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.AddResourceHolderPermissionsContext(ctx, token, resourceId, resourceHolder)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) DeleteResourceHolderPermissions(token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	return client.DeleteResourceHolderPermissionsContext(context.Background(), token, resourceId, resourceHolder)
+}
+
+func (client TracedBrood) DeleteResourceHolderPermissionsContext(ctx context.Context, token, resourceId string, resourceHolder brood.ResourceHolder) (brood.ResourceHolders, error) {
+	ctx, span := client.start(ctx, "DeleteResourceHolderPermissions", ResourceIDKey.String(resourceId))
+	result, err := client.Caller.DeleteResourceHolderPermissionsContext(ctx, token, resourceId, resourceHolder)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) CreateApplication(token, groupId, name, description string) (brood.Application, error) {
+	return client.CreateApplicationContext(context.Background(), token, groupId, name, description)
+}
+
+func (client TracedBrood) CreateApplicationContext(ctx context.Context, token, groupId, name, description string) (brood.Application, error) {
+	ctx, span := client.start(ctx, "CreateApplication", GroupIDKey.String(groupId))
+	result, err := client.Caller.CreateApplicationContext(ctx, token, groupId, name, description)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) GetApplication(token, applicationId string) (brood.Application, error) {
+	return client.GetApplicationContext(context.Background(), token, applicationId)
+}
+
+func (client TracedBrood) GetApplicationContext(ctx context.Context, token, applicationId string) (brood.Application, error) {
+	ctx, span := client.start(ctx, "GetApplication", ApplicationIDKey.String(applicationId))
+	result, err := client.Caller.GetApplicationContext(ctx, token, applicationId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ListApplications(token, groupId string) (brood.ApplicationsList, error) {
+	return client.ListApplicationsContext(context.Background(), token, groupId)
+}
+
+func (client TracedBrood) ListApplicationsContext(ctx context.Context, token, groupId string) (brood.ApplicationsList, error) {
+	ctx, span := client.start(ctx, "ListApplications", GroupIDKey.String(groupId))
+	result, err := client.Caller.ListApplicationsContext(ctx, token, groupId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) DeleteApplication(token, applicationId string) (brood.Application, error) {
+	return client.DeleteApplicationContext(context.Background(), token, applicationId)
+}
+
+func (client TracedBrood) DeleteApplicationContext(ctx context.Context, token, applicationId string) (brood.Application, error) {
+	ctx, span := client.start(ctx, "DeleteApplication", ApplicationIDKey.String(applicationId))
+	result, err := client.Caller.DeleteApplicationContext(ctx, token, applicationId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) UpdateApplication(token, applicationId, name, description string) (brood.Application, error) {
+	return client.UpdateApplicationContext(context.Background(), token, applicationId, name, description)
+}
+
+func (client TracedBrood) UpdateApplicationContext(ctx context.Context, token, applicationId, name, description string) (brood.Application, error) {
+	caller, ok := client.Caller.(brood.ApplicationCallerContext)
+	if !ok {
+		return brood.Application{}, utils.UnsupportedError(client.Caller, "UpdateApplicationContext")
+	}
+	ctx, span := client.start(ctx, "UpdateApplication", ApplicationIDKey.String(applicationId))
+	result, err := caller.UpdateApplicationContext(ctx, token, applicationId, name, description)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) TransferApplication(token, applicationId, groupId string) (brood.Application, error) {
+	return client.TransferApplicationContext(context.Background(), token, applicationId, groupId)
+}
+
+func (client TracedBrood) TransferApplicationContext(ctx context.Context, token, applicationId, groupId string) (brood.Application, error) {
+	caller, ok := client.Caller.(brood.ApplicationCallerContext)
+	if !ok {
+		return brood.Application{}, utils.UnsupportedError(client.Caller, "TransferApplicationContext")
+	}
+	ctx, span := client.start(ctx, "TransferApplication", ApplicationIDKey.String(applicationId), GroupIDKey.String(groupId))
+	result, err := caller.TransferApplicationContext(ctx, token, applicationId, groupId)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedBrood) ListApplicationUsers(token, applicationId string) (brood.ApplicationUsersList, error) {
+	return client.ListApplicationUsersContext(context.Background(), token, applicationId)
+}
+
+func (client TracedBrood) ListApplicationUsersContext(ctx context.Context, token, applicationId string) (brood.ApplicationUsersList, error) {
+	caller, ok := client.Caller.(brood.ApplicationCallerContext)
+	if !ok {
+		return brood.ApplicationUsersList{}, utils.UnsupportedError(client.Caller, "ListApplicationUsersContext")
+	}
+	ctx, span := client.start(ctx, "ListApplicationUsers", ApplicationIDKey.String(applicationId))
+	result, err := caller.ListApplicationUsersContext(ctx, token, applicationId)
+	endSpan(span, err)
+	return result, err
+}
@@ -0,0 +1,12 @@
+module github.com/bugout-dev/bugout-go/pkg/otelbugout
+
+go 1.15
+
+require (
+	github.com/bugout-dev/bugout-go v0.5.0
+	go.opentelemetry.io/otel v1.2.0
+	go.opentelemetry.io/otel/sdk v1.2.0
+	go.opentelemetry.io/otel/trace v1.2.0
+)
+
+replace github.com/bugout-dev/bugout-go => ../..
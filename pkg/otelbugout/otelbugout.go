@@ -0,0 +1,151 @@
+// Package otelbugout instruments Bugout Spire and Brood clients with OpenTelemetry tracing.
+//
+// It creates one span per client operation, named after the interface method (for example
+// "spire.CreateEntry"), and injects W3C trace context headers into the outgoing HTTP requests so
+// that traces continue into the Bugout APIs.
+//
+// This package is a separate Go module, so that applications which do not use OpenTelemetry do
+// not depend on it. Its go.mod replaces github.com/bugout-dev/bugout-go with the client library in
+// the same checkout, so both modules are always built and tested together.
+package otelbugout
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	bugout "github.com/bugout-dev/bugout-go/pkg"
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+const instrumentationName = "github.com/bugout-dev/bugout-go/pkg/otelbugout"
+
+// Attributes which are set on spans when the corresponding argument is passed to an operation.
+const (
+	JournalIDKey     = attribute.Key("bugout.journal_id")
+	EntryIDKey       = attribute.Key("bugout.entry_id")
+	ResourceIDKey    = attribute.Key("bugout.resource_id")
+	GroupIDKey       = attribute.Key("bugout.group_id")
+	ApplicationIDKey = attribute.Key("bugout.application_id")
+	MemberIDKey      = attribute.Key("bugout.member_id")
+	StatusCodeKey    = attribute.Key("http.response.status_code")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider used to create spans. Defaults to the global
+// tracer provider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tracerProvider
+	}
+}
+
+// WithPropagator sets the propagator used to inject trace context into outgoing requests.
+// Defaults to W3C trace context.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+// TraceContextMiddleware returns client middleware which injects the trace context of each
+// request into its headers, and records the response status code on the active span.
+func TraceContextMiddleware(opts ...Option) utils.Middleware {
+	c := newConfig(opts)
+	return utils.Middleware{
+		BeforeRequest: func(request *http.Request, info utils.RequestInfo) error {
+			c.propagator.Inject(request.Context(), propagation.HeaderCarrier(request.Header))
+			return nil
+		},
+		AfterResponse: func(response *http.Response, info utils.ResponseInfo) {
+			if response == nil || response.Request == nil {
+				return
+			}
+			span := trace.SpanFromContext(response.Request.Context())
+			span.SetAttributes(StatusCodeKey.Int(response.StatusCode))
+		},
+	}
+}
+
+// Records the outcome of an operation on its span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		var apiErr *utils.APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(StatusCodeKey.Int(apiErr.StatusCode))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Copies the middleware slice before appending, so that the caller's client is not modified.
+func withMiddleware(middleware []utils.Middleware, extra utils.Middleware) []utils.Middleware {
+	combined := make([]utils.Middleware, 0, len(middleware)+1)
+	combined = append(combined, middleware...)
+	return append(combined, extra)
+}
+
+// InstrumentSpireClient adds trace context propagation to a SpireClient and wraps it so that
+// every operation creates a span.
+func InstrumentSpireClient(client spire.SpireClient, opts ...Option) TracedSpire {
+	client.Middleware = withMiddleware(client.Middleware, TraceContextMiddleware(opts...))
+	return InstrumentSpire(client, opts...)
+}
+
+// InstrumentBroodClient adds trace context propagation to a BroodClient and wraps it so that
+// every operation creates a span.
+func InstrumentBroodClient(client brood.BroodClient, opts ...Option) TracedBrood {
+	client.Middleware = withMiddleware(client.Middleware, TraceContextMiddleware(opts...))
+	return InstrumentBrood(client, opts...)
+}
+
+// InstrumentClient instruments the Spire and Brood clients of a BugoutClient. Clients which are
+// not SpireClient or BroodClient values are wrapped with spans if they accept contexts, and are
+// otherwise left untouched.
+func InstrumentClient(client bugout.BugoutClient, opts ...Option) bugout.BugoutClient {
+	switch spireClient := client.Spire.(type) {
+	case spire.SpireClient:
+		client.Spire = InstrumentSpireClient(spireClient, opts...)
+	case spire.SpireCallerContext:
+		client.Spire = InstrumentSpire(spireClient, opts...)
+	}
+
+	switch broodClient := client.Brood.(type) {
+	case brood.BroodClient:
+		client.Brood = InstrumentBroodClient(broodClient, opts...)
+	case brood.BroodCallerContext:
+		client.Brood = InstrumentBrood(broodClient, opts...)
+	}
+
+	return client
+}
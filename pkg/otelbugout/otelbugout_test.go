@@ -0,0 +1,292 @@
+package otelbugout_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood/broodtest"
+	"github.com/bugout-dev/bugout-go/pkg/otelbugout"
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+func newRecorder() (*tracetest.SpanRecorder, trace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	return recorder, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+type expectedSpan struct {
+	name       string
+	attributes map[attribute.Key]string
+	statusCode int
+	failed     bool
+}
+
+func checkSpan(t *testing.T, span sdktrace.ReadOnlySpan, expected expectedSpan) {
+	t.Helper()
+	if span.Name() != expected.name {
+		t.Errorf("Expected span %q, got %q", expected.name, span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected a client span, got %s", span.SpanKind())
+	}
+	for key, value := range expected.attributes {
+		actual, ok := attributeValue(span.Attributes(), key)
+		if !ok {
+			t.Errorf("Span %s has no %s attribute", span.Name(), key)
+		} else if actual.AsString() != value {
+			t.Errorf("Expected %s=%q on span %s, got %q", key, value, span.Name(), actual.AsString())
+		}
+	}
+	statusCode, hasStatusCode := attributeValue(span.Attributes(), otelbugout.StatusCodeKey)
+	if expected.statusCode != 0 && (!hasStatusCode || statusCode.AsInt64() != int64(expected.statusCode)) {
+		t.Errorf("Expected status code %d on span %s, got %v", expected.statusCode, span.Name(), statusCode.Emit())
+	}
+	if expected.failed {
+		if span.Status().Code != codes.Error {
+			t.Errorf("Expected span %s to have error status, got %s", span.Name(), span.Status().Code)
+		}
+		if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+			t.Errorf("Expected span %s to record the error", span.Name())
+		}
+	} else if span.Status().Code == codes.Error {
+		t.Errorf("Expected span %s to succeed, got error status: %s", span.Name(), span.Status().Description)
+	}
+}
+
+func TestSpireSpans(t *testing.T) {
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "user")
+	journal := fake.SeedJournal("user", "journal")
+	entry, seedErr := fake.SeedEntry(journal.Id, spire.Entry{Title: "title", Content: "content"})
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+
+	cases := []struct {
+		call     func(client otelbugout.TracedSpire) error
+		expected expectedSpan
+	}{
+		{
+			call: func(client otelbugout.TracedSpire) error {
+				_, err := client.GetJournal("token", journal.Id)
+				return err
+			},
+			expected: expectedSpan{
+				name:       "spire.GetJournal",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: journal.Id},
+			},
+		},
+		{
+			call: func(client otelbugout.TracedSpire) error {
+				_, err := client.GetEntry("token", journal.Id, entry.Id)
+				return err
+			},
+			expected: expectedSpan{
+				name:       "spire.GetEntry",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: journal.Id, otelbugout.EntryIDKey: entry.Id},
+			},
+		},
+		{
+			call: func(client otelbugout.TracedSpire) error {
+				_, err := client.GetEntry("token", journal.Id, "missing")
+				return err
+			},
+			expected: expectedSpan{
+				name:       "spire.GetEntry",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: journal.Id, otelbugout.EntryIDKey: "missing"},
+				statusCode: http.StatusNotFound,
+				failed:     true,
+			},
+		},
+		{
+			call: func(client otelbugout.TracedSpire) error {
+				_, err := client.GetJournal("wrong token", journal.Id)
+				return err
+			},
+			expected: expectedSpan{
+				name:       "spire.GetJournal",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: journal.Id},
+				statusCode: http.StatusUnauthorized,
+				failed:     true,
+			},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, c.expected.name), func(t *testing.T) {
+			recorder, tracerProvider := newRecorder()
+			client := otelbugout.InstrumentSpire(fake, otelbugout.WithTracerProvider(tracerProvider))
+
+			err := c.call(client)
+			if (err != nil) != c.expected.failed {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("Expected 1 span, got %d", len(spans))
+			}
+			checkSpan(t, spans[0], c.expected)
+		})
+	}
+}
+
+func TestBroodSpans(t *testing.T) {
+	fake := broodtest.NewFake()
+	user, token, seedErr := fake.SeedUser("user", "user@example.com", "password")
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+	group, seedErr := fake.SeedGroup(user.Id, "group")
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+	application, seedErr := fake.CreateApplication(token, group.Id, "application", "")
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+	resource, seedErr := fake.CreateResource(token, application.Id, map[string]interface{}{"key": "value"})
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+
+	cases := []struct {
+		call     func(client otelbugout.TracedBrood) error
+		expected expectedSpan
+	}{
+		{
+			call: func(client otelbugout.TracedBrood) error {
+				_, err := client.GetResource(token, resource.Id)
+				return err
+			},
+			expected: expectedSpan{
+				name:       "brood.GetResource",
+				attributes: map[attribute.Key]string{otelbugout.ResourceIDKey: resource.Id},
+			},
+		},
+		{
+			call: func(client otelbugout.TracedBrood) error {
+				_, err := client.GetResource(token, "missing")
+				return err
+			},
+			expected: expectedSpan{
+				name:       "brood.GetResource",
+				attributes: map[attribute.Key]string{otelbugout.ResourceIDKey: "missing"},
+				statusCode: http.StatusNotFound,
+				failed:     true,
+			},
+		},
+		{
+			call: func(client otelbugout.TracedBrood) error {
+				_, err := client.GetApplication(token, application.Id)
+				return err
+			},
+			expected: expectedSpan{
+				name:       "brood.GetApplication",
+				attributes: map[attribute.Key]string{otelbugout.ApplicationIDKey: application.Id},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, c.expected.name), func(t *testing.T) {
+			recorder, tracerProvider := newRecorder()
+			client := otelbugout.InstrumentBrood(fake, otelbugout.WithTracerProvider(tracerProvider))
+
+			err := c.call(client)
+			if (err != nil) != c.expected.failed {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("Expected 1 span, got %d", len(spans))
+			}
+			checkSpan(t, spans[0], c.expected)
+		})
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	cases := []struct {
+		status   int
+		body     string
+		expected expectedSpan
+	}{
+		{
+			status: http.StatusOK,
+			body:   `{"id": "journal", "name": "journal"}`,
+			expected: expectedSpan{
+				name:       "spire.GetJournal",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: "journal"},
+				statusCode: http.StatusOK,
+			},
+		},
+		{
+			status: http.StatusForbidden,
+			body:   `{"detail": "Forbidden"}`,
+			expected: expectedSpan{
+				name:       "spire.GetJournal",
+				attributes: map[attribute.Key]string{otelbugout.JournalIDKey: "journal"},
+				statusCode: http.StatusForbidden,
+				failed:     true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			var traceparent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceparent = r.Header.Get("traceparent")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			}))
+			defer server.Close()
+
+			exporter := tracetest.NewInMemoryExporter()
+			tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			client := otelbugout.InstrumentSpireClient(spire.NewClient(server.URL, time.Second), otelbugout.WithTracerProvider(tracerProvider))
+
+			_, err := client.GetJournalContext(context.Background(), "token", "journal")
+			if (err != nil) != c.expected.failed {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			spans := exporter.GetSpans().Snapshots()
+			if len(spans) != 1 {
+				t.Fatalf("Expected 1 span, got %d", len(spans))
+			}
+			checkSpan(t, spans[0], c.expected)
+
+			if traceparent == "" {
+				t.Fatal("Request has no traceparent header")
+			}
+			carrier := propagation.HeaderCarrier(http.Header{"Traceparent": []string{traceparent}})
+			injected := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+			if injected.TraceID() != spans[0].SpanContext().TraceID() || injected.SpanID() != spans[0].SpanContext().SpanID() {
+				t.Errorf("traceparent %q does not refer to span %s", traceparent, spans[0].SpanContext().SpanID())
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package otelbugout
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// TracedSpire wraps a spire.SpireCallerContext, creating a span for every operation.
+type TracedSpire struct {
+	Caller spire.SpireCallerContext
+	tracer trace.Tracer
+}
+
+var _ spire.SpireCaller = TracedSpire{}
+var _ spire.SpireCallerContext = TracedSpire{}
+var _ spire.JournalMembersCaller = TracedSpire{}
+var _ spire.JournalMembersCallerContext = TracedSpire{}
+var _ spire.EntryTagsCaller = TracedSpire{}
+var _ spire.EntryTagsCallerContext = TracedSpire{}
+var _ spire.EntryReplaceCaller = TracedSpire{}
+var _ spire.EntryReplaceCallerContext = TracedSpire{}
+var _ spire.ConditionalUpdateCaller = TracedSpire{}
+var _ spire.ConditionalUpdateCallerContext = TracedSpire{}
+
+// InstrumentSpire wraps a Spire caller so that every operation creates a span. To also propagate
+// trace context to Spire, register TraceContextMiddleware on the underlying client (or use
+// InstrumentSpireClient).
+func InstrumentSpire(caller spire.SpireCallerContext, opts ...Option) TracedSpire {
+	return TracedSpire{Caller: caller, tracer: newConfig(opts).tracer()}
+}
+
+func (client TracedSpire) start(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return client.tracer.Start(ctx, "spire."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+func (client TracedSpire) Ping() (string, error) {
+	return client.PingContext(context.Background())
+}
+
+func (client TracedSpire) PingContext(ctx context.Context) (string, error) {
+	ctx, span := client.start(ctx, "Ping")
+	result, err := client.Caller.PingContext(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) CreateJournal(token, name string) (spire.Journal, error) {
+	return client.CreateJournalContext(context.Background(), token, name)
+}
+
+func (client TracedSpire) CreateJournalContext(ctx context.Context, token, name string) (spire.Journal, error) {
+	ctx, span := client.start(ctx, "CreateJournal")
+	result, err := client.Caller.CreateJournalContext(ctx, token, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) GetJournal(token, journalID string) (spire.Journal, error) {
+	return client.GetJournalContext(context.Background(), token, journalID)
+}
+
+func (client TracedSpire) GetJournalContext(ctx context.Context, token, journalID string) (spire.Journal, error) {
+	ctx, span := client.start(ctx, "GetJournal", JournalIDKey.String(journalID))
+	result, err := client.Caller.GetJournalContext(ctx, token, journalID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) ListJournals(token string) (spire.JournalsList, error) {
+	return client.ListJournalsContext(context.Background(), token)
+}
+
+func (client TracedSpire) ListJournalsContext(ctx context.Context, token string) (spire.JournalsList, error) {
+	ctx, span := client.start(ctx, "ListJournals")
+	result, err := client.Caller.ListJournalsContext(ctx, token)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) UpdateJournal(token, journalID, name string) (spire.Journal, error) {
+	return client.UpdateJournalContext(context.Background(), token, journalID, name)
+}
+
+func (client TracedSpire) UpdateJournalContext(ctx context.Context, token, journalID, name string) (spire.Journal, error) {
+	ctx, span := client.start(ctx, "UpdateJournal", JournalIDKey.String(journalID))
+	result, err := client.Caller.UpdateJournalContext(ctx, token, journalID, name)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) DeleteJournal(token, journalID string) (spire.Journal, error) {
+	return client.DeleteJournalContext(context.Background(), token, journalID)
+}
+
+func (client TracedSpire) DeleteJournalContext(ctx context.Context, token, journalID string) (spire.Journal, error) {
+	ctx, span := client.start(ctx, "DeleteJournal", JournalIDKey.String(journalID))
+	result, err := client.Caller.DeleteJournalContext(ctx, token, journalID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) AddJournalMember(token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	return client.AddJournalMemberContext(context.Background(), token, journalID, memberID, memberType, permissions)
+}
+
+func (client TracedSpire) AddJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	ctx, span := client.start(ctx, "AddJournalMember", JournalIDKey.String(journalID), MemberIDKey.String(memberID))
+	result, err := client.Caller.AddJournalMemberContext(ctx, token, journalID, memberID, memberType, permissions)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) RemoveJournalMember(token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	return client.RemoveJournalMemberContext(context.Background(), token, journalID, memberID, memberType, permissions)
+}
+
+func (client TracedSpire) RemoveJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	ctx, span := client.start(ctx, "RemoveJournalMember", JournalIDKey.String(journalID), MemberIDKey.String(memberID))
+	result, err := client.Caller.RemoveJournalMemberContext(ctx, token, journalID, memberID, memberType, permissions)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) ListJournalMembers(token, journalID string) (spire.JournalPermissionsList, error) {
+	return client.ListJournalMembersContext(context.Background(), token, journalID)
+}
+
+func (client TracedSpire) ListJournalMembersContext(ctx context.Context, token, journalID string) (spire.JournalPermissionsList, error) {
+	caller, ok := client.Caller.(spire.JournalMembersCallerContext)
+	if !ok {
+		return spire.JournalPermissionsList{}, utils.UnsupportedError(client.Caller, "ListJournalMembersContext")
+	}
+	ctx, span := client.start(ctx, "ListJournalMembers", JournalIDKey.String(journalID))
+	result, err := caller.ListJournalMembersContext(ctx, token, journalID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) CheckJournalPermission(token, journalID, permission string) (bool, error) {
+	return client.CheckJournalPermissionContext(context.Background(), token, journalID, permission)
+}
+
+func (client TracedSpire) CheckJournalPermissionContext(ctx context.Context, token, journalID, permission string) (bool, error) {
+	caller, ok := client.Caller.(spire.JournalMembersCallerContext)
+	if !ok {
+		return false, utils.UnsupportedError(client.Caller, "CheckJournalPermissionContext")
+	}
+	ctx, span := client.start(ctx, "CheckJournalPermission", JournalIDKey.String(journalID))
+	result, err := caller.CheckJournalPermissionContext(ctx, token, journalID, permission)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) CreateEntry(token, journalID, title, content string, tags []string, entryContext spire.EntryContext) (spire.Entry, error) {
+	return client.CreateEntryContext(context.Background(), token, journalID, title, content, tags, entryContext)
+}
+
+func (client TracedSpire) CreateEntryContext(ctx context.Context, token, journalID, title, content string, tags []string, entryContext spire.EntryContext) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "CreateEntry", JournalIDKey.String(journalID))
+	result, err := client.Caller.CreateEntryContext(ctx, token, journalID, title, content, tags, entryContext)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) DeleteEntry(token, journalID, entryID string) (spire.Entry, error) {
+	return client.DeleteEntryContext(context.Background(), token, journalID, entryID)
+}
+
+func (client TracedSpire) DeleteEntryContext(ctx context.Context, token, journalID, entryID string) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "DeleteEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := client.Caller.DeleteEntryContext(ctx, token, journalID, entryID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) GetEntry(token, journalID, entryID string) (spire.Entry, error) {
+	return client.GetEntryContext(context.Background(), token, journalID, entryID)
+}
+
+func (client TracedSpire) GetEntryContext(ctx context.Context, token, journalID, entryID string) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "GetEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := client.Caller.GetEntryContext(ctx, token, journalID, entryID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) ListEntries(token, journalID string, limit, offset int) (spire.EntryResultsPage, error) {
+	return client.ListEntriesContext(context.Background(), token, journalID, limit, offset)
+}
+
+func (client TracedSpire) ListEntriesContext(ctx context.Context, token, journalID string, limit, offset int) (spire.EntryResultsPage, error) {
+	ctx, span := client.start(ctx, "ListEntries", JournalIDKey.String(journalID))
+	result, err := client.Caller.ListEntriesContext(ctx, token, journalID, limit, offset)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) SearchEntries(token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (spire.EntryResultsPage, error) {
+	return client.SearchEntriesContext(context.Background(), token, journalID, searchQuery, limit, offset, queryParameters)
+}
+
+func (client TracedSpire) SearchEntriesContext(ctx context.Context, token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (spire.EntryResultsPage, error) {
+	ctx, span := client.start(ctx, "SearchEntries", JournalIDKey.String(journalID))
+	result, err := client.Caller.SearchEntriesContext(ctx, token, journalID, searchQuery, limit, offset, queryParameters)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) TagEntry(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	return client.TagEntryContext(context.Background(), token, journalID, entryID, tags)
+}
+
+func (client TracedSpire) TagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "TagEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := client.Caller.TagEntryContext(ctx, token, journalID, entryID, tags)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) UntagEntry(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	return client.UntagEntryContext(context.Background(), token, journalID, entryID, tags)
+}
+
+func (client TracedSpire) UntagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "UntagEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := client.Caller.UntagEntryContext(ctx, token, journalID, entryID, tags)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) SetEntryTags(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	return client.SetEntryTagsContext(context.Background(), token, journalID, entryID, tags)
+}
+
+func (client TracedSpire) SetEntryTagsContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	caller, ok := client.Caller.(spire.EntryTagsCallerContext)
+	if !ok {
+		return spire.Entry{}, utils.UnsupportedError(client.Caller, "SetEntryTagsContext")
+	}
+	ctx, span := client.start(ctx, "SetEntryTags", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := caller.SetEntryTagsContext(ctx, token, journalID, entryID, tags)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) UpdateEntry(token, journalID, entryID, title, content string) (spire.Entry, error) {
+	return client.UpdateEntryContext(context.Background(), token, journalID, entryID, title, content)
+}
+
+func (client TracedSpire) UpdateEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (spire.Entry, error) {
+	ctx, span := client.start(ctx, "UpdateEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := client.Caller.UpdateEntryContext(ctx, token, journalID, entryID, title, content)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) ReplaceEntry(token, journalID, entryID, title, content string) (spire.Entry, error) {
+	return client.ReplaceEntryContext(context.Background(), token, journalID, entryID, title, content)
+}
+
+func (client TracedSpire) ReplaceEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (spire.Entry, error) {
+	caller, ok := client.Caller.(spire.EntryReplaceCallerContext)
+	if !ok {
+		return spire.Entry{}, utils.UnsupportedError(client.Caller, "ReplaceEntryContext")
+	}
+	ctx, span := client.start(ctx, "ReplaceEntry", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := caller.ReplaceEntryContext(ctx, token, journalID, entryID, title, content)
+	endSpan(span, err)
+	return result, err
+}
+
+func (client TracedSpire) UpdateEntryIfUnchanged(token, journalID, entryID, title, content string, version spire.EntryVersion) (spire.Entry, error) {
+	return client.UpdateEntryIfUnchangedContext(context.Background(), token, journalID, entryID, title, content, version)
+}
+
+func (client TracedSpire) UpdateEntryIfUnchangedContext(ctx context.Context, token, journalID, entryID, title, content string, version spire.EntryVersion) (spire.Entry, error) {
+	caller, ok := client.Caller.(spire.ConditionalUpdateCallerContext)
+	if !ok {
+		return spire.Entry{}, utils.UnsupportedError(client.Caller, "UpdateEntryIfUnchangedContext")
+	}
+	ctx, span := client.start(ctx, "UpdateEntryIfUnchanged", JournalIDKey.String(journalID), EntryIDKey.String(entryID))
+	result, err := caller.UpdateEntryIfUnchangedContext(ctx, token, journalID, entryID, title, content, version)
+	endSpan(span, err)
+	return result, err
+}
@@ -0,0 +1,245 @@
+// Package recorder records HTTP traffic between Bugout clients and the Bugout APIs to cassette
+// files, and replays it back, so that tests can run deterministically without network access.
+//
+// A Recorder is an http.RoundTripper. Plug it into a client with spire.WithTransport or
+// brood.WithTransport (or set it as the Transport of the client's HTTPClient):
+//
+//	rec, err := recorder.New("testdata/create_entry.json", recorder.ModeReplay)
+//	client := spire.NewClientWithOptions(spireURL, spire.WithTransport(rec))
+//
+// Authorization headers, passwords and access tokens are scrubbed before anything is written to a
+// cassette.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Current version of the cassette file format.
+const CassetteVersion = 1
+
+type Mode int
+
+const (
+	// ModeRecord sends requests to the real API and records every interaction.
+	ModeRecord Mode = iota
+	// ModeReplay serves responses from the cassette and never touches the network. Requests which
+	// do not match a recorded interaction fail.
+	ModeReplay
+)
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records or replays HTTP interactions. It is safe for concurrent use.
+type Recorder struct {
+	Mode Mode
+	// Path of the cassette file.
+	Path string
+	// Transport used to send requests in ModeRecord. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Scrubber removes credentials from interactions before they are stored or matched.
+	Scrubber *Scrubber
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// New creates a recorder for the cassette at the given path. In ModeReplay, the cassette is loaded
+// immediately and must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	recorder := &Recorder{
+		Mode:     mode,
+		Path:     path,
+		Scrubber: NewScrubber(),
+		cassette: Cassette{Version: CassetteVersion, Interactions: []Interaction{}},
+	}
+	if mode != ModeReplay {
+		return recorder, nil
+	}
+
+	contents, readErr := ioutil.ReadFile(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	var cassette Cassette
+	decodeErr := json.Unmarshal(contents, &cassette)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("Could not parse cassette (%s): %s", path, decodeErr.Error())
+	}
+	if cassette.Version != CassetteVersion {
+		return nil, fmt.Errorf("Unsupported cassette version (%s): %d", path, cassette.Version)
+	}
+	recorder.cassette = cassette
+	recorder.replayed = make([]bool, len(cassette.Interactions))
+	return recorder, nil
+}
+
+// Client returns an HTTP client which sends its requests through the recorder.
+func (recorder *Recorder) Client() *http.Client {
+	return &http.Client{Transport: recorder}
+}
+
+func (recorder *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request they are given.
+	request = request.Clone(request.Context())
+	body, bodyErr := readRequestBody(request)
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
+	recorded := recorder.Scrubber.scrubRequest(request, body)
+
+	if recorder.Mode == ModeReplay {
+		return recorder.replay(request, recorded)
+	}
+	return recorder.record(request, recorded)
+}
+
+func (recorder *Recorder) record(request *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := recorder.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		return response, err
+	}
+
+	responseBody, readErr := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: recorder.Scrubber.scrubResponse(response, responseBody),
+	})
+	return response, nil
+}
+
+func (recorder *Recorder) replay(request *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	for i, interaction := range recorder.cassette.Interactions {
+		if recorder.replayed[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		recorder.replayed[i] = true
+
+		header := http.Header{}
+		for key, values := range interaction.Response.Header {
+			header[key] = append([]string{}, values...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("No recorded interaction matches request (%s): %s %s", recorder.Path, recorded.Method, recorded.URL)
+}
+
+// Interactions are matched on method, URL and body, after scrubbing.
+func matches(recorded, request RecordedRequest) bool {
+	return recorded.Method == request.Method && recorded.URL == request.URL && recorded.Body == request.Body
+}
+
+// Unreplayed returns the recorded interactions which have not been replayed yet. Tests can use it
+// to make sure that the code under test made every request it was expected to.
+func (recorder *Recorder) Unreplayed() []Interaction {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	interactions := []Interaction{}
+	if recorder.Mode != ModeReplay {
+		return interactions
+	}
+	for i, interaction := range recorder.cassette.Interactions {
+		if !recorder.replayed[i] {
+			interactions = append(interactions, interaction)
+		}
+	}
+	return interactions
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in ModeReplay.
+//
+// Secrets are scrubbed from every interaction once more before saving, so that a token which was
+// only learned from a later interaction (like an access token returned by Brood) does not leak
+// through an earlier one.
+func (recorder *Recorder) Save() error {
+	if recorder.Mode == ModeReplay {
+		return nil
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	cassette := Cassette{Version: CassetteVersion, Interactions: []Interaction{}}
+	for _, interaction := range recorder.cassette.Interactions {
+		cassette.Interactions = append(cassette.Interactions, recorder.Scrubber.scrubInteraction(interaction))
+	}
+
+	contents, encodeErr := json.MarshalIndent(cassette, "", "  ")
+	if encodeErr != nil {
+		return encodeErr
+	}
+	if dir := filepath.Dir(recorder.Path); dir != "" {
+		if mkdirErr := os.MkdirAll(dir, 0755); mkdirErr != nil {
+			return mkdirErr
+		}
+	}
+	return ioutil.WriteFile(recorder.Path, contents, 0644)
+}
+
+func readRequestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil, nil
+	}
+	body, readErr := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
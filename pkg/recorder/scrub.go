@@ -0,0 +1,187 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces scrubbed values in cassettes.
+const Redacted = "REDACTED"
+
+// Scrubber removes credentials from recorded interactions.
+//
+// Headers listed in Headers are dropped, and the values of form fields and JSON keys listed in
+// Fields are replaced with Redacted. In addition, the scrubber learns every access token it sees
+// (in Authorization headers and access_token fields) and replaces it with Redacted wherever it
+// appears, including URLs and response bodies.
+type Scrubber struct {
+	Headers []string
+	Fields  []string
+
+	mu      sync.Mutex
+	secrets []string
+}
+
+func NewScrubber() *Scrubber {
+	return &Scrubber{
+		Headers: []string{"Authorization", "Cookie", "Set-Cookie"},
+		Fields:  []string{"password", "current_password", "new_password", "access_token", "verification_code"},
+	}
+}
+
+// AddSecret makes the scrubber replace the given value wherever it appears.
+func (scrubber *Scrubber) AddSecret(secret string) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" || secret == Redacted {
+		return
+	}
+	scrubber.mu.Lock()
+	defer scrubber.mu.Unlock()
+	for _, known := range scrubber.secrets {
+		if known == secret {
+			return
+		}
+	}
+	scrubber.secrets = append(scrubber.secrets, secret)
+}
+
+func (scrubber *Scrubber) replaceSecrets(value string) string {
+	scrubber.mu.Lock()
+	defer scrubber.mu.Unlock()
+	for _, secret := range scrubber.secrets {
+		value = strings.Replace(value, secret, Redacted, -1)
+	}
+	return value
+}
+
+func (scrubber *Scrubber) isScrubbedField(key string) bool {
+	for _, field := range scrubber.Fields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (scrubber *Scrubber) scrubHeader(header http.Header) http.Header {
+	scrubbed := http.Header{}
+	for key, values := range header {
+		isScrubbed := false
+		for _, scrubbedKey := range scrubber.Headers {
+			if strings.EqualFold(key, scrubbedKey) {
+				isScrubbed = true
+			}
+		}
+		if isScrubbed {
+			continue
+		}
+		for _, value := range values {
+			scrubbed.Add(key, scrubber.replaceSecrets(value))
+		}
+	}
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
+
+// Replaces scrubbed fields anywhere in a decoded JSON value, learning any access tokens on the way.
+func (scrubber *Scrubber) scrubJSONValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			if stringValue, isString := child.(string); isString && scrubber.isScrubbedField(key) {
+				if key == "access_token" {
+					scrubber.AddSecret(stringValue)
+				}
+				typedValue[key] = Redacted
+				continue
+			}
+			typedValue[key] = scrubber.scrubJSONValue(child)
+		}
+	case []interface{}:
+		for i, child := range typedValue {
+			typedValue[i] = scrubber.scrubJSONValue(child)
+		}
+	}
+	return value
+}
+
+func (scrubber *Scrubber) scrubBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var decoded interface{}
+		if json.Unmarshal(trimmed, &decoded) == nil {
+			scrubbed, encodeErr := json.Marshal(scrubber.scrubJSONValue(decoded))
+			if encodeErr == nil {
+				return scrubber.replaceSecrets(string(scrubbed))
+			}
+		}
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, parseErr := url.ParseQuery(string(body)); parseErr == nil {
+			for key := range values {
+				if !scrubber.isScrubbedField(key) {
+					continue
+				}
+				if key == "access_token" {
+					scrubber.AddSecret(values.Get(key))
+				}
+				values.Set(key, Redacted)
+			}
+			return scrubber.replaceSecrets(values.Encode())
+		}
+	}
+
+	return scrubber.replaceSecrets(string(body))
+}
+
+func (scrubber *Scrubber) scrubRequest(request *http.Request, body []byte) RecordedRequest {
+	authorization := request.Header.Get("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		scrubber.AddSecret(authorization[7:])
+	}
+
+	// The body is scrubbed first, since that is where we may learn new secrets from.
+	scrubbedBody := scrubber.scrubBody(body, request.Header.Get("Content-Type"))
+	return RecordedRequest{
+		Method: request.Method,
+		URL:    scrubber.replaceSecrets(request.URL.String()),
+		Header: scrubber.scrubHeader(request.Header),
+		Body:   scrubbedBody,
+	}
+}
+
+func (scrubber *Scrubber) scrubResponse(response *http.Response, body []byte) RecordedResponse {
+	scrubbedBody := scrubber.scrubBody(body, response.Header.Get("Content-Type"))
+	return RecordedResponse{
+		StatusCode: response.StatusCode,
+		Header:     scrubber.scrubHeader(response.Header),
+		Body:       scrubbedBody,
+	}
+}
+
+func (scrubber *Scrubber) scrubInteraction(interaction Interaction) Interaction {
+	return Interaction{
+		Request: RecordedRequest{
+			Method: interaction.Request.Method,
+			URL:    scrubber.replaceSecrets(interaction.Request.URL),
+			Header: scrubber.scrubHeader(interaction.Request.Header),
+			Body:   scrubber.replaceSecrets(interaction.Request.Body),
+		},
+		Response: RecordedResponse{
+			StatusCode: interaction.Response.StatusCode,
+			Header:     scrubber.scrubHeader(interaction.Response.Header),
+			Body:       scrubber.replaceSecrets(interaction.Response.Body),
+		},
+	}
+}
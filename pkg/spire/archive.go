@@ -0,0 +1,319 @@
+package spire
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// Current version of the journal archive format.
+const ArchiveVersion int = 1
+
+// Types of records in a journal archive
+const (
+	ArchiveRecordHeader string = "header"
+	ArchiveRecordScope  string = "scope"
+	ArchiveRecordEntry  string = "entry"
+)
+
+// ArchiveRecord is a single line of a journal archive. An archive is a JSON Lines file which starts
+// with a header record holding the archive version and the journal, followed by a scope record for
+// each permission held on the journal and an entry record for each entry, oldest first.
+type ArchiveRecord struct {
+	Type string `json:"type"`
+
+	// Header records only
+	Version    int      `json:"version,omitempty"`
+	ExportedAt string   `json:"exported_at,omitempty"`
+	Journal    *Journal `json:"journal,omitempty"`
+
+	Scope *JournalPermission `json:"scope,omitempty"`
+	Entry *Entry             `json:"entry,omitempty"`
+}
+
+type ExportOptions struct {
+	// Entries with these IDs are not written. Used to resume an interrupted export.
+	SkipEntries map[string]bool
+	// If true, only entry records are written. Used to append to an archive whose header and
+	// scopes have already been written.
+	EntriesOnly bool
+}
+
+type ExportSummary struct {
+	Scopes  int `json:"scopes"`
+	Entries int `json:"entries"`
+	Skipped int `json:"skipped"`
+}
+
+// ExportJournal writes the journal, its member scopes and all of its entries to w as an archive.
+// Unless options.EntriesOnly is set, the client must implement JournalMembersCallerContext.
+func ExportJournal(ctx context.Context, client SpireCallerContext, token, journalID string, w io.Writer, options ExportOptions) (ExportSummary, error) {
+	summary := ExportSummary{}
+	encoder := json.NewEncoder(w)
+
+	if !options.EntriesOnly {
+		members, ok := client.(JournalMembersCallerContext)
+		if !ok {
+			return summary, utils.UnsupportedError(client, "ListJournalMembersContext")
+		}
+		journal, journalErr := client.GetJournalContext(ctx, token, journalID)
+		if journalErr != nil {
+			return summary, journalErr
+		}
+		scopes, scopesErr := members.ListJournalMembersContext(ctx, token, journalID)
+		if scopesErr != nil {
+			return summary, scopesErr
+		}
+
+		header := ArchiveRecord{
+			Type:       ArchiveRecordHeader,
+			Version:    ArchiveVersion,
+			ExportedAt: time.Now().UTC().Format(time.RFC3339),
+			Journal:    &journal,
+		}
+		if encodeErr := encoder.Encode(header); encodeErr != nil {
+			return summary, encodeErr
+		}
+		for i := range scopes.Scopes {
+			if encodeErr := encoder.Encode(ArchiveRecord{Type: ArchiveRecordScope, Scope: &scopes.Scopes[i]}); encodeErr != nil {
+				return summary, encodeErr
+			}
+			summary.Scopes++
+		}
+	}
+
+	iterator := NewEntryIteratorContext(ctx, client, token, journalID, "", map[string]string{"order": SearchOrderAscending})
+	for iterator.Next() {
+		entry := iterator.Entry()
+		if options.SkipEntries[entry.Id] {
+			summary.Skipped++
+			continue
+		}
+		// URLs and scores are specific to the deployment and the search, so they are left out.
+		entry.Url = ""
+		entry.JournalURL = ""
+		entry.Score = 0
+		if encodeErr := encoder.Encode(ArchiveRecord{Type: ArchiveRecordEntry, Entry: &entry}); encodeErr != nil {
+			return summary, encodeErr
+		}
+		summary.Entries++
+	}
+	return summary, iterator.Err()
+}
+
+type ImportOptions struct {
+	// Journal to import into. If empty, a new journal is created.
+	JournalID string
+	// Name of the journal to create. Defaults to the name of the archived journal.
+	Name string
+	// If true, the archived member scopes are granted on the journal.
+	ImportMembers bool
+	// If true, the member scopes were already granted by a previous import and are not granted
+	// again. Used to resume an interrupted import.
+	MembersImported bool
+	// Maps the IDs of archived holders to the IDs of the corresponding users and groups on the
+	// target deployment. Holders mapped to "" are left out, and unmapped holders keep their IDs.
+	HolderIDs map[string]string
+	// Maps the IDs of archived entries which were already imported to the IDs of the entries
+	// created for them. These entries are skipped. Used to resume an interrupted import.
+	ImportedEntries map[string]string
+	// If true, the archive is read and checked, but nothing is created.
+	DryRun bool
+	// Called as soon as the journal to import into is known (in particular, right after it is
+	// created), before anything is imported into it.
+	OnJournal func(journalID string) error
+	// Called after the member scopes have been granted on the journal (with ImportMembers).
+	OnMembers func(journalID string) error
+	// Called after each entry is created, with the journal, the ID of the archived entry and the new
+	// entry.
+	OnEntry func(journalID, archivedID string, entry Entry) error
+}
+
+type ImportSummary struct {
+	// The journal the archive was imported into (empty for a dry run which would create one)
+	JournalID string `json:"journal_id"`
+	// Name of the archived journal
+	ArchivedName string `json:"archived_name"`
+	Scopes       int    `json:"scopes"`
+	Entries      int    `json:"entries"`
+	Skipped      int    `json:"skipped"`
+	// Maps the IDs of archived entries to the IDs of the entries created for them, including
+	// entries imported previously
+	EntryIDs map[string]string `json:"entry_ids"`
+}
+
+// ImportJournal reads an archive written by ExportJournal and recreates its entries (and,
+// optionally, its member scopes) in a journal. Entries are created in the order in which they were
+// archived. Spire assigns new IDs and creation times to imported entries; the mapping from archived
+// to new entry IDs is returned in the summary.
+//
+// The import stops at the first error, including errors returned by the callbacks in the options.
+// It can be resumed by passing the journal ID (as reported to OnJournal), whether the member scopes
+// were granted (OnMembers) and the entry IDs imported so far (OnEntry) in the options.
+func ImportJournal(ctx context.Context, client SpireCallerContext, token string, r io.Reader, options ImportOptions) (ImportSummary, error) {
+	summary := ImportSummary{JournalID: options.JournalID, EntryIDs: map[string]string{}}
+	for archivedID, entryID := range options.ImportedEntries {
+		summary.EntryIDs[archivedID] = entryID
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	lineNumber := 0
+	scopes := map[string]*journalPermissionsRequest{}
+	holderOrder := []string{}
+	headerRead := false
+	// Whether the journal has been created (if necessary) and the scopes granted on it
+	prepared := false
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record ArchiveRecord
+		if decodeErr := json.Unmarshal(scanner.Bytes(), &record); decodeErr != nil {
+			return summary, fmt.Errorf("Could not parse archive record on line %d: %s", lineNumber, decodeErr.Error())
+		}
+
+		if !headerRead {
+			if record.Type != ArchiveRecordHeader || record.Journal == nil {
+				return summary, fmt.Errorf("Archive does not start with a header record")
+			}
+			if record.Version != ArchiveVersion {
+				return summary, fmt.Errorf("Unsupported archive version: %d", record.Version)
+			}
+			summary.ArchivedName = record.Journal.Name
+			headerRead = true
+			continue
+		}
+
+		switch record.Type {
+		case ArchiveRecordScope:
+			if record.Scope == nil {
+				return summary, fmt.Errorf("Scope record without a scope on line %d", lineNumber)
+			}
+			if !options.ImportMembers {
+				continue
+			}
+			holderID := record.Scope.HolderID
+			if mappedID, exists := options.HolderIDs[holderID]; exists {
+				holderID = mappedID
+			}
+			if holderID == "" {
+				continue
+			}
+			if _, exists := scopes[holderID]; !exists {
+				scopes[holderID] = &journalPermissionsRequest{HolderID: holderID, HolderType: record.Scope.HolderType}
+				holderOrder = append(holderOrder, holderID)
+			}
+			scopes[holderID].Permissions = append(scopes[holderID].Permissions, record.Scope.Permission)
+
+		case ArchiveRecordEntry:
+			if record.Entry == nil {
+				return summary, fmt.Errorf("Entry record without an entry on line %d", lineNumber)
+			}
+			if !prepared && !options.DryRun {
+				journalErr := importJournalAndScopes(ctx, client, token, options, &summary, scopes, holderOrder)
+				if journalErr != nil {
+					return summary, journalErr
+				}
+				prepared = true
+			}
+
+			archived := *record.Entry
+			if _, imported := summary.EntryIDs[archived.Id]; imported {
+				summary.Skipped++
+				continue
+			}
+			if options.DryRun {
+				summary.Entries++
+				continue
+			}
+
+			tags := archived.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			entryContext := EntryContext{ContextType: archived.ContextType, ContextID: archived.ContextID, ContextURL: archived.ContextUrl}
+			entry, entryErr := client.CreateEntryContext(ctx, token, summary.JournalID, archived.Title, archived.Content, tags, entryContext)
+			if entryErr != nil {
+				return summary, fmt.Errorf("Could not import entry %s (line %d): %s", archived.Id, lineNumber, entryErr.Error())
+			}
+			summary.EntryIDs[archived.Id] = entry.Id
+			summary.Entries++
+			if options.OnEntry != nil {
+				if callbackErr := options.OnEntry(summary.JournalID, archived.Id, entry); callbackErr != nil {
+					return summary, callbackErr
+				}
+			}
+
+		default:
+			return summary, fmt.Errorf("Unknown archive record type on line %d: %s", lineNumber, record.Type)
+		}
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return summary, scanErr
+	}
+	if !headerRead {
+		return summary, fmt.Errorf("Archive is empty")
+	}
+
+	// Journals without entries still need to be created.
+	if options.DryRun {
+		if !options.MembersImported {
+			for _, holderID := range holderOrder {
+				summary.Scopes += len(scopes[holderID].Permissions)
+			}
+		}
+	} else if !prepared {
+		journalErr := importJournalAndScopes(ctx, client, token, options, &summary, scopes, holderOrder)
+		if journalErr != nil {
+			return summary, journalErr
+		}
+	}
+	return summary, nil
+}
+
+// Creates the journal to import into if necessary, and grants the archived scopes on it unless they
+// were granted before. Scopes always precede entries in an archive, so they have all been read by
+// the time this is called.
+func importJournalAndScopes(ctx context.Context, client SpireCallerContext, token string, options ImportOptions, summary *ImportSummary, scopes map[string]*journalPermissionsRequest, holderOrder []string) error {
+	if options.JournalID == "" {
+		name := options.Name
+		if name == "" {
+			name = summary.ArchivedName
+		}
+		journal, journalErr := client.CreateJournalContext(ctx, token, name)
+		if journalErr != nil {
+			return journalErr
+		}
+		summary.JournalID = journal.Id
+	} else {
+		summary.JournalID = options.JournalID
+	}
+	if options.OnJournal != nil {
+		if callbackErr := options.OnJournal(summary.JournalID); callbackErr != nil {
+			return callbackErr
+		}
+	}
+
+	if !options.ImportMembers || options.MembersImported {
+		return nil
+	}
+	for _, holderID := range holderOrder {
+		scope := scopes[holderID]
+		_, addErr := client.AddJournalMemberContext(ctx, token, summary.JournalID, scope.HolderID, scope.HolderType, scope.Permissions)
+		if addErr != nil {
+			return fmt.Errorf("Could not grant permissions to %s %s: %s", scope.HolderType, scope.HolderID, addErr.Error())
+		}
+		summary.Scopes += len(scope.Permissions)
+	}
+	if options.OnMembers != nil {
+		return options.OnMembers(summary.JournalID)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package spire_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+// Counts the member scope requests made through a Fake.
+type memberCountingFake struct {
+	*spiretest.Fake
+	grants int
+}
+
+func (client *memberCountingFake) AddJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	client.grants++
+	return client.Fake.AddJournalMemberContext(ctx, token, journalID, memberID, memberType, permissions)
+}
+
+func newArchive(t *testing.T, fake *spiretest.Fake, entries int) []byte {
+	t.Helper()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "archived")
+	if _, err := fake.AddJournalMember("token", journal.Id, "member", "user", []string{"journals.read"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < entries; i++ {
+		if _, err := fake.SeedEntry(journal.Id, spire.Entry{Title: fmt.Sprintf("entry %d", i), Content: "content"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var archive bytes.Buffer
+	if _, err := spire.ExportJournal(context.Background(), fake, "token", journal.Id, &archive, spire.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return archive.Bytes()
+}
+
+func TestImportJournalResume(t *testing.T) {
+	fake := spiretest.NewFake()
+	archive := newArchive(t, fake, 3)
+	client := &memberCountingFake{Fake: fake}
+
+	// The first import fails when the second entry cannot be recorded.
+	recordedJournalID := ""
+	recordedMembers := false
+	recordedEntries := map[string]string{}
+	writeFailure := errors.New("disk full")
+	options := spire.ImportOptions{
+		ImportMembers: true,
+		OnJournal: func(journalID string) error {
+			if len(recordedEntries) > 0 || recordedMembers {
+				t.Errorf("OnJournal called after the journal was used")
+			}
+			recordedJournalID = journalID
+			return nil
+		},
+		OnMembers: func(journalID string) error {
+			if journalID != recordedJournalID {
+				t.Errorf("OnMembers called for journal %s instead of %s", journalID, recordedJournalID)
+			}
+			recordedMembers = true
+			return nil
+		},
+		OnEntry: func(journalID, archivedID string, entry spire.Entry) error {
+			if len(recordedEntries) == 1 {
+				return writeFailure
+			}
+			recordedEntries[archivedID] = entry.Id
+			return nil
+		},
+	}
+	summary, err := spire.ImportJournal(context.Background(), client, "token", bytes.NewReader(archive), options)
+	if err != writeFailure {
+		t.Fatalf("Expected the OnEntry error, got %v", err)
+	}
+	if recordedJournalID == "" || summary.JournalID != recordedJournalID {
+		t.Fatalf("Expected OnJournal to report journal %q, got %q", summary.JournalID, recordedJournalID)
+	}
+	if !recordedMembers || client.grants == 0 {
+		t.Fatalf("Expected member scopes to be granted and reported")
+	}
+	if len(recordedEntries) != 1 || len(fake.Entries(recordedJournalID)) != 2 {
+		t.Fatalf("Expected 1 recorded and 2 created entries, got %d and %d", len(recordedEntries), len(fake.Entries(recordedJournalID)))
+	}
+
+	// Resuming from what was recorded neither creates a journal nor grants scopes again.
+	journals := len(fake.Journals())
+	grants := client.grants
+	resumed := spire.ImportOptions{
+		JournalID:       recordedJournalID,
+		ImportMembers:   true,
+		MembersImported: recordedMembers,
+		ImportedEntries: recordedEntries,
+		OnJournal: func(journalID string) error {
+			if journalID != recordedJournalID {
+				t.Errorf("OnJournal called for journal %s instead of %s", journalID, recordedJournalID)
+			}
+			return nil
+		},
+		OnMembers: func(journalID string) error {
+			t.Errorf("OnMembers called although the scopes were already granted")
+			return nil
+		},
+	}
+	summary, err = spire.ImportJournal(context.Background(), client, "token", bytes.NewReader(archive), resumed)
+	if err != nil {
+		t.Fatalf("Unexpected error resuming the import: %v", err)
+	}
+	if len(fake.Journals()) != journals {
+		t.Errorf("Expected no new journal, got %d journals instead of %d", len(fake.Journals()), journals)
+	}
+	if client.grants != grants || summary.Scopes != 0 {
+		t.Errorf("Expected no scopes to be granted again, got %d requests for %d scopes", client.grants-grants, summary.Scopes)
+	}
+	if summary.Skipped != 1 || summary.Entries != 2 {
+		t.Errorf("Expected 1 skipped and 2 imported entries, got %d and %d", summary.Skipped, summary.Entries)
+	}
+}
+
+func TestImportJournalCallbackErrors(t *testing.T) {
+	fake := spiretest.NewFake()
+	archive := newArchive(t, fake, 1)
+	// Without entry records, the journal is only created once the whole archive has been read.
+	headerOnly := bytes.SplitAfter(archive, []byte("\n"))[0]
+
+	cases := []struct {
+		name    string
+		archive []byte
+		options func(err error) spire.ImportOptions
+		grants  int
+		entries int
+	}{
+		{
+			name:    "journal",
+			archive: archive,
+			options: func(err error) spire.ImportOptions {
+				return spire.ImportOptions{ImportMembers: true, OnJournal: func(string) error { return err }}
+			},
+			grants:  0,
+			entries: 0,
+		},
+		{
+			name:    "members",
+			archive: archive,
+			options: func(err error) spire.ImportOptions {
+				return spire.ImportOptions{ImportMembers: true, OnMembers: func(string) error { return err }}
+			},
+			grants:  2,
+			entries: 0,
+		},
+		{
+			name:    "empty journal",
+			archive: headerOnly,
+			options: func(err error) spire.ImportOptions {
+				return spire.ImportOptions{OnJournal: func(string) error { return err }}
+			},
+			grants:  0,
+			entries: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &memberCountingFake{Fake: fake}
+			callbackErr := errors.New(c.name)
+			summary, err := spire.ImportJournal(context.Background(), client, "token", bytes.NewReader(c.archive), c.options(callbackErr))
+			if err != callbackErr {
+				t.Fatalf("Expected the callback error, got %v", err)
+			}
+			if client.grants != c.grants {
+				t.Errorf("Expected %d scope requests, got %d", c.grants, client.grants)
+			}
+			if summary.Entries != c.entries {
+				t.Errorf("Expected %d imported entries, got %d", c.entries, summary.Entries)
+			}
+		})
+	}
+}
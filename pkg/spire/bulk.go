@@ -0,0 +1,155 @@
+package spire
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default number of concurrent requests made by CreateEntries
+const DefaultBulkParallelism int = 4
+
+// EntrySpec describes an entry to be created by CreateEntries. Its JSON representation is the
+// format of each line read by "bugout entries import".
+type EntrySpec struct {
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags"`
+	ContextType string   `json:"context_type,omitempty"`
+	ContextID   string   `json:"context_id,omitempty"`
+	ContextURL  string   `json:"context_url,omitempty"`
+}
+
+// BulkOptions controls how CreateEntries spreads its requests over time.
+type BulkOptions struct {
+	// Maximum number of requests in flight at once (defaults to DefaultBulkParallelism)
+	Parallelism int
+	// Maximum number of requests started per second. Zero means no limit.
+	RateLimit float64
+}
+
+// EntryResult reports the outcome of creating a single entry. Exactly one of Entry and Err is set:
+// Err is an *APIError if Spire rejected the request, and the context's error if the context was
+// done before the request was made.
+type EntryResult struct {
+	// Position of the spec in the input slice or channel
+	Index int
+	Spec  EntrySpec
+	Entry Entry
+	Err   error
+}
+
+// CreateEntries creates an entry for each spec, making up to options.Parallelism requests at once.
+// Failures do not stop the other requests. The results are returned in the same order as the specs.
+func CreateEntries(ctx context.Context, client SpireCallerContext, token, journalID string, specs []EntrySpec, options BulkOptions) []EntryResult {
+	specsChannel := make(chan EntrySpec)
+	go func() {
+		defer close(specsChannel)
+		for _, spec := range specs {
+			specsChannel <- spec
+		}
+	}()
+
+	results := make([]EntryResult, len(specs))
+	for result := range CreateEntriesFromChannel(ctx, client, token, journalID, specsChannel, options) {
+		results[result.Index] = result
+	}
+	return results
+}
+
+// CreateEntriesFromChannel creates an entry for each spec received on the specs channel until it is
+// closed, and sends a result for each of them on the returned channel, in the order in which the
+// requests complete. The returned channel is closed once every spec has been processed.
+//
+// Once the context is done, the remaining specs are still read from the channel, but are reported
+// as failed with the context's error instead of being sent to Spire.
+func CreateEntriesFromChannel(ctx context.Context, client SpireCallerContext, token, journalID string, specs <-chan EntrySpec, options BulkOptions) <-chan EntryResult {
+	limiter := newRateLimiter(options.RateLimit)
+
+	type indexedSpec struct {
+		index int
+		spec  EntrySpec
+	}
+	indexedSpecs := make(chan indexedSpec)
+	go func() {
+		defer close(indexedSpecs)
+		index := 0
+		for spec := range specs {
+			indexedSpecs <- indexedSpec{index: index, spec: spec}
+			index++
+		}
+	}()
+
+	results := make(chan EntryResult)
+	var wg sync.WaitGroup
+	for i := 0; i < options.parallelism(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range indexedSpecs {
+				result := EntryResult{Index: item.index, Spec: item.spec}
+				if ctxErr := limiter.wait(ctx); ctxErr != nil {
+					result.Err = ctxErr
+				} else {
+					tags := item.spec.Tags
+					if tags == nil {
+						tags = []string{}
+					}
+					entryContext := EntryContext{
+						ContextType: item.spec.ContextType,
+						ContextID:   item.spec.ContextID,
+						ContextURL:  item.spec.ContextURL,
+					}
+					result.Entry, result.Err = client.CreateEntryContext(ctx, token, journalID, item.spec.Title, item.spec.Content, tags, entryContext)
+				}
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		limiter.stop()
+		close(results)
+	}()
+
+	return results
+}
+
+func (options BulkOptions) parallelism() int {
+	if options.Parallelism <= 0 {
+		return DefaultBulkParallelism
+	}
+	return options.Parallelism
+}
+
+// Spaces out the requests made by bulk operations. Tickets are handed out at a fixed rate, and each
+// request waits for one.
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// A rate of zero or less means no limit.
+func newRateLimiter(rate float64) rateLimiter {
+	if rate <= 0 {
+		return rateLimiter{}
+	}
+	return rateLimiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / rate))}
+}
+
+// Blocks until the next request may be made. Returns the context's error if it is done first.
+func (limiter rateLimiter) wait(ctx context.Context) error {
+	if limiter.ticker != nil && ctx.Err() == nil {
+		select {
+		case <-limiter.ticker.C:
+		case <-ctx.Done():
+		}
+	}
+	return ctx.Err()
+}
+
+func (limiter rateLimiter) stop() {
+	if limiter.ticker != nil {
+		limiter.ticker.Stop()
+	}
+}
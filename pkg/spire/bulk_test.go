@@ -0,0 +1,244 @@
+package spire_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+// Controls the entry creation requests made through a Fake.
+type creatingFake struct {
+	*spiretest.Fake
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	created     int
+	// Called before each request is passed on to the Fake. An error fails the request.
+	before func(title string) error
+}
+
+func (client *creatingFake) CreateEntryContext(ctx context.Context, token, journalID, title, content string, tags []string, entryContext spire.EntryContext) (spire.Entry, error) {
+	client.mu.Lock()
+	client.inFlight++
+	if client.inFlight > client.maxInFlight {
+		client.maxInFlight = client.inFlight
+	}
+	client.mu.Unlock()
+	defer func() {
+		client.mu.Lock()
+		client.inFlight--
+		client.created++
+		client.mu.Unlock()
+	}()
+
+	if client.before != nil {
+		if err := client.before(title); err != nil {
+			return spire.Entry{}, err
+		}
+	}
+	return client.Fake.CreateEntryContext(ctx, token, journalID, title, content, tags, entryContext)
+}
+
+func newBulkJournal(t *testing.T) (*creatingFake, string) {
+	t.Helper()
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "bulk")
+	return &creatingFake{Fake: fake}, journal.Id
+}
+
+func entrySpecs(titles ...string) []spire.EntrySpec {
+	specs := make([]spire.EntrySpec, len(titles))
+	for i, title := range titles {
+		specs[i] = spire.EntrySpec{Title: title, Content: "content of " + title, Tags: []string{"bulk"}}
+	}
+	return specs
+}
+
+func TestCreateEntriesPartialFailure(t *testing.T) {
+	client, journalID := newBulkJournal(t)
+	rejection := &spire.APIError{StatusCode: http.StatusBadRequest, Detail: "invalid entry"}
+	client.before = func(title string) error {
+		if strings.HasPrefix(title, "bad") {
+			return rejection
+		}
+		return nil
+	}
+
+	specs := entrySpecs("good 0", "bad 1", "good 2", "bad 3", "good 4")
+	results := spire.CreateEntries(context.Background(), client, "token", journalID, specs, spire.BulkOptions{Parallelism: 2})
+
+	if len(results) != len(specs) {
+		t.Fatalf("Expected %d results, got %d", len(specs), len(results))
+	}
+	for i, result := range results {
+		failed := strings.HasPrefix(specs[i].Title, "bad")
+		if failed {
+			var apiErr *spire.APIError
+			if !errors.As(result.Err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected an API error for %s, got %v", specs[i].Title, result.Err)
+			}
+			if result.Entry.Id != "" {
+				t.Errorf("Expected no entry for %s, got %#v", specs[i].Title, result.Entry)
+			}
+		} else {
+			if result.Err != nil {
+				t.Errorf("Unexpected error for %s: %v", specs[i].Title, result.Err)
+			}
+			if result.Entry.Id == "" || result.Entry.Title != specs[i].Title {
+				t.Errorf("Expected an entry for %s, got %#v", specs[i].Title, result.Entry)
+			}
+		}
+	}
+
+	titles := []string{}
+	for _, entry := range client.Entries(journalID) {
+		titles = append(titles, entry.Title)
+	}
+	sort.Strings(titles)
+	expected := []string{"good 0", "good 2", "good 4"}
+	if strings.Join(titles, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected entries %v in the journal, got %v", expected, titles)
+	}
+}
+
+func TestCreateEntriesOrder(t *testing.T) {
+	client, journalID := newBulkJournal(t)
+	// Earlier entries take longer to create, so requests complete in reverse order.
+	client.before = func(title string) error {
+		var index int
+		fmt.Sscanf(title, "entry %d", &index)
+		time.Sleep(time.Duration(8-index) * 2 * time.Millisecond)
+		return nil
+	}
+
+	titles := []string{}
+	for i := 0; i < 8; i++ {
+		titles = append(titles, fmt.Sprintf("entry %d", i))
+	}
+	specs := entrySpecs(titles...)
+	results := spire.CreateEntries(context.Background(), client, "token", journalID, specs, spire.BulkOptions{Parallelism: 4})
+
+	for i, result := range results {
+		if result.Index != i || result.Spec.Title != specs[i].Title || result.Entry.Title != specs[i].Title {
+			t.Errorf("Result %d is out of order: %#v", i, result)
+		}
+	}
+	if client.maxInFlight > 4 {
+		t.Errorf("Expected at most 4 requests at once, got %d", client.maxInFlight)
+	}
+	if client.maxInFlight < 2 {
+		t.Errorf("Expected requests to be made concurrently, got %d at once", client.maxInFlight)
+	}
+}
+
+func TestCreateEntriesFromChannel(t *testing.T) {
+	client, journalID := newBulkJournal(t)
+
+	specs := make(chan spire.EntrySpec)
+	results := spire.CreateEntriesFromChannel(context.Background(), client, "token", journalID, specs, spire.BulkOptions{Parallelism: 3})
+	go func() {
+		for _, spec := range entrySpecs("a", "b", "c", "d", "e") {
+			specs <- spec
+		}
+		close(specs)
+	}()
+
+	seen := map[int]string{}
+	for result := range results {
+		if result.Err != nil {
+			t.Errorf("Unexpected error: %v", result.Err)
+		}
+		if _, exists := seen[result.Index]; exists {
+			t.Errorf("Index %d reported twice", result.Index)
+		}
+		seen[result.Index] = result.Entry.Title
+	}
+	expected := map[int]string{0: "a", 1: "b", 2: "c", 3: "d", 4: "e"}
+	if fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected results %v, got %v", expected, seen)
+	}
+
+	empty := make(chan spire.EntrySpec)
+	close(empty)
+	for result := range spire.CreateEntriesFromChannel(context.Background(), client, "token", journalID, empty, spire.BulkOptions{}) {
+		t.Errorf("Unexpected result for an empty channel: %#v", result)
+	}
+}
+
+func TestCreateEntriesCancel(t *testing.T) {
+	client, journalID := newBulkJournal(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.before = func(title string) error {
+		if title == "entry 1" {
+			cancel()
+		}
+		return nil
+	}
+
+	specs := entrySpecs("entry 0", "entry 1", "entry 2", "entry 3", "entry 4")
+	results := spire.CreateEntries(ctx, client, "token", journalID, specs, spire.BulkOptions{Parallelism: 1})
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("Expected result %d to have index %d, got %d", i, i, result.Index)
+		}
+		// The request for "entry 1" is in flight when the context is cancelled, so it fails too.
+		if i < 1 {
+			if result.Err != nil {
+				t.Errorf("Unexpected error for %s: %v", specs[i].Title, result.Err)
+			}
+		} else if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected a cancellation error for %s, got %v", specs[i].Title, result.Err)
+		}
+	}
+	if client.created != 2 {
+		t.Errorf("Expected no requests after cancellation, got %d requests", client.created)
+	}
+}
+
+func TestCreateEntriesRateLimit(t *testing.T) {
+	client, journalID := newBulkJournal(t)
+	specs := entrySpecs("a", "b", "c", "d", "e")
+
+	start := time.Now()
+	results := spire.CreateEntries(context.Background(), client, "token", journalID, specs, spire.BulkOptions{Parallelism: 5, RateLimit: 100})
+	elapsed := time.Since(start)
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Unexpected error: %v", result.Err)
+		}
+	}
+	// Five requests at 100 per second need at least 50ms, however many of them run in parallel.
+	if elapsed < 45*time.Millisecond {
+		t.Errorf("Expected rate limited requests to take at least 50ms, took %s", elapsed)
+	}
+
+	// Requests which are waiting for the rate limiter stop when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	results = spire.CreateEntries(ctx, client, "token", journalID, specs, spire.BulkOptions{Parallelism: 2, RateLimit: 1})
+	elapsed = time.Since(start)
+
+	if elapsed > time.Second {
+		t.Errorf("Expected cancelled requests to stop waiting, took %s", elapsed)
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.DeadlineExceeded) {
+			t.Errorf("Expected a deadline error for %s, got %v", result.Spec.Title, result.Err)
+		}
+	}
+}
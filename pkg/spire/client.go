@@ -1,14 +1,15 @@
 package spire
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bugout-dev/bugout-go/pkg/config"
 	"github.com/bugout-dev/bugout-go/pkg/utils"
 )
 
@@ -24,7 +25,7 @@ type SpireCaller interface {
 	DeleteJournal(token, journalID string) (Journal, error)
 	AddJournalMember(token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error)
 	RemoveJournalMember(token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error)
-	CreateEntry(token, journalID, title, content string, tags []string, context EntryContext) (Entry, error)
+	CreateEntry(token, journalID, title, content string, tags []string, entryContext EntryContext) (Entry, error)
 	DeleteEntry(token, journalID, entryID string) (Entry, error)
 	GetEntry(token, journalID, entryID string) (Entry, error)
 	ListEntries(token, journalID string, limit, offset int) (EntryResultsPage, error)
@@ -34,6 +35,69 @@ type SpireCaller interface {
 	UpdateEntry(token, journalID, entryID, title, content string) (Entry, error)
 }
 
+// SpireCallerContext mirrors SpireCaller, but every method accepts a context.Context which is
+// attached to the underlying HTTP requests. Use it to propagate cancellation and deadlines.
+type SpireCallerContext interface {
+	PingContext(ctx context.Context) (string, error)
+	CreateJournalContext(ctx context.Context, token, name string) (Journal, error)
+	GetJournalContext(ctx context.Context, token, journalID string) (Journal, error)
+	ListJournalsContext(ctx context.Context, token string) (JournalsList, error)
+	UpdateJournalContext(ctx context.Context, token, journalID, name string) (Journal, error)
+	DeleteJournalContext(ctx context.Context, token, journalID string) (Journal, error)
+	AddJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error)
+	RemoveJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error)
+	CreateEntryContext(ctx context.Context, token, journalID, title, content string, tags []string, entryContext EntryContext) (Entry, error)
+	DeleteEntryContext(ctx context.Context, token, journalID, entryID string) (Entry, error)
+	GetEntryContext(ctx context.Context, token, journalID, entryID string) (Entry, error)
+	ListEntriesContext(ctx context.Context, token, journalID string, limit, offset int) (EntryResultsPage, error)
+	SearchEntriesContext(ctx context.Context, token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (EntryResultsPage, error)
+	TagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error)
+	UntagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error)
+	UpdateEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (Entry, error)
+}
+
+// The interfaces below hold the methods of SpireClient which are not part of SpireCaller. Keeping
+// them out of SpireCaller means that existing implementations of SpireCaller remain valid.
+// SpireClient implements all of them; use a type assertion to check whether another caller does.
+
+// JournalMembersCaller lists the members of a journal and checks the permissions of the caller.
+type JournalMembersCaller interface {
+	ListJournalMembers(token, journalID string) (JournalPermissionsList, error)
+	CheckJournalPermission(token, journalID, permission string) (bool, error)
+}
+
+type JournalMembersCallerContext interface {
+	ListJournalMembersContext(ctx context.Context, token, journalID string) (JournalPermissionsList, error)
+	CheckJournalPermissionContext(ctx context.Context, token, journalID, permission string) (bool, error)
+}
+
+// EntryTagsCaller makes the tags of an entry exactly the given tags.
+type EntryTagsCaller interface {
+	SetEntryTags(token, journalID, entryID string, tags []string) (Entry, error)
+}
+
+type EntryTagsCallerContext interface {
+	SetEntryTagsContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error)
+}
+
+// EntryReplaceCaller sets the title and content of an entry to exactly the given values.
+type EntryReplaceCaller interface {
+	ReplaceEntry(token, journalID, entryID, title, content string) (Entry, error)
+}
+
+type EntryReplaceCallerContext interface {
+	ReplaceEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (Entry, error)
+}
+
+// ConditionalUpdateCaller updates an entry only if it has not changed since it was read.
+type ConditionalUpdateCaller interface {
+	UpdateEntryIfUnchanged(token, journalID, entryID, title, content string, version EntryVersion) (Entry, error)
+}
+
+type ConditionalUpdateCallerContext interface {
+	UpdateEntryIfUnchangedContext(ctx context.Context, token, journalID, entryID, title, content string, version EntryVersion) (Entry, error)
+}
+
 type SpireRoutes struct {
 	Ping     string
 	Journals string
@@ -48,15 +112,49 @@ func RoutesFromURL(spireURL string) SpireRoutes {
 	}
 }
 
+var _ SpireCaller = SpireClient{}
+var _ SpireCallerContext = SpireClient{}
+var _ JournalMembersCaller = SpireClient{}
+var _ JournalMembersCallerContext = SpireClient{}
+var _ EntryTagsCaller = SpireClient{}
+var _ EntryTagsCallerContext = SpireClient{}
+var _ EntryReplaceCaller = SpireClient{}
+var _ EntryReplaceCallerContext = SpireClient{}
+var _ ConditionalUpdateCaller = SpireClient{}
+var _ ConditionalUpdateCallerContext = SpireClient{}
+
 type SpireClient struct {
 	SpireURL   string
 	Routes     SpireRoutes
 	HTTPClient *http.Client
+	Retry      RetryPolicy
+	UserAgent  string
+	// Headers which are added to every request
+	Headers    http.Header
+	Middleware []Middleware
+}
+
+// Sends a request to Spire using the client's HTTPClient, retrying according to its Retry policy.
+// The operation is the name of the calling method, and is made available to middleware.
+func (client SpireClient) do(request *http.Request, operation string) (*http.Response, error) {
+	utils.ApplyDefaultHeaders(request, client.UserAgent, client.Headers)
+	return utils.DoWithMiddleware(request, operation, client.Middleware, func(request *http.Request) (*http.Response, error) {
+		return utils.DoWithRetry(client.HTTPClient, request, client.Retry)
+	})
 }
 
 func (client SpireClient) Ping() (string, error) {
+	return client.PingContext(context.Background())
+}
+
+func (client SpireClient) PingContext(ctx context.Context) (string, error) {
 	pingURL := client.Routes.Ping
-	response, err := client.HTTPClient.Get(pingURL)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", pingURL, nil)
+	if requestErr != nil {
+		return "", requestErr
+	}
+
+	response, err := client.do(request, "Ping")
 	if err != nil {
 		return "", err
 	}
@@ -75,18 +173,44 @@ func (client SpireClient) Ping() (string, error) {
 }
 
 func NewClient(spireURL string, timeout time.Duration) SpireClient {
+	return NewClientWithOptions(spireURL, WithTimeout(timeout))
+}
+
+func NewClientWithOptions(spireURL string, opts ...ClientOption) SpireClient {
 	routes := RoutesFromURL(spireURL)
-	httpClient := http.Client{Timeout: timeout}
-	return SpireClient{SpireURL: spireURL, Routes: routes, HTTPClient: &httpClient}
+	config := utils.NewClientConfig(opts...)
+	return SpireClient{
+		SpireURL:   spireURL,
+		Routes:     routes,
+		HTTPClient: config.BuildHTTPClient(),
+		Retry:      config.Retry,
+		UserAgent:  config.UserAgent,
+		Headers:    config.Headers,
+		Middleware: config.Middleware,
+	}
+}
+
+// ClientFromEnv creates a SpireClient configured by BUGOUT_* environment variables, falling back to
+// the active profile in the bugout configuration file. Options passed to it take precedence over
+// both.
+func ClientFromEnv(opts ...ClientOption) (SpireClient, error) {
+	env, envErr := config.LoadEnv()
+	if envErr != nil {
+		return SpireClient{}, envErr
+	}
+	return ClientFromConfig(env, opts...)
 }
 
-func ClientFromEnv() (SpireClient, error) {
-	spireURL := os.Getenv("BUGOUT_SPIRE_URL")
+// ClientFromConfig is like ClientFromEnv, but takes settings from an already loaded configuration
+// profile.
+func ClientFromConfig(env config.Env, opts ...ClientOption) (SpireClient, error) {
+
+	spireURL := env.Getenv("BUGOUT_SPIRE_URL")
 	if spireURL == "" {
 		spireURL = BugoutSpireURL
 	}
 
-	spireTimeoutSecondsRaw := os.Getenv("BUGOUT_TIMEOUT_SECONDS")
+	spireTimeoutSecondsRaw := env.Getenv("BUGOUT_TIMEOUT_SECONDS")
 	if spireTimeoutSecondsRaw == "" {
 		spireTimeoutSecondsRaw = "3"
 	}
@@ -96,7 +220,13 @@ func ClientFromEnv() (SpireClient, error) {
 	}
 	spireTimeout := time.Duration(spireTimeoutSeconds) * time.Second
 
-	client := NewClient(spireURL, spireTimeout)
+	retryPolicy, retryErr := utils.RetryPolicyFromConfig(env)
+	if retryErr != nil {
+		return SpireClient{}, retryErr
+	}
+
+	envOpts := []ClientOption{WithTimeout(spireTimeout), WithRetry(retryPolicy)}
+	client := NewClientWithOptions(spireURL, append(envOpts, opts...)...)
 
 	return client, nil
 }
@@ -0,0 +1,60 @@
+package spire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EntryVersion identifies the version of an entry which an update is based on. It is passed to
+// UpdateEntryIfUnchanged, which fails if the entry no longer matches it.
+type EntryVersion struct {
+	// UpdatedAt of the entry when it was read. Not checked if zero.
+	UpdatedAt time.Time
+	// Hash of the entry when it was read (see EntryHash). Not checked if empty.
+	Hash string
+}
+
+// EntryVersionOf returns the version of the given entry, checking both its UpdatedAt and its hash.
+func EntryVersionOf(entry Entry) EntryVersion {
+	return EntryVersion{UpdatedAt: entry.UpdatedAt, Hash: EntryHash(entry)}
+}
+
+// EntryHash returns a hash of the title and content of an entry, the parts of an entry which are
+// changed by UpdateEntry.
+func EntryHash(entry Entry) string {
+	encoded, _ := json.Marshal([]string{entry.Title, entry.Content})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether the entry is still at this version.
+func (version EntryVersion) Matches(entry Entry) bool {
+	if !version.UpdatedAt.IsZero() && !version.UpdatedAt.Equal(entry.UpdatedAt) {
+		return false
+	}
+	if version.Hash != "" && version.Hash != EntryHash(entry) {
+		return false
+	}
+	return true
+}
+
+// EntryConflictError is returned by UpdateEntryIfUnchanged when the entry changed after the version
+// the update is based on. It matches ErrConflict with errors.Is.
+type EntryConflictError struct {
+	EntryID string
+	// The version the update was based on
+	Expected EntryVersion
+	// The entry as it is now
+	Current Entry
+}
+
+func (e *EntryConflictError) Error() string {
+	return fmt.Sprintf("Entry %s was changed by someone else (updated at %s)", e.EntryID, e.Current.UpdatedAt.Format(time.RFC3339))
+}
+
+func (e *EntryConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
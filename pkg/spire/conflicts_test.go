@@ -0,0 +1,91 @@
+package spire_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+// Serves a single entry, recording the bodies of the requests which update it.
+func newEntryServer(t *testing.T, entry *spire.Entry) (*httptest.Server, *[]map[string]string) {
+	t.Helper()
+	updates := []map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/journals/journal/entries/"+entry.Id {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPut {
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			updates = append(updates, body)
+			entry.Title, entry.Content = body["title"], body["content"]
+			entry.UpdatedAt = entry.UpdatedAt.Add(time.Second)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entry)
+	}))
+	t.Cleanup(server.Close)
+	return server, &updates
+}
+
+func TestUpdateEntryIfUnchangedToEmptyContent(t *testing.T) {
+	entry := &spire.Entry{Id: "entry", Title: "Title", Content: "Content to remove", UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server, updates := newEntryServer(t, entry)
+	client := spire.NewClient(server.URL, time.Second)
+
+	updated, err := client.UpdateEntryIfUnchangedContext(context.Background(), "token", "journal", "entry", "Title", "", spire.EntryVersionOf(*entry))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Content != "" {
+		t.Errorf("Expected the content to be cleared, got %q", updated.Content)
+	}
+	if len(*updates) != 1 || (*updates)[0]["title"] != "Title" || (*updates)[0]["content"] != "" {
+		t.Errorf("Expected a single update with empty content, got %v", *updates)
+	}
+
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "conflicts")
+	seeded, seedErr := fake.SeedEntry(journal.Id, spire.Entry{Title: "Title", Content: "Content to remove"})
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+	updated, err = fake.UpdateEntryIfUnchanged("token", journal.Id, seeded.Id, "Title", "", spire.EntryVersionOf(seeded))
+	if err != nil {
+		t.Fatalf("Unexpected error from the fake: %v", err)
+	}
+	if updated.Content != "" || fake.Entries(journal.Id)[0].Content != "" {
+		t.Errorf("Expected the fake to clear the content, got %q", updated.Content)
+	}
+}
+
+func TestUpdateEntryIfUnchangedConflict(t *testing.T) {
+	entry := &spire.Entry{Id: "entry", Title: "Title", Content: "Content", UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server, updates := newEntryServer(t, entry)
+	client := spire.NewClient(server.URL, time.Second)
+
+	version := spire.EntryVersionOf(*entry)
+	entry.Content = "Changed by someone else"
+	entry.UpdatedAt = entry.UpdatedAt.Add(time.Minute)
+
+	_, err := client.UpdateEntryIfUnchangedContext(context.Background(), "token", "journal", "entry", "Title", "", version)
+	var conflictErr *spire.EntryConflictError
+	if !errors.As(err, &conflictErr) || conflictErr.Current.Content != "Changed by someone else" {
+		t.Fatalf("Expected an EntryConflictError with the current entry, got %v", err)
+	}
+	if len(*updates) != 0 {
+		t.Errorf("Expected no updates, got %v", *updates)
+	}
+}
@@ -1,16 +1,45 @@
 package spire
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
 type journalCreateRequest struct {
 	Name string `json:"name"`
 }
 
 type Journal struct {
-	Id           string   `json:"id"`
-	BugoutUserID string   `json:"bugout_user_id"`
-	HolderIDs    []string `json:"holder_ids"`
-	Name         string   `json:"name"`
-	CreatedAt    string   `json:"created_at"`
-	UpdatedAt    string   `json:"updated_at"`
+	Id           string    `json:"id"`
+	BugoutUserID string    `json:"bugout_user_id"`
+	HolderIDs    []string  `json:"holder_ids"`
+	Name         string    `json:"name"`
+	SearchIndex  string    `json:"search_index,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (journal *Journal) UnmarshalJSON(data []byte) error {
+	type Alias Journal
+	aux := &struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		*Alias
+	}{
+		Alias: (*Alias)(journal),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var parseErr error
+	if journal.CreatedAt, parseErr = utils.ParseTimestamp(aux.CreatedAt); parseErr != nil {
+		return parseErr
+	}
+	journal.UpdatedAt, parseErr = utils.ParseTimestamp(aux.UpdatedAt)
+	return parseErr
 }
 
 type JournalsList struct {
@@ -34,6 +63,13 @@ type JournalPermissionsList struct {
 	Scopes []JournalPermission `json:"scopes"`
 }
 
+// JournalUserPermissions lists the permissions which the calling user holds on a journal, directly
+// or through their groups.
+type JournalUserPermissions struct {
+	JournalID   string   `json:"journal_id"`
+	Permissions []string `json:"permissions"`
+}
+
 type EntryContext struct {
 	ContextType string
 	ContextID   string
@@ -63,17 +99,41 @@ type entryRemoveTagRequest struct {
 }
 
 type Entry struct {
-	Id          string   `json:"id,omitempty"`
-	Url         string   `json:"entry_url,omitempty"`
-	JournalURL  string   `json:"journal_url,omitempty"`
-	Title       string   `json:"title"`
-	Content     string   `json:"content"`
-	Tags        []string `json:"tags"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-	ContextUrl  string   `json:"context_url,omitempty"`
-	ContextType string   `json:"context_type,omitempty"`
-	Score       float64  `json:"score,omitempty"`
+	Id          string    `json:"id,omitempty"`
+	Url         string    `json:"entry_url,omitempty"`
+	JournalURL  string    `json:"journal_url,omitempty"`
+	ContentURL  string    `json:"content_url,omitempty"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ContextUrl  string    `json:"context_url,omitempty"`
+	ContextType string    `json:"context_type,omitempty"`
+	ContextID   string    `json:"context_id,omitempty"`
+	LockedBy    string    `json:"locked_by,omitempty"`
+	Score       float64   `json:"score,omitempty"`
+}
+
+func (entry *Entry) UnmarshalJSON(data []byte) error {
+	type Alias Entry
+	aux := &struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		*Alias
+	}{
+		Alias: (*Alias)(entry),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var parseErr error
+	if entry.CreatedAt, parseErr = utils.ParseTimestamp(aux.CreatedAt); parseErr != nil {
+		return parseErr
+	}
+	entry.UpdatedAt, parseErr = utils.ParseTimestamp(aux.UpdatedAt)
+	return parseErr
 }
 
 type EntryResultsPage struct {
@@ -0,0 +1,232 @@
+package spire_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+// Decodes payload into value, encodes value again and checks that the result holds the same JSON
+// as expected (or as payload itself, if expected is empty).
+func checkRoundTrip(t *testing.T, payload, expected string, value interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(payload), value); err != nil {
+		t.Fatalf("Could not decode payload: %v", err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Could not encode %#v: %v", value, err)
+	}
+
+	if expected == "" {
+		expected = payload
+	}
+	var expectedJSON, actualJSON interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedJSON); err != nil {
+		t.Fatalf("Invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &actualJSON); err != nil {
+		t.Fatalf("Invalid encoded JSON: %v", err)
+	}
+	if !reflect.DeepEqual(actualJSON, expectedJSON) {
+		t.Errorf("Round trip changed the payload\nexpected: %s\nencoded:  %s", expected, encoded)
+	}
+}
+
+func TestDataRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload string
+		// JSON which the payload is expected to be encoded as, if it differs from the payload
+		expected string
+		value    func() interface{}
+	}{
+		{
+			name: "journal",
+			payload: `{
+				"id": "7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11",
+				"bugout_user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"holder_ids": ["2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21", "c0b1d9b4-5a1f-4f7e-8e1d-0a3e2b7c9f10"],
+				"name": "Deployments",
+				"search_index": "journal_7ec6ac5e",
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-11T09:00:00.5Z"
+			}`,
+			value: func() interface{} { return &spire.Journal{} },
+		},
+		{
+			name: "journal with space-separated timestamps without time zone",
+			payload: `{
+				"id": "7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11",
+				"bugout_user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"holder_ids": [],
+				"name": "Deployments",
+				"created_at": "2021-02-10 14:44:38.104949",
+				"updated_at": "2021-02-11 09:00:00+02:00"
+			}`,
+			expected: `{
+				"id": "7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11",
+				"bugout_user_id": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21",
+				"holder_ids": [],
+				"name": "Deployments",
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-11T07:00:00Z"
+			}`,
+			value: func() interface{} { return &spire.Journal{} },
+		},
+		{
+			name:    "journals list",
+			payload: `{"journals": [{"id": "j1", "bugout_user_id": "u1", "holder_ids": ["u1"], "name": "One", "created_at": "2021-02-10T14:44:38Z", "updated_at": "2021-02-10T14:44:38Z"}]}`,
+			value:   func() interface{} { return &spire.JournalsList{} },
+		},
+		{
+			name: "entry with context",
+			payload: `{
+				"id": "e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b",
+				"journal_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11",
+				"content_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11/entries/e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b/content",
+				"title": "Deploy failed",
+				"content": "The migration timed out.\n",
+				"tags": ["deploy", "os:linux"],
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-10T15:00:01.000001Z",
+				"context_url": "https://github.com/bugout-dev/bugout-go/pull/12",
+				"context_type": "github",
+				"context_id": "bugout-dev/bugout-go#12",
+				"locked_by": "2f4f3c2a-9d2e-4a57-9a55-8f0c8a1e4d21"
+			}`,
+			value: func() interface{} { return &spire.Entry{} },
+		},
+		{
+			name: "entry with space-separated timestamps without time zone",
+			payload: `{
+				"id": "e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b",
+				"title": "Deploy failed",
+				"content": "",
+				"tags": [],
+				"created_at": "2021-02-10 14:44:38.104949",
+				"updated_at": "2021-02-10 15:00:01"
+			}`,
+			expected: `{
+				"id": "e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b",
+				"title": "Deploy failed",
+				"content": "",
+				"tags": [],
+				"created_at": "2021-02-10T14:44:38.104949Z",
+				"updated_at": "2021-02-10T15:00:01Z"
+			}`,
+			value: func() interface{} { return &spire.Entry{} },
+		},
+		{
+			name: "search results",
+			payload: `{
+				"total_results": 12,
+				"offset": 10,
+				"next_offset": 11,
+				"max_score": 1.5,
+				"results": [{
+					"entry_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11/entries/e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b",
+					"content_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11/entries/e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b/content",
+					"title": "Deploy failed",
+					"content": "The migration timed out.",
+					"tags": ["deploy"],
+					"created_at": "2021-02-10 14:44:38.104949+00:00",
+					"updated_at": "2021-02-10 15:00:01.5+00:00",
+					"context_url": "https://github.com/bugout-dev/bugout-go/pull/12",
+					"context_type": "github",
+					"context_id": "bugout-dev/bugout-go#12",
+					"score": 1.5
+				}]
+			}`,
+			expected: `{
+				"total_results": 12,
+				"offset": 10,
+				"next_offset": 11,
+				"max_score": 1.5,
+				"results": [{
+					"entry_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11/entries/e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b",
+					"content_url": "https://spire.bugout.dev/journals/7ec6ac5e-2a4e-4b2c-8f3a-6d1d6a3f2c11/entries/e0b0a8a2-0f51-4d7e-9a8b-3c5d2e1f4a6b/content",
+					"title": "Deploy failed",
+					"content": "The migration timed out.",
+					"tags": ["deploy"],
+					"created_at": "2021-02-10T14:44:38.104949Z",
+					"updated_at": "2021-02-10T15:00:01.5Z",
+					"context_url": "https://github.com/bugout-dev/bugout-go/pull/12",
+					"context_type": "github",
+					"context_id": "bugout-dev/bugout-go#12",
+					"score": 1.5
+				}]
+			}`,
+			value: func() interface{} { return &spire.EntryResultsPage{} },
+		},
+		{
+			name: "journal scopes",
+			payload: `{"scopes": [
+				{"journal_id": "j1", "holder_id": "u1", "holder_type": "user", "permission": "journals.read"},
+				{"journal_id": "j1", "holder_id": "g1", "holder_type": "group", "permission": "journals.entries.create"}
+			]}`,
+			value: func() interface{} { return &spire.JournalPermissionsList{} },
+		},
+		{
+			name:    "journal permissions of a user",
+			payload: `{"journal_id": "j1", "permissions": ["journals.read", "journals.update"]}`,
+			value:   func() interface{} { return &spire.JournalUserPermissions{} },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkRoundTrip(t, c.payload, c.expected, c.value())
+		})
+	}
+}
+
+func TestEntryTimestamps(t *testing.T) {
+	expected := time.Date(2021, 2, 10, 14, 44, 38, 104949000, time.UTC)
+	timestamps := []string{
+		"2021-02-10T14:44:38.104949Z",
+		"2021-02-10T14:44:38.104949+00:00",
+		"2021-02-10T16:44:38.104949+02:00",
+		"2021-02-10T14:44:38.104949",
+		"2021-02-10T14:44:38.104949+0000",
+		"2021-02-10 14:44:38.104949",
+		"2021-02-10 14:44:38.104949+00:00",
+		"2021-02-10 09:44:38.104949-0500",
+	}
+
+	for _, timestamp := range timestamps {
+		t.Run(timestamp, func(t *testing.T) {
+			var entry spire.Entry
+			payload := `{"id": "e1", "created_at": "` + timestamp + `", "updated_at": "` + timestamp + `"}`
+			if err := json.Unmarshal([]byte(payload), &entry); err != nil {
+				t.Fatalf("Could not decode entry: %v", err)
+			}
+			if !entry.CreatedAt.Equal(expected) || !entry.UpdatedAt.Equal(expected) {
+				t.Errorf("Expected %s, got %s and %s", expected, entry.CreatedAt, entry.UpdatedAt)
+			}
+			if entry.CreatedAt.Location() != time.UTC {
+				t.Errorf("Expected a UTC time, got %s", entry.CreatedAt.Location())
+			}
+		})
+	}
+
+	var entry spire.Entry
+	if err := json.Unmarshal([]byte(`{"id": "e1"}`), &entry); err != nil {
+		t.Fatalf("Could not decode entry without timestamps: %v", err)
+	}
+	if !entry.CreatedAt.IsZero() || !entry.UpdatedAt.IsZero() {
+		t.Errorf("Expected missing timestamps to be zero, got %s and %s", entry.CreatedAt, entry.UpdatedAt)
+	}
+
+	for _, payload := range []string{`{"created_at": "yesterday"}`, `{"updated_at": "2021-02-30T00:00:00Z"}`} {
+		if err := json.Unmarshal([]byte(payload), &entry); err == nil {
+			t.Errorf("Expected an error decoding %s", payload)
+		}
+		if err := json.Unmarshal([]byte(payload), &spire.Journal{}); err == nil {
+			t.Errorf("Expected an error decoding journal %s", payload)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package spire
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,7 +11,11 @@ import (
 	"github.com/bugout-dev/bugout-go/pkg/utils"
 )
 
-func (client SpireClient) CreateEntry(token, journalID, title, content string, tags []string, context EntryContext) (Entry, error) {
+func (client SpireClient) CreateEntry(token, journalID, title, content string, tags []string, entryContext EntryContext) (Entry, error) {
+	return client.CreateEntryContext(context.Background(), token, journalID, title, content, tags, entryContext)
+}
+
+func (client SpireClient) CreateEntryContext(ctx context.Context, token, journalID, title, content string, tags []string, entryContext EntryContext) (Entry, error) {
 	entriesRoute := fmt.Sprintf("%s/%s/entries", client.Routes.Journals, journalID)
 	requestBody := entryCreateRequest{
 		Title:   title,
@@ -18,14 +23,14 @@ func (client SpireClient) CreateEntry(token, journalID, title, content string, t
 		Tags:    tags,
 	}
 
-	if context.ContextType != "" {
-		requestBody.ContextType = context.ContextType
+	if entryContext.ContextType != "" {
+		requestBody.ContextType = entryContext.ContextType
 	}
-	if context.ContextID != "" {
-		requestBody.ContextID = context.ContextID
+	if entryContext.ContextID != "" {
+		requestBody.ContextID = entryContext.ContextID
 	}
-	if context.ContextURL != "" {
-		requestBody.ContextURL = context.ContextURL
+	if entryContext.ContextURL != "" {
+		requestBody.ContextURL = entryContext.ContextURL
 	}
 
 	requestBuffer := new(bytes.Buffer)
@@ -33,7 +38,7 @@ func (client SpireClient) CreateEntry(token, journalID, title, content string, t
 	if encodeErr != nil {
 		return Entry{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("POST", entriesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", entriesRoute, requestBuffer)
 	if requestErr != nil {
 		return Entry{}, requestErr
 	}
@@ -41,7 +46,7 @@ func (client SpireClient) CreateEntry(token, journalID, title, content string, t
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "CreateEntry")
 	if responseErr != nil {
 		return Entry{}, responseErr
 	}
@@ -58,15 +63,19 @@ func (client SpireClient) CreateEntry(token, journalID, title, content string, t
 }
 
 func (client SpireClient) DeleteEntry(token, journalID, entryID string) (Entry, error) {
+	return client.DeleteEntryContext(context.Background(), token, journalID, entryID)
+}
+
+func (client SpireClient) DeleteEntryContext(ctx context.Context, token, journalID, entryID string) (Entry, error) {
 	entryRoute := fmt.Sprintf("%s/%s/entries/%s", client.Routes.Journals, journalID, entryID)
-	request, requestErr := http.NewRequest("DELETE", entryRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", entryRoute, nil)
 	if requestErr != nil {
 		return Entry{}, requestErr
 	}
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "DeleteEntry")
 	if responseErr != nil {
 		return Entry{}, responseErr
 	}
@@ -83,15 +92,19 @@ func (client SpireClient) DeleteEntry(token, journalID, entryID string) (Entry,
 }
 
 func (client SpireClient) GetEntry(token, journalID, entryID string) (Entry, error) {
+	return client.GetEntryContext(context.Background(), token, journalID, entryID)
+}
+
+func (client SpireClient) GetEntryContext(ctx context.Context, token, journalID, entryID string) (Entry, error) {
 	entryRoute := fmt.Sprintf("%s/%s/entries/%s", client.Routes.Journals, journalID, entryID)
-	request, requestErr := http.NewRequest("GET", entryRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", entryRoute, nil)
 	if requestErr != nil {
 		return Entry{}, requestErr
 	}
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "GetEntry")
 	if responseErr != nil {
 		return Entry{}, responseErr
 	}
@@ -108,8 +121,12 @@ func (client SpireClient) GetEntry(token, journalID, entryID string) (Entry, err
 }
 
 func (client SpireClient) ListEntries(token, journalID string, limit, offset int) (EntryResultsPage, error) {
+	return client.ListEntriesContext(context.Background(), token, journalID, limit, offset)
+}
+
+func (client SpireClient) ListEntriesContext(ctx context.Context, token, journalID string, limit, offset int) (EntryResultsPage, error) {
 	entriesRoute := fmt.Sprintf("%s/%s/search", client.Routes.Journals, journalID)
-	request, requestErr := http.NewRequest("GET", entriesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", entriesRoute, nil)
 	if requestErr != nil {
 		return EntryResultsPage{}, requestErr
 	}
@@ -122,7 +139,7 @@ func (client SpireClient) ListEntries(token, journalID string, limit, offset int
 	query.Add("offset", strconv.Itoa(offset))
 	request.URL.RawQuery = query.Encode()
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "ListEntries")
 	if responseErr != nil {
 		return EntryResultsPage{}, responseErr
 	}
@@ -139,8 +156,12 @@ func (client SpireClient) ListEntries(token, journalID string, limit, offset int
 }
 
 func (client SpireClient) SearchEntries(token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (EntryResultsPage, error) {
+	return client.SearchEntriesContext(context.Background(), token, journalID, searchQuery, limit, offset, queryParameters)
+}
+
+func (client SpireClient) SearchEntriesContext(ctx context.Context, token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (EntryResultsPage, error) {
 	entriesRoute := fmt.Sprintf("%s/%s/search", client.Routes.Journals, journalID)
-	request, requestErr := http.NewRequest("GET", entriesRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", entriesRoute, nil)
 	if requestErr != nil {
 		return EntryResultsPage{}, requestErr
 	}
@@ -158,7 +179,7 @@ func (client SpireClient) SearchEntries(token, journalID, searchQuery string, li
 	query.Add("offset", strconv.Itoa(offset))
 	request.URL.RawQuery = query.Encode()
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "SearchEntries")
 	if responseErr != nil {
 		return EntryResultsPage{}, responseErr
 	}
@@ -175,12 +196,16 @@ func (client SpireClient) SearchEntries(token, journalID, searchQuery string, li
 }
 
 func (client SpireClient) TagEntry(token, journalID, entryID string, tags []string) (Entry, error) {
+	return client.TagEntryContext(context.Background(), token, journalID, entryID, tags)
+}
+
+func (client SpireClient) TagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error) {
 	// The tagging endpoint returns an error if we try to add tags to an entry that it already has.
 	// Therefore, we must first restrict the slice of new tags to only those tags which are not
 	// already present on the entry.
-	currentEntry, currentEntryErr := client.GetEntry(token, journalID, entryID)
+	currentEntry, currentEntryErr := client.GetEntryContext(ctx, token, journalID, entryID)
 	if currentEntryErr != nil {
-		return Entry{}, fmt.Errorf("Error obtaining entry (journalID: %s, entryID: %s):\n%s", journalID, entryID, currentEntryErr.Error())
+		return Entry{}, fmt.Errorf("Error obtaining entry (journalID: %s, entryID: %s):\n%w", journalID, entryID, currentEntryErr)
 	}
 
 	existingTags := make(map[string]bool)
@@ -188,81 +213,191 @@ func (client SpireClient) TagEntry(token, journalID, entryID string, tags []stri
 		existingTags[tag] = true
 	}
 
-	tagsToAdd := make([]string, len(tags))
-	numTags := 0
+	tagsToAdd := []string{}
 	for _, tag := range tags {
 		if _, exists := existingTags[tag]; !exists {
-			tagsToAdd[numTags] = tag
-			numTags++
+			tagsToAdd = append(tagsToAdd, tag)
+			existingTags[tag] = true
 		}
 	}
+	if len(tagsToAdd) == 0 {
+		return currentEntry, nil
+	}
+	addErr := client.addTags(ctx, token, journalID, entryID, tagsToAdd)
+	if addErr != nil {
+		return Entry{}, addErr
+	}
 
+	// Now return the freshest state of the entry
+	return client.GetEntryContext(ctx, token, journalID, entryID)
+}
+
+func (client SpireClient) UntagEntry(token, journalID, entryID string, tags []string) (Entry, error) {
+	return client.UntagEntryContext(context.Background(), token, journalID, entryID, tags)
+}
+
+func (client SpireClient) UntagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error) {
+	tagErrors := client.removeTags(ctx, token, journalID, entryID, tags)
+	if len(tagErrors) > 0 {
+		return Entry{}, tagErrors
+	}
+
+	// Now return the freshest state of the entry
+	return client.GetEntryContext(ctx, token, journalID, entryID)
+}
+
+func (client SpireClient) SetEntryTags(token, journalID, entryID string, tags []string) (Entry, error) {
+	return client.SetEntryTagsContext(context.Background(), token, journalID, entryID, tags)
+}
+
+// SetEntryTagsContext makes the tags of an entry exactly the given tags, adding and removing only
+// the tags which differ from the entry's current tags.
+func (client SpireClient) SetEntryTagsContext(ctx context.Context, token, journalID, entryID string, tags []string) (Entry, error) {
+	currentEntry, currentEntryErr := client.GetEntryContext(ctx, token, journalID, entryID)
+	if currentEntryErr != nil {
+		return Entry{}, fmt.Errorf("Error obtaining entry (journalID: %s, entryID: %s):\n%w", journalID, entryID, currentEntryErr)
+	}
+
+	tagsToAdd, tagsToRemove := DiffTags(currentEntry.Tags, tags)
+	if len(tagsToAdd) == 0 && len(tagsToRemove) == 0 {
+		return currentEntry, nil
+	}
+
+	tagErrors := client.removeTags(ctx, token, journalID, entryID, tagsToRemove)
+	if len(tagsToAdd) > 0 {
+		addErr := client.addTags(ctx, token, journalID, entryID, tagsToAdd)
+		if addErr != nil {
+			for _, tag := range tagsToAdd {
+				tagErrors = append(tagErrors, TagError{Tag: tag, Err: addErr})
+			}
+		}
+	}
+	if len(tagErrors) > 0 {
+		return Entry{}, tagErrors
+	}
+
+	// Now return the freshest state of the entry
+	return client.GetEntryContext(ctx, token, journalID, entryID)
+}
+
+// Adds tags to an entry with a single request. Spire rejects the request if the entry already has
+// any of the tags.
+func (client SpireClient) addTags(ctx context.Context, token, journalID, entryID string, tags []string) error {
 	entryTagsRoute := fmt.Sprintf("%s/%s/entries/%s/tags", client.Routes.Journals, journalID, entryID)
 	requestBody := entryAddTagsRequest{
-		Tags: tagsToAdd[:numTags],
+		Tags: tags,
 	}
 
 	requestBuffer := new(bytes.Buffer)
 	encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
 	if encodeErr != nil {
-		return Entry{}, encodeErr
+		return encodeErr
 	}
-	request, requestErr := http.NewRequest("POST", entryTagsRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", entryTagsRoute, requestBuffer)
 	if requestErr != nil {
-		return Entry{}, requestErr
+		return requestErr
 	}
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "TagEntry")
 	if responseErr != nil {
-		return Entry{}, responseErr
+		return responseErr
 	}
 	defer response.Body.Close()
 
-	// Now return the freshest state of the entry
-	return client.GetEntry(token, journalID, entryID)
+	return utils.HTTPStatusCheck(response)
 }
 
-func (client SpireClient) UntagEntry(token, journalID, entryID string, tags []string) (Entry, error) {
+// Removes tags from an entry, one request per tag. A failure to remove one tag does not stop the
+// others from being removed. Returns the errors for the tags which could not be removed.
+func (client SpireClient) removeTags(ctx context.Context, token, journalID, entryID string, tags []string) TagErrors {
 	entryTagsRoute := fmt.Sprintf("%s/%s/entries/%s/tags", client.Routes.Journals, journalID, entryID)
+	var tagErrors TagErrors
 	for _, tag := range tags {
-		requestBody := entryRemoveTagRequest{Tag: tag}
-
-		requestBuffer := new(bytes.Buffer)
-		encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
-		if encodeErr != nil {
-			return Entry{}, encodeErr
-		}
-		request, requestErr := http.NewRequest("DELETE", entryTagsRoute, requestBuffer)
-		if requestErr != nil {
-			return Entry{}, requestErr
-		}
-		request.Header.Add("Content-Type", "application/json")
-		request.Header.Add("Accept", "application/json")
-		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-
-		response, responseErr := client.HTTPClient.Do(request)
-		if responseErr != nil {
-			return Entry{}, responseErr
+		removeErr := func() error {
+			requestBody := entryRemoveTagRequest{Tag: tag}
+
+			requestBuffer := new(bytes.Buffer)
+			encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
+			if encodeErr != nil {
+				return encodeErr
+			}
+			request, requestErr := http.NewRequestWithContext(ctx, "DELETE", entryTagsRoute, requestBuffer)
+			if requestErr != nil {
+				return requestErr
+			}
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Add("Accept", "application/json")
+			request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			response, responseErr := client.do(request, "UntagEntry")
+			if responseErr != nil {
+				return responseErr
+			}
+			defer response.Body.Close()
+
+			return utils.HTTPStatusCheck(response)
+		}()
+		if removeErr != nil {
+			tagErrors = append(tagErrors, TagError{Tag: tag, Err: removeErr})
 		}
-		defer response.Body.Close()
 	}
-
-	// Now return the freshest state of the entry
-	return client.GetEntry(token, journalID, entryID)
+	return tagErrors
 }
 
 func (client SpireClient) UpdateEntry(token, journalID, entryID, title, content string) (Entry, error) {
+	return client.UpdateEntryContext(context.Background(), token, journalID, entryID, title, content)
+}
+
+func (client SpireClient) UpdateEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (Entry, error) {
 	// If title or content are empty, does not overwrite the current title and content for the
 	// entry with the given entryID. This is different from the behavior of out endpoint.
-	currentEntry, currentEntryErr := client.GetEntry(token, journalID, entryID)
+	currentEntry, currentEntryErr := client.GetEntryContext(ctx, token, journalID, entryID)
 	if currentEntryErr != nil {
 		return Entry{}, currentEntryErr
 	}
+	return client.putEntry(ctx, token, journalID, entryID, updatedEntry(currentEntry, title, content), "UpdateEntry")
+}
 
-	entryRoute := fmt.Sprintf("%s/%s/entries/%s", client.Routes.Journals, journalID, entryID)
+func (client SpireClient) ReplaceEntry(token, journalID, entryID, title, content string) (Entry, error) {
+	return client.ReplaceEntryContext(context.Background(), token, journalID, entryID, title, content)
+}
+
+// ReplaceEntryContext sets the title and content of an entry to exactly the given values. Unlike
+// UpdateEntryContext, empty values are sent as they are, so this can be used to clear the content
+// of an entry.
+func (client SpireClient) ReplaceEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (Entry, error) {
+	return client.putEntry(ctx, token, journalID, entryID, entryUpdateRequest{Title: title, Content: content}, "ReplaceEntry")
+}
+
+func (client SpireClient) UpdateEntryIfUnchanged(token, journalID, entryID, title, content string, version EntryVersion) (Entry, error) {
+	return client.UpdateEntryIfUnchangedContext(context.Background(), token, journalID, entryID, title, content, version)
+}
+
+// UpdateEntryIfUnchangedContext sets the title and content of an entry to exactly the given values,
+// like ReplaceEntryContext, but only if the entry is still at the given version. Otherwise, it returns an *EntryConflictError holding the current
+// entry and makes no changes.
+//
+// Spire does not support conditional updates, so the version is checked against the entry right
+// before it is updated. This catches changes made while the update was being prepared (for example,
+// while the entry was open in an editor), but not changes made in the moment between the check and
+// the update.
+func (client SpireClient) UpdateEntryIfUnchangedContext(ctx context.Context, token, journalID, entryID, title, content string, version EntryVersion) (Entry, error) {
+	currentEntry, currentEntryErr := client.GetEntryContext(ctx, token, journalID, entryID)
+	if currentEntryErr != nil {
+		return Entry{}, currentEntryErr
+	}
+	if !version.Matches(currentEntry) {
+		return Entry{}, &EntryConflictError{EntryID: entryID, Expected: version, Current: currentEntry}
+	}
+	return client.putEntry(ctx, token, journalID, entryID, entryUpdateRequest{Title: title, Content: content}, "UpdateEntry")
+}
+
+// Returns the title and content of the current entry, replaced by those in the update which are not
+// empty.
+func updatedEntry(currentEntry Entry, title, content string) entryUpdateRequest {
 	requestBody := entryUpdateRequest{
 		Title:   currentEntry.Title,
 		Content: currentEntry.Content,
@@ -273,13 +408,19 @@ func (client SpireClient) UpdateEntry(token, journalID, entryID, title, content
 	if content != "" {
 		requestBody.Content = content
 	}
+	return requestBody
+}
+
+// Replaces the title and content of an entry.
+func (client SpireClient) putEntry(ctx context.Context, token, journalID, entryID string, requestBody entryUpdateRequest, operation string) (Entry, error) {
+	entryRoute := fmt.Sprintf("%s/%s/entries/%s", client.Routes.Journals, journalID, entryID)
 
 	requestBuffer := new(bytes.Buffer)
 	encodeErr := json.NewEncoder(requestBuffer).Encode(requestBody)
 	if encodeErr != nil {
 		return Entry{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("PUT", entryRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "PUT", entryRoute, requestBuffer)
 	if requestErr != nil {
 		return Entry{}, requestErr
 	}
@@ -287,7 +428,7 @@ func (client SpireClient) UpdateEntry(token, journalID, entryID, title, content
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, operation)
 	if responseErr != nil {
 		return Entry{}, responseErr
 	}
@@ -299,5 +440,5 @@ func (client SpireClient) UpdateEntry(token, journalID, entryID, title, content
 	}
 
 	// One more GetEntry API call to get the freshest version of the entry on the server.
-	return client.GetEntry(token, journalID, entryID)
+	return client.GetEntryContext(ctx, token, journalID, entryID)
 }
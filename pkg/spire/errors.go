@@ -0,0 +1,18 @@
+package spire
+
+import (
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// APIError is returned by SpireClient methods when Spire responds with a non-2xx status code. Use
+// errors.As to inspect it, or errors.Is with the sentinel errors below to check for common cases.
+type APIError = utils.APIError
+
+var (
+	ErrNotFound     = utils.ErrNotFound
+	ErrUnauthorized = utils.ErrUnauthorized
+	ErrForbidden    = utils.ErrForbidden
+	ErrConflict     = utils.ErrConflict
+	ErrRateLimited  = utils.ErrRateLimited
+	ErrUnsupported  = utils.ErrUnsupported
+)
@@ -0,0 +1,169 @@
+package spire
+
+import (
+	"context"
+	"strings"
+)
+
+// Default number of entries requested per page by an EntryIterator
+const DefaultIteratorPageSize int = 100
+
+// EntryIterator walks through all the entries matching a search query, fetching pages from Spire as
+// needed:
+//
+//	iterator := spire.NewEntryIterator(client, token, journalID, "tag:deploy", nil)
+//	for iterator.Next() {
+//		entry := iterator.Entry()
+//		...
+//	}
+//	if err := iterator.Err(); err != nil {
+//		...
+//	}
+//
+// Set PageSize and MaxResults before the first call to Next. An empty query iterates over every
+// entry in the journal, as ListEntries does. Unlike the results of SearchEntries, the entries have
+// their IDs set.
+type EntryIterator struct {
+	// Number of entries to request per page (defaults to DefaultIteratorPageSize)
+	PageSize int
+	// If positive, iteration stops after this many entries
+	MaxResults int
+
+	ctx             context.Context
+	client          SpireCallerContext
+	token           string
+	journalID       string
+	searchQuery     string
+	queryParameters map[string]string
+
+	page         []Entry
+	index        int
+	offset       int
+	exhausted    bool
+	yielded      int
+	totalResults int
+	err          error
+}
+
+func NewEntryIterator(client SpireCallerContext, token, journalID, searchQuery string, queryParameters map[string]string) *EntryIterator {
+	return NewEntryIteratorContext(context.Background(), client, token, journalID, searchQuery, queryParameters)
+}
+
+// NewEntryIteratorContext creates an EntryIterator which makes its requests with the given context.
+// Once the context is done, Next returns false and Err returns the context's error.
+func NewEntryIteratorContext(ctx context.Context, client SpireCallerContext, token, journalID, searchQuery string, queryParameters map[string]string) *EntryIterator {
+	return &EntryIterator{
+		PageSize:        DefaultIteratorPageSize,
+		ctx:             ctx,
+		client:          client,
+		token:           token,
+		journalID:       journalID,
+		searchQuery:     searchQuery,
+		queryParameters: queryParameters,
+		index:           -1,
+	}
+}
+
+// Next advances the iterator to the next entry, fetching a new page if necessary. It returns false
+// when there are no more entries or when an error occurs.
+func (iterator *EntryIterator) Next() bool {
+	if iterator.err != nil {
+		return false
+	}
+	if iterator.MaxResults > 0 && iterator.yielded >= iterator.MaxResults {
+		return false
+	}
+	if ctxErr := iterator.ctx.Err(); ctxErr != nil {
+		iterator.err = ctxErr
+		return false
+	}
+
+	if iterator.index+1 >= len(iterator.page) {
+		if iterator.exhausted || !iterator.fetchPage() {
+			return false
+		}
+	}
+
+	iterator.index++
+	iterator.yielded++
+	return true
+}
+
+// Fetches the page starting at the current offset. Returns false if there are no more entries.
+func (iterator *EntryIterator) fetchPage() bool {
+	limit := iterator.PageSize
+	if limit <= 0 {
+		limit = DefaultIteratorPageSize
+	}
+	if iterator.MaxResults > 0 && iterator.MaxResults-iterator.yielded < limit {
+		limit = iterator.MaxResults - iterator.yielded
+	}
+
+	page, err := iterator.client.SearchEntriesContext(iterator.ctx, iterator.token, iterator.journalID, iterator.searchQuery, limit, iterator.offset, iterator.queryParameters)
+	if err != nil {
+		iterator.err = err
+		return false
+	}
+
+	iterator.totalResults = page.TotalResults
+	iterator.page = page.Results
+	iterator.index = -1
+	// Search results do not include the IDs of entries, so they are taken from the entry URLs.
+	for i, entry := range iterator.page {
+		if entry.Id == "" && entry.Url != "" {
+			entryURL := strings.TrimRight(entry.Url, "/")
+			iterator.page[i].Id = entryURL[strings.LastIndex(entryURL, "/")+1:]
+		}
+	}
+
+	// Spire only sets next_offset when there are more results, but we do not rely on it alone in
+	// case the journal changes while we are iterating over it.
+	nextOffset := iterator.offset + len(page.Results)
+	if page.NextOffset > iterator.offset {
+		nextOffset = page.NextOffset
+	}
+	iterator.offset = nextOffset
+	if len(page.Results) == 0 || nextOffset >= page.TotalResults {
+		iterator.exhausted = true
+	}
+
+	return len(page.Results) > 0
+}
+
+// Entry returns the current entry. It is only valid after a call to Next which returned true.
+func (iterator *EntryIterator) Entry() Entry {
+	if iterator.index < 0 || iterator.index >= len(iterator.page) {
+		return Entry{}
+	}
+	return iterator.page[iterator.index]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (iterator *EntryIterator) Err() error {
+	return iterator.err
+}
+
+// TotalResults returns the total number of entries matching the query, as reported by the last
+// page fetched from Spire. It returns 0 before the first page is fetched.
+func (iterator *EntryIterator) TotalResults() int {
+	return iterator.totalResults
+}
+
+// Entries runs the iterator in a separate goroutine and sends every entry on the returned channel,
+// which is closed when the iteration ends. Check Err once the channel is closed. To stop early,
+// cancel the iterator's context; otherwise the goroutine blocks until the channel is drained.
+func (iterator *EntryIterator) Entries() <-chan Entry {
+	entries := make(chan Entry)
+	go func() {
+		defer close(entries)
+		for iterator.Next() {
+			select {
+			case entries <- iterator.Entry():
+			case <-iterator.ctx.Done():
+				iterator.err = iterator.ctx.Err()
+				return
+			}
+		}
+	}()
+	return entries
+}
@@ -0,0 +1,299 @@
+package spire_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+type pageRequest struct {
+	limit, offset int
+}
+
+// Records the search requests made through a Fake, and optionally alters its responses.
+type pagingFake struct {
+	*spiretest.Fake
+	requests []pageRequest
+	// If set, the page with this (1-indexed) number fails.
+	failPage int
+	// If set, changes the pages returned by the Fake before they reach the iterator.
+	alterPage func(page *spire.EntryResultsPage)
+}
+
+func (client *pagingFake) SearchEntriesContext(ctx context.Context, token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (spire.EntryResultsPage, error) {
+	client.requests = append(client.requests, pageRequest{limit: limit, offset: offset})
+	if len(client.requests) == client.failPage {
+		return spire.EntryResultsPage{}, errors.New("search failed")
+	}
+	page, err := client.Fake.SearchEntriesContext(ctx, token, journalID, searchQuery, limit, offset, queryParameters)
+	if err == nil && client.alterPage != nil {
+		client.alterPage(&page)
+	}
+	return page, err
+}
+
+// Seeds a journal with the given number of entries, titled "entry 0", "entry 1", and so on.
+func newIteratorJournal(t *testing.T, entries int) (*spiretest.Fake, string) {
+	t.Helper()
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "iterated")
+	for i := 0; i < entries; i++ {
+		if _, err := fake.SeedEntry(journal.Id, spire.Entry{Title: fmt.Sprintf("entry %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake, journal.Id
+}
+
+func iteratedTitles(iterator *spire.EntryIterator) []string {
+	titles := []string{}
+	for iterator.Next() {
+		titles = append(titles, iterator.Entry().Title)
+	}
+	return titles
+}
+
+func ascendingTitles(from, to int) []string {
+	titles := []string{}
+	for i := from; i < to; i++ {
+		titles = append(titles, fmt.Sprintf("entry %d", i))
+	}
+	return titles
+}
+
+func TestEntryIteratorPaging(t *testing.T) {
+	cases := []struct {
+		name       string
+		entries    int
+		pageSize   int
+		maxResults int
+		titles     []string
+		requests   []pageRequest
+	}{
+		{
+			name:     "empty journal",
+			entries:  0,
+			pageSize: 3,
+			titles:   []string{},
+			requests: []pageRequest{{3, 0}},
+		},
+		{
+			name:     "single page",
+			entries:  2,
+			pageSize: 3,
+			titles:   ascendingTitles(0, 2),
+			requests: []pageRequest{{3, 0}},
+		},
+		{
+			name:     "last page is partial",
+			entries:  7,
+			pageSize: 3,
+			titles:   ascendingTitles(0, 7),
+			requests: []pageRequest{{3, 0}, {3, 3}, {3, 6}},
+		},
+		{
+			name:     "last page is full",
+			entries:  6,
+			pageSize: 3,
+			titles:   ascendingTitles(0, 6),
+			requests: []pageRequest{{3, 0}, {3, 3}},
+		},
+		{
+			name:       "limit inside a page",
+			entries:    7,
+			pageSize:   3,
+			maxResults: 5,
+			titles:     ascendingTitles(0, 5),
+			requests:   []pageRequest{{3, 0}, {2, 3}},
+		},
+		{
+			name:       "limit at the end of a page",
+			entries:    7,
+			pageSize:   3,
+			maxResults: 6,
+			titles:     ascendingTitles(0, 6),
+			requests:   []pageRequest{{3, 0}, {3, 3}},
+		},
+		{
+			name:       "limit smaller than a page",
+			entries:    7,
+			pageSize:   3,
+			maxResults: 2,
+			titles:     ascendingTitles(0, 2),
+			requests:   []pageRequest{{2, 0}},
+		},
+		{
+			name:       "limit beyond the last entry",
+			entries:    4,
+			pageSize:   3,
+			maxResults: 10,
+			titles:     ascendingTitles(0, 4),
+			requests:   []pageRequest{{3, 0}, {3, 3}},
+		},
+		{
+			name:     "default page size",
+			entries:  3,
+			pageSize: 0,
+			titles:   ascendingTitles(0, 3),
+			requests: []pageRequest{{spire.DefaultIteratorPageSize, 0}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fake, journalID := newIteratorJournal(t, c.entries)
+			client := &pagingFake{Fake: fake}
+
+			iterator := spire.NewEntryIterator(client, "token", journalID, "", map[string]string{"order": "asc"})
+			iterator.PageSize = c.pageSize
+			iterator.MaxResults = c.maxResults
+			titles := iteratedTitles(iterator)
+
+			if err := iterator.Err(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(titles, c.titles) {
+				t.Errorf("Expected entries %v, got %v", c.titles, titles)
+			}
+			if !reflect.DeepEqual(client.requests, c.requests) {
+				t.Errorf("Expected requests %v, got %v", c.requests, client.requests)
+			}
+			if iterator.TotalResults() != c.entries {
+				t.Errorf("Expected %d total results, got %d", c.entries, iterator.TotalResults())
+			}
+			if iterator.Next() {
+				t.Errorf("Next returned true after the iteration ended")
+			}
+		})
+	}
+}
+
+func TestEntryIteratorNextOffset(t *testing.T) {
+	fake, journalID := newIteratorJournal(t, 7)
+	client := &pagingFake{Fake: fake}
+	// The server skips an entry between the first and second pages.
+	client.alterPage = func(page *spire.EntryResultsPage) {
+		if page.Offset == 0 {
+			page.NextOffset = 4
+		}
+	}
+
+	iterator := spire.NewEntryIterator(client, "token", journalID, "", map[string]string{"order": "asc"})
+	iterator.PageSize = 3
+	titles := iteratedTitles(iterator)
+
+	if err := iterator.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := append(ascendingTitles(0, 3), ascendingTitles(4, 7)...)
+	if !reflect.DeepEqual(titles, expected) {
+		t.Errorf("Expected entries %v, got %v", expected, titles)
+	}
+	expectedRequests := []pageRequest{{3, 0}, {3, 4}}
+	if !reflect.DeepEqual(client.requests, expectedRequests) {
+		t.Errorf("Expected requests %v, got %v", expectedRequests, client.requests)
+	}
+}
+
+func TestEntryIteratorWithoutNextOffset(t *testing.T) {
+	fake, journalID := newIteratorJournal(t, 5)
+	client := &pagingFake{Fake: fake}
+	// Without next_offset, the iterator continues after the entries it has received.
+	client.alterPage = func(page *spire.EntryResultsPage) {
+		page.NextOffset = 0
+	}
+
+	iterator := spire.NewEntryIterator(client, "token", journalID, "", map[string]string{"order": "asc"})
+	iterator.PageSize = 2
+	titles := iteratedTitles(iterator)
+
+	if !reflect.DeepEqual(titles, ascendingTitles(0, 5)) {
+		t.Errorf("Expected all entries, got %v", titles)
+	}
+	expectedRequests := []pageRequest{{2, 0}, {2, 2}, {2, 4}}
+	if !reflect.DeepEqual(client.requests, expectedRequests) {
+		t.Errorf("Expected requests %v, got %v", expectedRequests, client.requests)
+	}
+}
+
+func TestEntryIteratorErrors(t *testing.T) {
+	fake, journalID := newIteratorJournal(t, 5)
+	client := &pagingFake{Fake: fake, failPage: 2}
+
+	iterator := spire.NewEntryIterator(client, "token", journalID, "", map[string]string{"order": "asc"})
+	iterator.PageSize = 2
+	titles := iteratedTitles(iterator)
+
+	if iterator.Err() == nil || iterator.Err().Error() != "search failed" {
+		t.Errorf("Expected the search error, got %v", iterator.Err())
+	}
+	if !reflect.DeepEqual(titles, ascendingTitles(0, 2)) {
+		t.Errorf("Expected the entries of the first page, got %v", titles)
+	}
+	if iterator.Next() || len(client.requests) != 2 {
+		t.Errorf("Expected the iterator to stop after the error, got %d requests", len(client.requests))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client = &pagingFake{Fake: fake}
+	iterator = spire.NewEntryIteratorContext(ctx, client, "token", journalID, "", nil)
+	if iterator.Next() {
+		t.Errorf("Next returned true with a cancelled context")
+	}
+	if !errors.Is(iterator.Err(), context.Canceled) || len(client.requests) != 0 {
+		t.Errorf("Expected a cancellation error without requests, got %v and %d requests", iterator.Err(), len(client.requests))
+	}
+}
+
+func TestEntryIteratorChannel(t *testing.T) {
+	fake, journalID := newIteratorJournal(t, 5)
+	iterator := spire.NewEntryIterator(fake, "token", journalID, "", map[string]string{"order": "asc"})
+	iterator.PageSize = 2
+
+	titles := []string{}
+	for entry := range iterator.Entries() {
+		titles = append(titles, entry.Title)
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(titles, ascendingTitles(0, 5)) {
+		t.Errorf("Expected all entries, got %v", titles)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iterator = spire.NewEntryIteratorContext(ctx, fake, "token", journalID, "", nil)
+	iterator.PageSize = 2
+	entries := iterator.Entries()
+	<-entries
+	cancel()
+	for range entries {
+	}
+	if !errors.Is(iterator.Err(), context.Canceled) {
+		t.Errorf("Expected a cancellation error, got %v", iterator.Err())
+	}
+}
+
+func TestEntryIteratorEntryIDs(t *testing.T) {
+	fake, journalID := newIteratorJournal(t, 3)
+	expected := []string{}
+	for _, entry := range fake.Entries(journalID) {
+		expected = append(expected, entry.Id)
+	}
+
+	iterator := spire.NewEntryIterator(fake, "token", journalID, "", map[string]string{"order": "asc"})
+	ids := []string{}
+	for iterator.Next() {
+		ids = append(ids, iterator.Entry().Id)
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected entry IDs %v, got %v", expected, ids)
+	}
+}
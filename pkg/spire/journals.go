@@ -2,6 +2,7 @@ package spire
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,10 @@ import (
 )
 
 func (client SpireClient) CreateJournal(token, name string) (Journal, error) {
+	return client.CreateJournalContext(context.Background(), token, name)
+}
+
+func (client SpireClient) CreateJournalContext(ctx context.Context, token, name string) (Journal, error) {
 	journalsRoute := fmt.Sprintf("%s/", client.Routes.Journals)
 	requestBody := journalCreateRequest{Name: name}
 	requestBuffer := new(bytes.Buffer)
@@ -18,7 +23,7 @@ func (client SpireClient) CreateJournal(token, name string) (Journal, error) {
 	if encodeErr != nil {
 		return Journal{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("POST", journalsRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", journalsRoute, requestBuffer)
 	if requestErr != nil {
 		return Journal{}, requestErr
 	}
@@ -26,7 +31,7 @@ func (client SpireClient) CreateJournal(token, name string) (Journal, error) {
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "CreateJournal")
 	if responseErr != nil {
 		return Journal{}, responseErr
 	}
@@ -43,15 +48,19 @@ func (client SpireClient) CreateJournal(token, name string) (Journal, error) {
 }
 
 func (client SpireClient) GetJournal(token, journalID string) (Journal, error) {
+	return client.GetJournalContext(context.Background(), token, journalID)
+}
+
+func (client SpireClient) GetJournalContext(ctx context.Context, token, journalID string) (Journal, error) {
 	journalRoute := fmt.Sprintf("%s/%s", client.Routes.Journals, journalID)
-	request, requestErr := http.NewRequest("GET", journalRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", journalRoute, nil)
 	if requestErr != nil {
 		return Journal{}, requestErr
 	}
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "GetJournal")
 	if responseErr != nil {
 		return Journal{}, responseErr
 	}
@@ -68,16 +77,20 @@ func (client SpireClient) GetJournal(token, journalID string) (Journal, error) {
 }
 
 func (client SpireClient) ListJournals(token string) (JournalsList, error) {
+	return client.ListJournalsContext(context.Background(), token)
+}
+
+func (client SpireClient) ListJournalsContext(ctx context.Context, token string) (JournalsList, error) {
 	// Have to add trailing slash because of how we set the route on API
 	journalsRoute := fmt.Sprintf("%s/", client.Routes.Journals)
-	request, requestErr := http.NewRequest("GET", journalsRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", journalsRoute, nil)
 	if requestErr != nil {
 		return JournalsList{}, requestErr
 	}
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "ListJournals")
 	if responseErr != nil {
 		return JournalsList{}, responseErr
 	}
@@ -94,6 +107,10 @@ func (client SpireClient) ListJournals(token string) (JournalsList, error) {
 }
 
 func (client SpireClient) UpdateJournal(token, journalID, name string) (Journal, error) {
+	return client.UpdateJournalContext(context.Background(), token, journalID, name)
+}
+
+func (client SpireClient) UpdateJournalContext(ctx context.Context, token, journalID, name string) (Journal, error) {
 	journalRoute := fmt.Sprintf("%s/%s", client.Routes.Journals, journalID)
 	requestBody := journalCreateRequest{Name: name}
 	requestBuffer := new(bytes.Buffer)
@@ -101,7 +118,7 @@ func (client SpireClient) UpdateJournal(token, journalID, name string) (Journal,
 	if encodeErr != nil {
 		return Journal{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("PUT", journalRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "PUT", journalRoute, requestBuffer)
 	if requestErr != nil {
 		return Journal{}, requestErr
 	}
@@ -109,7 +126,7 @@ func (client SpireClient) UpdateJournal(token, journalID, name string) (Journal,
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "UpdateJournal")
 	if responseErr != nil {
 		return Journal{}, responseErr
 	}
@@ -126,15 +143,19 @@ func (client SpireClient) UpdateJournal(token, journalID, name string) (Journal,
 }
 
 func (client SpireClient) DeleteJournal(token, journalID string) (Journal, error) {
+	return client.DeleteJournalContext(context.Background(), token, journalID)
+}
+
+func (client SpireClient) DeleteJournalContext(ctx context.Context, token, journalID string) (Journal, error) {
 	journalRoute := fmt.Sprintf("%s/%s", client.Routes.Journals, journalID)
-	request, requestErr := http.NewRequest("DELETE", journalRoute, nil)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", journalRoute, nil)
 	if requestErr != nil {
 		return Journal{}, requestErr
 	}
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "DeleteJournal")
 	if responseErr != nil {
 		return Journal{}, responseErr
 	}
@@ -180,6 +201,10 @@ func IsValidJournalPermission(permission string) bool {
 }
 
 func (client SpireClient) AddJournalMember(token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
+	return client.AddJournalMemberContext(context.Background(), token, journalID, memberID, memberType, permissions)
+}
+
+func (client SpireClient) AddJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
 	// Validate memberType and permissions
 	if !IsValidMemberType(memberType) {
 		return JournalPermissionsList{}, fmt.Errorf("Invalid memberType: %s. Choices: %s", memberType, strings.Join(ValidMemberTypes(), ","))
@@ -208,7 +233,7 @@ func (client SpireClient) AddJournalMember(token, journalID, memberID, memberTyp
 	if encodeErr != nil {
 		return JournalPermissionsList{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("POST", scopesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "POST", scopesRoute, requestBuffer)
 	if requestErr != nil {
 		return JournalPermissionsList{}, requestErr
 	}
@@ -216,7 +241,7 @@ func (client SpireClient) AddJournalMember(token, journalID, memberID, memberTyp
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "AddJournalMember")
 	if responseErr != nil {
 		return JournalPermissionsList{}, responseErr
 	}
@@ -233,6 +258,10 @@ func (client SpireClient) AddJournalMember(token, journalID, memberID, memberTyp
 }
 
 func (client SpireClient) RemoveJournalMember(token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
+	return client.RemoveJournalMemberContext(context.Background(), token, journalID, memberID, memberType, permissions)
+}
+
+func (client SpireClient) RemoveJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
 	// Validate memberType and permissions
 	if !IsValidMemberType(memberType) {
 		return JournalPermissionsList{}, fmt.Errorf("Invalid memberType: %s. Choices: %s", memberType, strings.Join(ValidMemberTypes(), ","))
@@ -261,7 +290,7 @@ func (client SpireClient) RemoveJournalMember(token, journalID, memberID, member
 	if encodeErr != nil {
 		return JournalPermissionsList{}, encodeErr
 	}
-	request, requestErr := http.NewRequest("DELETE", scopesRoute, requestBuffer)
+	request, requestErr := http.NewRequestWithContext(ctx, "DELETE", scopesRoute, requestBuffer)
 	if requestErr != nil {
 		return JournalPermissionsList{}, requestErr
 	}
@@ -269,7 +298,37 @@ func (client SpireClient) RemoveJournalMember(token, journalID, memberID, member
 	request.Header.Add("Accept", "application/json")
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	response, responseErr := client.HTTPClient.Do(request)
+	response, responseErr := client.do(request, "RemoveJournalMember")
+	if responseErr != nil {
+		return JournalPermissionsList{}, responseErr
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return JournalPermissionsList{}, statusErr
+	}
+
+	var permissionsList JournalPermissionsList
+	decodeErr := json.NewDecoder(response.Body).Decode(&permissionsList)
+	return permissionsList, decodeErr
+}
+
+func (client SpireClient) ListJournalMembers(token, journalID string) (JournalPermissionsList, error) {
+	return client.ListJournalMembersContext(context.Background(), token, journalID)
+}
+
+// ListJournalMembersContext returns every permission held on a journal, by users and groups alike.
+func (client SpireClient) ListJournalMembersContext(ctx context.Context, token, journalID string) (JournalPermissionsList, error) {
+	scopesRoute := fmt.Sprintf("%s/%s/scopes", client.Routes.Journals, journalID)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", scopesRoute, nil)
+	if requestErr != nil {
+		return JournalPermissionsList{}, requestErr
+	}
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	response, responseErr := client.do(request, "ListJournalMembers")
 	if responseErr != nil {
 		return JournalPermissionsList{}, responseErr
 	}
@@ -284,3 +343,46 @@ func (client SpireClient) RemoveJournalMember(token, journalID, memberID, member
 	decodeErr := json.NewDecoder(response.Body).Decode(&permissionsList)
 	return permissionsList, decodeErr
 }
+
+func (client SpireClient) CheckJournalPermission(token, journalID, permission string) (bool, error) {
+	return client.CheckJournalPermissionContext(context.Background(), token, journalID, permission)
+}
+
+// CheckJournalPermissionContext reports whether the user that the token belongs to holds the given
+// permission on a journal, either directly or through one of their groups.
+func (client SpireClient) CheckJournalPermissionContext(ctx context.Context, token, journalID, permission string) (bool, error) {
+	if !IsValidJournalPermission(permission) {
+		return false, fmt.Errorf("Invalid permission: %s. Choices: %s", permission, strings.Join(ValidJournalPermissions(), ","))
+	}
+
+	permissionsRoute := fmt.Sprintf("%s/%s/permissions", client.Routes.Journals, journalID)
+	request, requestErr := http.NewRequestWithContext(ctx, "GET", permissionsRoute, nil)
+	if requestErr != nil {
+		return false, requestErr
+	}
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	response, responseErr := client.do(request, "CheckJournalPermission")
+	if responseErr != nil {
+		return false, responseErr
+	}
+	defer response.Body.Close()
+
+	statusErr := utils.HTTPStatusCheck(response)
+	if statusErr != nil {
+		return false, statusErr
+	}
+
+	var userPermissions JournalUserPermissions
+	decodeErr := json.NewDecoder(response.Body).Decode(&userPermissions)
+	if decodeErr != nil {
+		return false, decodeErr
+	}
+	for _, heldPermission := range userPermissions.Permissions {
+		if heldPermission == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,12 @@
+package spire
+
+import (
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// Middleware hooks into every request made by a SpireClient. See utils.Middleware.
+type Middleware = utils.Middleware
+
+type RequestInfo = utils.RequestInfo
+
+type ResponseInfo = utils.ResponseInfo
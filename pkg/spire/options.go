@@ -0,0 +1,40 @@
+package spire
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// ClientOption configures a SpireClient created by NewClientWithOptions or ClientFromEnv. The same
+// options can be passed to bugout.ClientFromEnv.
+type ClientOption = utils.ClientOption
+
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return utils.WithHTTPClient(httpClient)
+}
+
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return utils.WithTransport(transport)
+}
+
+func WithTimeout(timeout time.Duration) ClientOption {
+	return utils.WithTimeout(timeout)
+}
+
+func WithUserAgent(userAgent string) ClientOption {
+	return utils.WithUserAgent(userAgent)
+}
+
+func WithHeader(key, value string) ClientOption {
+	return utils.WithHeader(key, value)
+}
+
+func WithRetry(policy RetryPolicy) ClientOption {
+	return utils.WithRetry(policy)
+}
+
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return utils.WithMiddleware(middleware...)
+}
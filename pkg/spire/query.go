@@ -0,0 +1,301 @@
+package spire
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values for the Order field of a SearchQuery
+const (
+	SearchOrderAscending  string = "asc"
+	SearchOrderDescending string = "desc"
+)
+
+// SearchQuery is a structured representation of a Spire search query. Instead of building the
+// query string by hand, fill in a SearchQuery and pass its rendered form to SearchEntries:
+//
+//	query := spire.SearchQuery{
+//		RequiredTags: []string{"deploy", "prod"},
+//		CreatedAfter: time.Now().Add(-24 * time.Hour),
+//		Order:        spire.SearchOrderDescending,
+//	}
+//	page, err := client.SearchEntries(token, journalID, query.Query(), 10, 0, query.Parameters())
+//
+// All the conditions in a query must hold for an entry to match it. Zero values mean that the
+// corresponding condition is not applied.
+type SearchQuery struct {
+	// Tags which matching entries must have ("tag:<tag>")
+	RequiredTags []string
+	// Tags which matching entries must not have ("!tag:<tag>")
+	ExcludedTags []string
+	// Free text terms, matched against the title and content of entries
+	Terms []string
+	// Terms matched against the title of entries only ("title:<term>")
+	TitleTerms []string
+	// Terms matched against the content of entries only ("content:<term>")
+	ContentTerms []string
+
+	// Creation and update time ranges ("created_at:>...", "updated_at:<..." and so on)
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// Context of matching entries ("context_type:<type>", "context_id:<id>")
+	ContextType string
+	ContextID   string
+
+	// Order of results by creation time: SearchOrderAscending or SearchOrderDescending. Spire's
+	// default ordering is used if it is empty.
+	Order string
+	// If true, Spire returns entries without their content
+	ExcludeContent bool
+
+	// Any other query parameters to send along with the search
+	ExtraParameters map[string]string
+}
+
+// Prefixes which introduce fields in the search syntax
+const (
+	searchPrefixTag         = "tag:"
+	searchPrefixExcludedTag = "!tag:"
+	searchPrefixTitle       = "title:"
+	searchPrefixContent     = "content:"
+	searchPrefixCreatedAt   = "created_at:"
+	searchPrefixUpdatedAt   = "updated_at:"
+	searchPrefixContextType = "context_type:"
+	searchPrefixContextID   = "context_id:"
+)
+
+// Query parameters which SearchQuery manages outside of the "q" string
+const (
+	searchParameterOrder   = "order"
+	searchParameterContent = "content"
+)
+
+var searchPrefixes = []string{
+	searchPrefixTag,
+	searchPrefixExcludedTag,
+	searchPrefixTitle,
+	searchPrefixContent,
+	searchPrefixCreatedAt,
+	searchPrefixUpdatedAt,
+	searchPrefixContextType,
+	searchPrefixContextID,
+}
+
+// Query renders the query into the Spire search syntax, for use as the searchQuery argument to
+// SearchEntries.
+func (query SearchQuery) Query() string {
+	terms := []string{}
+	for _, tag := range query.RequiredTags {
+		terms = append(terms, searchPrefixTag+tag)
+	}
+	for _, tag := range query.ExcludedTags {
+		terms = append(terms, searchPrefixExcludedTag+tag)
+	}
+	if query.ContextType != "" {
+		terms = append(terms, searchPrefixContextType+query.ContextType)
+	}
+	if query.ContextID != "" {
+		terms = append(terms, searchPrefixContextID+query.ContextID)
+	}
+	if !query.CreatedAfter.IsZero() {
+		terms = append(terms, searchPrefixCreatedAt+">"+query.CreatedAfter.Format(time.RFC3339Nano))
+	}
+	if !query.CreatedBefore.IsZero() {
+		terms = append(terms, searchPrefixCreatedAt+"<"+query.CreatedBefore.Format(time.RFC3339Nano))
+	}
+	if !query.UpdatedAfter.IsZero() {
+		terms = append(terms, searchPrefixUpdatedAt+">"+query.UpdatedAfter.Format(time.RFC3339Nano))
+	}
+	if !query.UpdatedBefore.IsZero() {
+		terms = append(terms, searchPrefixUpdatedAt+"<"+query.UpdatedBefore.Format(time.RFC3339Nano))
+	}
+	for _, term := range query.TitleTerms {
+		terms = append(terms, searchPrefixTitle+term)
+	}
+	for _, term := range query.ContentTerms {
+		terms = append(terms, searchPrefixContent+term)
+	}
+	terms = append(terms, query.Terms...)
+	return strings.Join(terms, " ")
+}
+
+// Parameters returns the query parameters to pass to SearchEntries along with the rendered query.
+func (query SearchQuery) Parameters() map[string]string {
+	parameters := map[string]string{}
+	for key, value := range query.ExtraParameters {
+		parameters[key] = value
+	}
+	if query.Order != "" {
+		parameters[searchParameterOrder] = query.Order
+	}
+	if query.ExcludeContent {
+		parameters[searchParameterContent] = "false"
+	}
+	return parameters
+}
+
+// String returns the rendered query.
+func (query SearchQuery) String() string {
+	return query.Query()
+}
+
+// Validate checks that the query can be rendered into the Spire search syntax and means the same
+// thing when Spire parses it back.
+func (query SearchQuery) Validate() error {
+	checks := []struct {
+		name     string
+		values   []string
+		optional bool
+	}{
+		{"tag", query.RequiredTags, false},
+		{"excluded tag", query.ExcludedTags, false},
+		{"title term", query.TitleTerms, false},
+		{"content term", query.ContentTerms, false},
+		{"context type", []string{query.ContextType}, true},
+		{"context ID", []string{query.ContextID}, true},
+	}
+	for _, check := range checks {
+		for _, value := range check.values {
+			if value == "" && !check.optional {
+				return fmt.Errorf("Invalid search query: empty %s", check.name)
+			}
+			if strings.ContainsAny(value, " \t\r\n") {
+				return fmt.Errorf("Invalid search query: %s contains whitespace: %q", check.name, value)
+			}
+		}
+	}
+
+	for _, term := range query.Terms {
+		if term == "" || strings.ContainsAny(term, " \t\r\n") {
+			return fmt.Errorf("Invalid search query: terms must be single non-empty words: %q", term)
+		}
+		for _, prefix := range searchPrefixes {
+			if strings.HasPrefix(term, prefix) {
+				return fmt.Errorf("Invalid search query: term would be interpreted as a %s condition: %q", strings.TrimSuffix(prefix, ":"), term)
+			}
+		}
+	}
+
+	if !query.CreatedAfter.IsZero() && !query.CreatedBefore.IsZero() && !query.CreatedAfter.Before(query.CreatedBefore) {
+		return fmt.Errorf("Invalid search query: created after %s and before %s", query.CreatedAfter.Format(time.RFC3339), query.CreatedBefore.Format(time.RFC3339))
+	}
+	if !query.UpdatedAfter.IsZero() && !query.UpdatedBefore.IsZero() && !query.UpdatedAfter.Before(query.UpdatedBefore) {
+		return fmt.Errorf("Invalid search query: updated after %s and before %s", query.UpdatedAfter.Format(time.RFC3339), query.UpdatedBefore.Format(time.RFC3339))
+	}
+
+	if query.Order != "" && query.Order != SearchOrderAscending && query.Order != SearchOrderDescending {
+		return fmt.Errorf("Invalid search query: order must be %s or %s: %s", SearchOrderAscending, SearchOrderDescending, query.Order)
+	}
+
+	return nil
+}
+
+// ParseSearchQuery parses a query in the Spire search syntax, along with the query parameters
+// that would be sent with it, into a SearchQuery. It returns an error if the query is malformed.
+//
+// Time ranges are given as "created_at:>TIME", "created_at:<TIME", "updated_at:>TIME" and
+// "updated_at:<TIME", where TIME is in RFC 3339 format or a date (YYYY-MM-DD, in UTC).
+func ParseSearchQuery(searchQuery string, queryParameters map[string]string) (SearchQuery, error) {
+	query := SearchQuery{}
+
+	for _, term := range strings.Fields(searchQuery) {
+		switch {
+		case strings.HasPrefix(term, searchPrefixExcludedTag):
+			query.ExcludedTags = append(query.ExcludedTags, strings.TrimPrefix(term, searchPrefixExcludedTag))
+		case strings.HasPrefix(term, searchPrefixTag):
+			query.RequiredTags = append(query.RequiredTags, strings.TrimPrefix(term, searchPrefixTag))
+		case strings.HasPrefix(term, searchPrefixTitle):
+			query.TitleTerms = append(query.TitleTerms, strings.TrimPrefix(term, searchPrefixTitle))
+		case strings.HasPrefix(term, searchPrefixContent):
+			query.ContentTerms = append(query.ContentTerms, strings.TrimPrefix(term, searchPrefixContent))
+		case strings.HasPrefix(term, searchPrefixContextType):
+			if query.ContextType != "" {
+				return query, fmt.Errorf("Invalid search query: context_type given more than once")
+			}
+			query.ContextType = strings.TrimPrefix(term, searchPrefixContextType)
+		case strings.HasPrefix(term, searchPrefixContextID):
+			if query.ContextID != "" {
+				return query, fmt.Errorf("Invalid search query: context_id given more than once")
+			}
+			query.ContextID = strings.TrimPrefix(term, searchPrefixContextID)
+		case strings.HasPrefix(term, searchPrefixCreatedAt):
+			if err := parseTimeRange(term, &query.CreatedAfter, &query.CreatedBefore); err != nil {
+				return query, err
+			}
+		case strings.HasPrefix(term, searchPrefixUpdatedAt):
+			if err := parseTimeRange(term, &query.UpdatedAfter, &query.UpdatedBefore); err != nil {
+				return query, err
+			}
+		case strings.HasPrefix(term, "!"):
+			return query, fmt.Errorf("Invalid search query: only tags can be excluded: %s", term)
+		default:
+			query.Terms = append(query.Terms, term)
+		}
+	}
+
+	for key, value := range queryParameters {
+		switch key {
+		case searchParameterOrder:
+			query.Order = value
+		case searchParameterContent:
+			includeContent, parseErr := strconv.ParseBool(value)
+			if parseErr != nil {
+				return query, fmt.Errorf("Invalid search query: content parameter must be true or false: %s", value)
+			}
+			query.ExcludeContent = !includeContent
+		case "q", "limit", "offset":
+			// SearchEntries sets these itself.
+		default:
+			if query.ExtraParameters == nil {
+				query.ExtraParameters = map[string]string{}
+			}
+			query.ExtraParameters[key] = value
+		}
+	}
+
+	return query, query.Validate()
+}
+
+// Parses a "created_at:" or "updated_at:" condition into the after or before bound of a range.
+func parseTimeRange(term string, after, before *time.Time) error {
+	field := term[:strings.Index(term, ":")]
+	condition := term[len(field)+1:]
+	if condition == "" {
+		return fmt.Errorf("Invalid search query: empty %s condition", field)
+	}
+
+	bound := after
+	switch condition[0] {
+	case '>':
+	case '<':
+		bound = before
+	default:
+		return fmt.Errorf("Invalid search query: %s condition must start with > or <: %s", field, term)
+	}
+	if !bound.IsZero() {
+		return fmt.Errorf("Invalid search query: %s bound given more than once: %s", field, term)
+	}
+
+	value := condition[1:]
+	parsed, parseErr := ParseSearchTime(value)
+	if parseErr != nil {
+		return fmt.Errorf("Invalid search query: could not parse time in %s condition (use RFC 3339 or YYYY-MM-DD): %s", field, value)
+	}
+	*bound = parsed
+	return nil
+}
+
+// ParseSearchTime parses a time in one of the formats accepted by time range conditions: RFC 3339
+// or a date (YYYY-MM-DD, in UTC).
+func ParseSearchTime(value string) (time.Time, error) {
+	parsed, parseErr := time.Parse(time.RFC3339Nano, value)
+	if parseErr == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
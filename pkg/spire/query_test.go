@@ -0,0 +1,269 @@
+package spire_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		parameters map[string]string
+		expected   spire.SearchQuery
+	}{
+		{
+			name:     "empty",
+			query:    "",
+			expected: spire.SearchQuery{},
+		},
+		{
+			name:  "tags",
+			query: "tag:deploy tag:prod",
+			expected: spire.SearchQuery{
+				RequiredTags: []string{"deploy", "prod"},
+			},
+		},
+		{
+			name:  "negated tags",
+			query: "!tag:staging tag:deploy !tag:test",
+			expected: spire.SearchQuery{
+				RequiredTags: []string{"deploy"},
+				ExcludedTags: []string{"staging", "test"},
+			},
+		},
+		{
+			name:  "tags with colons",
+			query: "tag:os:linux !tag:arch:arm64",
+			expected: spire.SearchQuery{
+				RequiredTags: []string{"os:linux"},
+				ExcludedTags: []string{"arch:arm64"},
+			},
+		},
+		{
+			name:  "context filters",
+			query: "context_type:github context_id:bugout-dev/bugout-go",
+			expected: spire.SearchQuery{
+				ContextType: "github",
+				ContextID:   "bugout-dev/bugout-go",
+			},
+		},
+		{
+			name:  "time ranges",
+			query: "created_at:>2021-01-31 created_at:<2021-02-28T12:30:00Z updated_at:>2021-02-01T00:00:00.5Z",
+			expected: spire.SearchQuery{
+				CreatedAfter:  time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2021, 2, 28, 12, 30, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2021, 2, 1, 0, 0, 0, 500000000, time.UTC),
+			},
+		},
+		{
+			name:  "terms",
+			query: "title:outage content:postgres  timeout\tdeploy",
+			expected: spire.SearchQuery{
+				TitleTerms:   []string{"outage"},
+				ContentTerms: []string{"postgres"},
+				Terms:        []string{"timeout", "deploy"},
+			},
+		},
+		{
+			name:       "parameters",
+			query:      "tag:deploy",
+			parameters: map[string]string{"order": "asc", "content": "false", "q": "ignored", "limit": "5", "offset": "10", "extra": "value"},
+			expected: spire.SearchQuery{
+				RequiredTags:    []string{"deploy"},
+				Order:           spire.SearchOrderAscending,
+				ExcludeContent:  true,
+				ExtraParameters: map[string]string{"extra": "value"},
+			},
+		},
+		{
+			name:       "content included",
+			parameters: map[string]string{"content": "true"},
+			expected:   spire.SearchQuery{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, err := spire.ParseSearchQuery(c.query, c.parameters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(query, c.expected) {
+				t.Errorf("Expected %#v, got %#v", c.expected, query)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		parameters map[string]string
+		message    string
+	}{
+		{"empty tag", "tag:", nil, "empty tag"},
+		{"empty excluded tag", "!tag:", nil, "empty excluded tag"},
+		{"negated term", "!deploy", nil, "only tags can be excluded"},
+		{"repeated context type", "context_type:a context_type:b", nil, "context_type given more than once"},
+		{"repeated context ID", "context_id:a context_id:b", nil, "context_id given more than once"},
+		{"empty time condition", "created_at:", nil, "empty created_at condition"},
+		{"time without comparison", "created_at:2021-01-31", nil, "must start with > or <"},
+		{"repeated time bound", "updated_at:>2021-01-01 updated_at:>2021-02-01", nil, "bound given more than once"},
+		{"malformed time", "created_at:>yesterday", nil, "could not parse time"},
+		{"empty time range", "created_at:>2021-02-01 created_at:<2021-01-01", nil, "created after"},
+		{"invalid order", "", map[string]string{"order": "random"}, "order must be"},
+		{"invalid content", "", map[string]string{"content": "maybe"}, "content parameter must be true or false"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := spire.ParseSearchQuery(c.query, c.parameters)
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if !strings.Contains(err.Error(), c.message) {
+				t.Errorf("Expected error containing %q, got %q", c.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestSearchQueryRender(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      spire.SearchQuery
+		rendered   string
+		parameters map[string]string
+	}{
+		{
+			name:       "empty",
+			query:      spire.SearchQuery{},
+			rendered:   "",
+			parameters: map[string]string{},
+		},
+		{
+			name: "all conditions",
+			query: spire.SearchQuery{
+				RequiredTags:   []string{"deploy", "prod"},
+				ExcludedTags:   []string{"test"},
+				Terms:          []string{"timeout"},
+				TitleTerms:     []string{"outage"},
+				ContentTerms:   []string{"postgres"},
+				CreatedAfter:   time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore:  time.Date(2021, 2, 28, 12, 30, 0, 0, time.UTC),
+				ContextType:    "github",
+				ContextID:      "42",
+				Order:          spire.SearchOrderDescending,
+				ExcludeContent: true,
+			},
+			rendered:   "tag:deploy tag:prod !tag:test context_type:github context_id:42 created_at:>2021-01-31T00:00:00Z updated_at:<2021-02-28T12:30:00Z title:outage content:postgres timeout",
+			parameters: map[string]string{"order": "desc", "content": "false"},
+		},
+		{
+			name: "extra parameters",
+			query: spire.SearchQuery{
+				Order:           spire.SearchOrderAscending,
+				ExtraParameters: map[string]string{"order": "desc", "extra": "value"},
+			},
+			rendered:   "",
+			parameters: map[string]string{"order": "asc", "extra": "value"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.query.Validate(); err != nil {
+				t.Fatalf("Unexpected validation error: %v", err)
+			}
+			if rendered := c.query.Query(); rendered != c.rendered {
+				t.Errorf("Expected query %q, got %q", c.rendered, rendered)
+			}
+			if parameters := c.query.Parameters(); !reflect.DeepEqual(parameters, c.parameters) {
+				t.Errorf("Expected parameters %v, got %v", c.parameters, parameters)
+			}
+		})
+	}
+}
+
+func TestSearchQueryValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		query spire.SearchQuery
+	}{
+		{"tag with whitespace", spire.SearchQuery{RequiredTags: []string{"two words"}}},
+		{"empty title term", spire.SearchQuery{TitleTerms: []string{""}}},
+		{"context ID with whitespace", spire.SearchQuery{ContextID: "a b"}},
+		{"term with prefix", spire.SearchQuery{Terms: []string{"tag:deploy"}}},
+		{"negated tag as term", spire.SearchQuery{Terms: []string{"!tag:deploy"}}},
+		{"empty range", spire.SearchQuery{
+			UpdatedAfter:  time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedBefore: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.query.Validate(); err == nil {
+				t.Errorf("Expected %#v to be invalid", c.query)
+			}
+		})
+	}
+}
+
+func TestSearchQueryRoundTrip(t *testing.T) {
+	queries := []string{
+		"tag:deploy tag:prod !tag:test",
+		"context_type:github context_id:bugout-dev/bugout-go title:outage",
+		"created_at:>2021-01-31T00:00:00Z created_at:<2021-02-28T12:30:00.25Z updated_at:>2021-02-01T00:00:00Z updated_at:<2021-03-01T00:00:00Z",
+		"tag:deploy !tag:test context_type:slack created_at:>2021-01-31T00:00:00Z title:outage content:postgres timeout deploy",
+	}
+	parameters := map[string]string{"order": "asc", "content": "false", "extra": "value"}
+
+	for _, searchQuery := range queries {
+		t.Run(searchQuery, func(t *testing.T) {
+			parsed, err := spire.ParseSearchQuery(searchQuery, parameters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// The queries are already in canonical order, so rendering reproduces them exactly.
+			if rendered := parsed.Query(); rendered != searchQuery {
+				t.Errorf("Expected %q to render as itself, got %q", searchQuery, rendered)
+			}
+			if renderedParameters := parsed.Parameters(); !reflect.DeepEqual(renderedParameters, parameters) {
+				t.Errorf("Expected parameters %v, got %v", parameters, renderedParameters)
+			}
+
+			reparsed, err := spire.ParseSearchQuery(parsed.Query(), parsed.Parameters())
+			if err != nil {
+				t.Fatalf("Unexpected error parsing rendered query: %v", err)
+			}
+			if !reflect.DeepEqual(reparsed, parsed) {
+				t.Errorf("Query changed on round trip: %#v became %#v", parsed, reparsed)
+			}
+		})
+	}
+
+	// Queries in any other order are rendered in canonical order, after which they are stable.
+	parsed, err := spire.ParseSearchQuery("timeout !tag:test created_at:>2021-01-31 tag:deploy", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "tag:deploy !tag:test created_at:>2021-01-31T00:00:00Z timeout"
+	if rendered := parsed.Query(); rendered != expected {
+		t.Errorf("Expected %q, got %q", expected, rendered)
+	}
+	reparsed, err := spire.ParseSearchQuery(parsed.Query(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing rendered query: %v", err)
+	}
+	if reparsed.Query() != expected {
+		t.Errorf("Expected %q to be stable, got %q", expected, reparsed.Query())
+	}
+}
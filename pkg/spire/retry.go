@@ -0,0 +1,14 @@
+package spire
+
+import (
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// RetryPolicy configures how the client retries failed requests. See utils.RetryPolicy.
+type RetryPolicy = utils.RetryPolicy
+
+// DefaultRetryPolicy retries idempotent requests up to 3 times on 429, 502, 503 and 504 responses
+// and on network errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return utils.DefaultRetryPolicy()
+}
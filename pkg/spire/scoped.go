@@ -0,0 +1,214 @@
+package spire
+
+import (
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// TokenSource supplies access tokens to a ScopedClient. See utils.TokenSource.
+type TokenSource = utils.TokenSource
+
+// ScopedClient wraps a SpireCaller and a TokenSource. Its methods mirror those of SpireCaller, but
+// take the access token from the TokenSource instead of as their first argument.
+type ScopedClient struct {
+	Caller SpireCaller
+	Tokens TokenSource
+}
+
+func NewScopedClient(caller SpireCaller, tokens TokenSource) ScopedClient {
+	return ScopedClient{Caller: caller, Tokens: tokens}
+}
+
+// WithToken returns a ScopedClient which authenticates every request with the given token.
+func (client SpireClient) WithToken(token string) ScopedClient {
+	return NewScopedClient(client, utils.NewStaticTokenSource(token))
+}
+
+// WithTokenSource returns a ScopedClient which authenticates every request with a token from
+// the given TokenSource.
+func (client SpireClient) WithTokenSource(tokens TokenSource) ScopedClient {
+	return NewScopedClient(client, tokens)
+}
+
+func (client ScopedClient) Ping() (string, error) {
+	return client.Caller.Ping()
+}
+
+func (client ScopedClient) CreateJournal(name string) (Journal, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Journal{}, tokenErr
+	}
+	return client.Caller.CreateJournal(token, name)
+}
+
+func (client ScopedClient) GetJournal(journalID string) (Journal, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Journal{}, tokenErr
+	}
+	return client.Caller.GetJournal(token, journalID)
+}
+
+func (client ScopedClient) ListJournals() (JournalsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return JournalsList{}, tokenErr
+	}
+	return client.Caller.ListJournals(token)
+}
+
+func (client ScopedClient) UpdateJournal(journalID, name string) (Journal, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Journal{}, tokenErr
+	}
+	return client.Caller.UpdateJournal(token, journalID, name)
+}
+
+func (client ScopedClient) DeleteJournal(journalID string) (Journal, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Journal{}, tokenErr
+	}
+	return client.Caller.DeleteJournal(token, journalID)
+}
+
+func (client ScopedClient) AddJournalMember(journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return JournalPermissionsList{}, tokenErr
+	}
+	return client.Caller.AddJournalMember(token, journalID, memberID, memberType, permissions)
+}
+
+func (client ScopedClient) RemoveJournalMember(journalID, memberID, memberType string, permissions []string) (JournalPermissionsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return JournalPermissionsList{}, tokenErr
+	}
+	return client.Caller.RemoveJournalMember(token, journalID, memberID, memberType, permissions)
+}
+
+func (client ScopedClient) ListJournalMembers(journalID string) (JournalPermissionsList, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return JournalPermissionsList{}, tokenErr
+	}
+	caller, ok := client.Caller.(JournalMembersCaller)
+	if !ok {
+		return JournalPermissionsList{}, utils.UnsupportedError(client.Caller, "ListJournalMembers")
+	}
+	return caller.ListJournalMembers(token, journalID)
+}
+
+func (client ScopedClient) CheckJournalPermission(journalID, permission string) (bool, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return false, tokenErr
+	}
+	caller, ok := client.Caller.(JournalMembersCaller)
+	if !ok {
+		return false, utils.UnsupportedError(client.Caller, "CheckJournalPermission")
+	}
+	return caller.CheckJournalPermission(token, journalID, permission)
+}
+
+func (client ScopedClient) CreateEntry(journalID, title, content string, tags []string, entryContext EntryContext) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.CreateEntry(token, journalID, title, content, tags, entryContext)
+}
+
+func (client ScopedClient) DeleteEntry(journalID, entryID string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.DeleteEntry(token, journalID, entryID)
+}
+
+func (client ScopedClient) GetEntry(journalID, entryID string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.GetEntry(token, journalID, entryID)
+}
+
+func (client ScopedClient) ListEntries(journalID string, limit, offset int) (EntryResultsPage, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return EntryResultsPage{}, tokenErr
+	}
+	return client.Caller.ListEntries(token, journalID, limit, offset)
+}
+
+func (client ScopedClient) SearchEntries(journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (EntryResultsPage, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return EntryResultsPage{}, tokenErr
+	}
+	return client.Caller.SearchEntries(token, journalID, searchQuery, limit, offset, queryParameters)
+}
+
+func (client ScopedClient) TagEntry(journalID, entryID string, tags []string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.TagEntry(token, journalID, entryID, tags)
+}
+
+func (client ScopedClient) UntagEntry(journalID, entryID string, tags []string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.UntagEntry(token, journalID, entryID, tags)
+}
+
+func (client ScopedClient) SetEntryTags(journalID, entryID string, tags []string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	caller, ok := client.Caller.(EntryTagsCaller)
+	if !ok {
+		return Entry{}, utils.UnsupportedError(client.Caller, "SetEntryTags")
+	}
+	return caller.SetEntryTags(token, journalID, entryID, tags)
+}
+
+func (client ScopedClient) UpdateEntry(journalID, entryID, title, content string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	return client.Caller.UpdateEntry(token, journalID, entryID, title, content)
+}
+
+func (client ScopedClient) ReplaceEntry(journalID, entryID, title, content string) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	caller, ok := client.Caller.(EntryReplaceCaller)
+	if !ok {
+		return Entry{}, utils.UnsupportedError(client.Caller, "ReplaceEntry")
+	}
+	return caller.ReplaceEntry(token, journalID, entryID, title, content)
+}
+
+func (client ScopedClient) UpdateEntryIfUnchanged(journalID, entryID, title, content string, version EntryVersion) (Entry, error) {
+	token, tokenErr := client.Tokens.Token()
+	if tokenErr != nil {
+		return Entry{}, tokenErr
+	}
+	caller, ok := client.Caller.(ConditionalUpdateCaller)
+	if !ok {
+		return Entry{}, utils.UnsupportedError(client.Caller, "UpdateEntryIfUnchanged")
+	}
+	return caller.UpdateEntryIfUnchanged(token, journalID, entryID, title, content, version)
+}
@@ -0,0 +1,48 @@
+package spire_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+// Hides the optional methods of the Fake.
+type basicCaller struct {
+	spire.SpireCaller
+}
+
+func TestScopedClientOptionalMethods(t *testing.T) {
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "scoped")
+	entry, seedErr := fake.SeedEntry(journal.Id, spire.Entry{Title: "Title", Content: "Content"})
+	if seedErr != nil {
+		t.Fatal(seedErr)
+	}
+
+	client := spire.NewScopedClient(fake, utils.NewStaticTokenSource("token"))
+	replaced, err := client.ReplaceEntry(journal.Id, entry.Id, "Title", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if replaced.Content != "" {
+		t.Errorf("Expected the content to be cleared, got %q", replaced.Content)
+	}
+
+	client = spire.NewScopedClient(basicCaller{fake}, utils.NewStaticTokenSource("token"))
+	if _, err := client.ReplaceEntry(journal.Id, entry.Id, "Title", ""); !errors.Is(err, spire.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from ReplaceEntry, got %v", err)
+	}
+	if _, err := client.SetEntryTags(journal.Id, entry.Id, []string{"a"}); !errors.Is(err, spire.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from SetEntryTags, got %v", err)
+	}
+	if _, err := client.ListJournalMembers(journal.Id); !errors.Is(err, spire.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from ListJournalMembers, got %v", err)
+	}
+	if _, err := client.GetEntry(journal.Id, entry.Id); err != nil {
+		t.Errorf("Unexpected error from GetEntry: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Context variants of the Fake methods, for spire.SpireCallerContext and the optional Context
+// interfaces. They fail if the context is already done and otherwise behave exactly like the
+// methods they wrap.
+
+package spiretest
+
+import (
+	"context"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+func (fake *Fake) PingContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fake.Ping()
+}
+
+func (fake *Fake) CreateJournalContext(ctx context.Context, token, name string) (spire.Journal, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Journal{}, err
+	}
+	return fake.CreateJournal(token, name)
+}
+
+func (fake *Fake) GetJournalContext(ctx context.Context, token, journalID string) (spire.Journal, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Journal{}, err
+	}
+	return fake.GetJournal(token, journalID)
+}
+
+func (fake *Fake) ListJournalsContext(ctx context.Context, token string) (spire.JournalsList, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.JournalsList{}, err
+	}
+	return fake.ListJournals(token)
+}
+
+func (fake *Fake) UpdateJournalContext(ctx context.Context, token, journalID, name string) (spire.Journal, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Journal{}, err
+	}
+	return fake.UpdateJournal(token, journalID, name)
+}
+
+func (fake *Fake) DeleteJournalContext(ctx context.Context, token, journalID string) (spire.Journal, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Journal{}, err
+	}
+	return fake.DeleteJournal(token, journalID)
+}
+
+func (fake *Fake) AddJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+	return fake.AddJournalMember(token, journalID, memberID, memberType, permissions)
+}
+
+func (fake *Fake) RemoveJournalMemberContext(ctx context.Context, token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+	return fake.RemoveJournalMember(token, journalID, memberID, memberType, permissions)
+}
+
+func (fake *Fake) ListJournalMembersContext(ctx context.Context, token, journalID string) (spire.JournalPermissionsList, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+	return fake.ListJournalMembers(token, journalID)
+}
+
+func (fake *Fake) CheckJournalPermissionContext(ctx context.Context, token, journalID, permission string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return fake.CheckJournalPermission(token, journalID, permission)
+}
+
+func (fake *Fake) CreateEntryContext(ctx context.Context, token, journalID, title, content string, tags []string, entryContext spire.EntryContext) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.CreateEntry(token, journalID, title, content, tags, entryContext)
+}
+
+func (fake *Fake) DeleteEntryContext(ctx context.Context, token, journalID, entryID string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.DeleteEntry(token, journalID, entryID)
+}
+
+func (fake *Fake) GetEntryContext(ctx context.Context, token, journalID, entryID string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.GetEntry(token, journalID, entryID)
+}
+
+func (fake *Fake) ListEntriesContext(ctx context.Context, token, journalID string, limit, offset int) (spire.EntryResultsPage, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.EntryResultsPage{}, err
+	}
+	return fake.ListEntries(token, journalID, limit, offset)
+}
+
+func (fake *Fake) SearchEntriesContext(ctx context.Context, token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (spire.EntryResultsPage, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.EntryResultsPage{}, err
+	}
+	return fake.SearchEntries(token, journalID, searchQuery, limit, offset, queryParameters)
+}
+
+func (fake *Fake) TagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.TagEntry(token, journalID, entryID, tags)
+}
+
+func (fake *Fake) UntagEntryContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.UntagEntry(token, journalID, entryID, tags)
+}
+
+func (fake *Fake) SetEntryTagsContext(ctx context.Context, token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.SetEntryTags(token, journalID, entryID, tags)
+}
+
+func (fake *Fake) UpdateEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.UpdateEntry(token, journalID, entryID, title, content)
+}
+
+func (fake *Fake) ReplaceEntryContext(ctx context.Context, token, journalID, entryID, title, content string) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.ReplaceEntry(token, journalID, entryID, title, content)
+}
+
+func (fake *Fake) UpdateEntryIfUnchangedContext(ctx context.Context, token, journalID, entryID, title, content string, version spire.EntryVersion) (spire.Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return spire.Entry{}, err
+	}
+	return fake.UpdateEntryIfUnchanged(token, journalID, entryID, title, content, version)
+}
@@ -0,0 +1,789 @@
+// Package spiretest provides an in-memory implementation of spire.SpireCaller for use in tests.
+package spiretest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/brood"
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+// Authenticator resolves Bugout access tokens to users. *broodtest.Fake and brood.BroodClient
+// both satisfy it.
+type Authenticator interface {
+	Auth(token string) (brood.AuthUser, error)
+}
+
+type holderRecord struct {
+	HolderType  string   `json:"holder_type"`
+	Permissions []string `json:"permissions"`
+}
+
+type journalRecord struct {
+	Journal spire.Journal `json:"journal"`
+	// Maps holder IDs to their permissions on the journal
+	Holders map[string]*holderRecord `json:"holders"`
+	Entries map[string]*spire.Entry  `json:"entries"`
+}
+
+// The complete contents of a Fake, in the form in which SaveState serializes it.
+type fakeState struct {
+	// Maps access tokens to the users they belong to. Only used if the Fake has no Authenticator.
+	Users    map[string]brood.AuthUser `json:"users"`
+	Journals map[string]*journalRecord `json:"journals"`
+	Counter  int                       `json:"counter"`
+}
+
+func newState() fakeState {
+	return fakeState{
+		Users:    map[string]brood.AuthUser{},
+		Journals: map[string]*journalRecord{},
+	}
+}
+
+// Fake is a stateful, in-memory implementation of spire.SpireCaller, spire.SpireCallerContext and
+// the optional interfaces such as spire.EntryTagsCaller. It enforces journal permissions the same
+// way Spire does, and returns *spire.APIError values with matching status codes on failure. It is
+// safe for concurrent use.
+//
+// Tokens are resolved using the Authenticator if one is set (for example, a *broodtest.Fake), and
+// otherwise using the users registered with SeedUser.
+type Fake struct {
+	// Now returns the current time. Override it to make timestamps deterministic.
+	Now func() time.Time
+	// Base URL used to build the entry_url and journal_url fields of entries.
+	SpireURL      string
+	Authenticator Authenticator
+
+	mu    sync.Mutex
+	state fakeState
+}
+
+var _ spire.SpireCaller = (*Fake)(nil)
+var _ spire.SpireCallerContext = (*Fake)(nil)
+var _ spire.JournalMembersCaller = (*Fake)(nil)
+var _ spire.JournalMembersCallerContext = (*Fake)(nil)
+var _ spire.EntryTagsCaller = (*Fake)(nil)
+var _ spire.EntryTagsCallerContext = (*Fake)(nil)
+var _ spire.EntryReplaceCaller = (*Fake)(nil)
+var _ spire.EntryReplaceCallerContext = (*Fake)(nil)
+var _ spire.ConditionalUpdateCaller = (*Fake)(nil)
+var _ spire.ConditionalUpdateCallerContext = (*Fake)(nil)
+
+func NewFake() *Fake {
+	return &Fake{Now: time.Now, SpireURL: spire.BugoutSpireURL, state: newState()}
+}
+
+func apiError(statusCode int, detail string) error {
+	return &spire.APIError{StatusCode: statusCode, Detail: detail, Body: fmt.Sprintf(`{"detail":%q}`, detail)}
+}
+
+func (fake *Fake) newID() string {
+	fake.state.Counter++
+	return fmt.Sprintf("00000000-0000-4000-9000-%012d", fake.state.Counter)
+}
+
+// Bugout APIs return timestamps with microsecond precision.
+func (fake *Fake) timestamp() time.Time {
+	return fake.Now().UTC().Truncate(time.Microsecond)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Appends the values which are not already present, preserving order.
+func appendUnique(values []string, newValues ...string) []string {
+	for _, value := range newValues {
+		if !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Must be called without holding the lock, since the Authenticator may be slow or may call back
+// into other fakes.
+func (fake *Fake) authenticate(token string) (brood.AuthUser, error) {
+	if fake.Authenticator != nil {
+		return fake.Authenticator.Auth(token)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	user, exists := fake.state.Users[token]
+	if !exists {
+		return brood.AuthUser{}, apiError(http.StatusUnauthorized, "Access token not found")
+	}
+	return user, nil
+}
+
+func holderIDs(user brood.AuthUser) []string {
+	ids := []string{user.UserId}
+	for _, group := range user.Groups {
+		ids = append(ids, group.GroupId)
+	}
+	return ids
+}
+
+func (fake *Fake) hasPermission(journal *journalRecord, user brood.AuthUser, permission string) bool {
+	for _, holderID := range holderIDs(user) {
+		holder, exists := journal.Holders[holderID]
+		if exists && contains(holder.Permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Journals which the user cannot read are reported as missing, just like Spire does.
+func (fake *Fake) requirePermission(journalID string, user brood.AuthUser, permission string) (*journalRecord, error) {
+	journal, exists := fake.state.Journals[journalID]
+	if !exists || !fake.hasPermission(journal, user, "journals.read") && !fake.hasPermission(journal, user, permission) {
+		return nil, apiError(http.StatusNotFound, "Journal not found")
+	}
+	if !fake.hasPermission(journal, user, permission) {
+		return nil, apiError(http.StatusForbidden, "You do not have permission to perform this action")
+	}
+	return journal, nil
+}
+
+func (fake *Fake) requireEntry(journal *journalRecord, entryID string) (*spire.Entry, error) {
+	entry, exists := journal.Entries[entryID]
+	if !exists {
+		return nil, apiError(http.StatusNotFound, "Entry not found")
+	}
+	return entry, nil
+}
+
+// Keeps the holder_ids field of the journal in sync with its permissions.
+func (fake *Fake) refreshHolderIDs(journal *journalRecord) {
+	ids := []string{}
+	for holderID := range journal.Holders {
+		ids = append(ids, holderID)
+	}
+	sort.Strings(ids)
+	journal.Journal.HolderIDs = ids
+}
+
+func (fake *Fake) createJournal(ownerID, name string) spire.Journal {
+	now := fake.timestamp()
+	journal := &journalRecord{
+		Journal: spire.Journal{
+			Id:           fake.newID(),
+			BugoutUserID: ownerID,
+			Name:         name,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+		Holders: map[string]*holderRecord{
+			ownerID: {HolderType: "user", Permissions: spire.ValidJournalPermissions()},
+		},
+		Entries: map[string]*spire.Entry{},
+	}
+	fake.refreshHolderIDs(journal)
+	fake.state.Journals[journal.Journal.Id] = journal
+	return journal.Journal
+}
+
+func (fake *Fake) insertEntry(journal *journalRecord, entry spire.Entry) spire.Entry {
+	if entry.Id == "" {
+		entry.Id = fake.newID()
+	}
+	now := fake.timestamp()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = now
+	}
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = entry.CreatedAt
+	}
+	journalURL := fmt.Sprintf("%s/journals/%s", strings.TrimRight(fake.SpireURL, "/"), journal.Journal.Id)
+	entry.JournalURL = journalURL
+	entry.Url = fmt.Sprintf("%s/entries/%s", journalURL, entry.Id)
+	entry.Tags = appendUnique([]string{}, entry.Tags...)
+	entry.Score = 0
+
+	stored := entry
+	journal.Entries[entry.Id] = &stored
+	return entry
+}
+
+func copyEntry(entry *spire.Entry) spire.Entry {
+	entryCopy := *entry
+	entryCopy.Tags = append([]string{}, entry.Tags...)
+	return entryCopy
+}
+
+func validatePermissions(memberType string, permissions []string) error {
+	if !spire.IsValidMemberType(memberType) {
+		return apiError(http.StatusBadRequest, fmt.Sprintf("Invalid holder type: %s", memberType))
+	}
+	for _, permission := range permissions {
+		if !spire.IsValidJournalPermission(permission) {
+			return apiError(http.StatusBadRequest, fmt.Sprintf("Invalid permission: %s", permission))
+		}
+	}
+	return nil
+}
+
+// Seeding and inspection
+
+// SeedUser registers an access token for a user who belongs to the given groups. It has no effect
+// if the Fake has an Authenticator.
+func (fake *Fake) SeedUser(token, userID string, groupIDs ...string) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	user := brood.AuthUser{UserId: userID, Username: userID, Groups: []brood.AuthUserGroup{}}
+	for _, groupID := range groupIDs {
+		user.Groups = append(user.Groups, brood.AuthUserGroup{GroupId: groupID, UserId: userID, UserType: string(brood.GroupRoleMember)})
+	}
+	fake.state.Users[token] = user
+}
+
+// SeedJournal creates a journal on which the user with the given ID holds every permission.
+func (fake *Fake) SeedJournal(ownerID, name string) spire.Journal {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.createJournal(ownerID, name)
+}
+
+// SeedEntry stores an entry in a journal as is, without checking permissions. Missing IDs and
+// timestamps are filled in, so this is the way to create entries with specific creation times.
+func (fake *Fake) SeedEntry(journalID string, entry spire.Entry) (spire.Entry, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	journal, exists := fake.state.Journals[journalID]
+	if !exists {
+		return spire.Entry{}, apiError(http.StatusNotFound, "Journal not found")
+	}
+	return fake.insertEntry(journal, entry), nil
+}
+
+// SaveState writes the complete state of the fake to w as JSON.
+func (fake *Fake) SaveState(w io.Writer) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(fake.state)
+}
+
+// LoadState replaces the state of the fake with state that was written by SaveState.
+func (fake *Fake) LoadState(r io.Reader) error {
+	state := newState()
+	decodeErr := json.NewDecoder(r).Decode(&state)
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.state = state
+	return nil
+}
+
+// Journals returns all journals, sorted by ID.
+func (fake *Fake) Journals() []spire.Journal {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	journals := []spire.Journal{}
+	for _, journal := range fake.state.Journals {
+		journals = append(journals, journal.Journal)
+	}
+	sort.Slice(journals, func(i, j int) bool { return journals[i].Id < journals[j].Id })
+	return journals
+}
+
+// Entries returns all entries in a journal, oldest first.
+func (fake *Fake) Entries(journalID string) []spire.Entry {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	entries := []spire.Entry{}
+	if journal, exists := fake.state.Journals[journalID]; exists {
+		entries = sortedEntries(journal, "asc")
+	}
+	return entries
+}
+
+// Scopes returns all permissions granted on a journal, sorted by holder.
+func (fake *Fake) Scopes(journalID string) spire.JournalPermissionsList {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	scopes := spire.JournalPermissionsList{Scopes: []spire.JournalPermission{}}
+	journal, exists := fake.state.Journals[journalID]
+	if !exists {
+		return scopes
+	}
+	for _, holderID := range journal.Journal.HolderIDs {
+		holder := journal.Holders[holderID]
+		for _, permission := range holder.Permissions {
+			scopes.Scopes = append(scopes.Scopes, spire.JournalPermission{
+				JournalID:  journalID,
+				HolderID:   holderID,
+				HolderType: holder.HolderType,
+				Permission: permission,
+			})
+		}
+	}
+	return scopes
+}
+
+// API
+
+func (fake *Fake) Ping() (string, error) {
+	return `{"status":"ok"}`, nil
+}
+
+func (fake *Fake) CreateJournal(token, name string) (spire.Journal, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Journal{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.createJournal(user.UserId, name), nil
+}
+
+func (fake *Fake) GetJournal(token, journalID string) (spire.Journal, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Journal{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.read")
+	if err != nil {
+		return spire.Journal{}, err
+	}
+	return journal.Journal, nil
+}
+
+func (fake *Fake) ListJournals(token string) (spire.JournalsList, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.JournalsList{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journals := spire.JournalsList{Journals: []spire.Journal{}}
+	for _, journal := range fake.state.Journals {
+		if fake.hasPermission(journal, user, "journals.read") {
+			journals.Journals = append(journals.Journals, journal.Journal)
+		}
+	}
+	sort.Slice(journals.Journals, func(i, j int) bool { return journals.Journals[i].Id < journals.Journals[j].Id })
+	return journals, nil
+}
+
+func (fake *Fake) UpdateJournal(token, journalID, name string) (spire.Journal, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Journal{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.update")
+	if err != nil {
+		return spire.Journal{}, err
+	}
+	journal.Journal.Name = name
+	journal.Journal.UpdatedAt = fake.timestamp()
+	return journal.Journal, nil
+}
+
+func (fake *Fake) DeleteJournal(token, journalID string) (spire.Journal, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Journal{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.delete")
+	if err != nil {
+		return spire.Journal{}, err
+	}
+	delete(fake.state.Journals, journalID)
+	return journal.Journal, nil
+}
+
+func (fake *Fake) AddJournalMember(token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.JournalPermissionsList{}, authErr
+	}
+	if err := validatePermissions(memberType, permissions); err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.update")
+	if err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+
+	holder, exists := journal.Holders[memberID]
+	if !exists {
+		holder = &holderRecord{HolderType: memberType, Permissions: []string{}}
+		journal.Holders[memberID] = holder
+	}
+	holder.Permissions = appendUnique(holder.Permissions, permissions...)
+	fake.refreshHolderIDs(journal)
+
+	scopes := spire.JournalPermissionsList{Scopes: []spire.JournalPermission{}}
+	for _, permission := range appendUnique([]string{}, permissions...) {
+		scopes.Scopes = append(scopes.Scopes, spire.JournalPermission{JournalID: journalID, HolderID: memberID, HolderType: memberType, Permission: permission})
+	}
+	return scopes, nil
+}
+
+func (fake *Fake) RemoveJournalMember(token, journalID, memberID, memberType string, permissions []string) (spire.JournalPermissionsList, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.JournalPermissionsList{}, authErr
+	}
+	if err := validatePermissions(memberType, permissions); err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.update")
+	if err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+	holder, exists := journal.Holders[memberID]
+	if !exists || holder.HolderType != memberType {
+		return spire.JournalPermissionsList{}, apiError(http.StatusNotFound, "Holder not found")
+	}
+
+	scopes := spire.JournalPermissionsList{Scopes: []spire.JournalPermission{}}
+	remaining := []string{}
+	for _, permission := range holder.Permissions {
+		if contains(permissions, permission) {
+			scopes.Scopes = append(scopes.Scopes, spire.JournalPermission{JournalID: journalID, HolderID: memberID, HolderType: memberType, Permission: permission})
+		} else {
+			remaining = append(remaining, permission)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(journal.Holders, memberID)
+	} else {
+		holder.Permissions = remaining
+	}
+	fake.refreshHolderIDs(journal)
+	return scopes, nil
+}
+
+func (fake *Fake) ListJournalMembers(token, journalID string) (spire.JournalPermissionsList, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.JournalPermissionsList{}, authErr
+	}
+
+	fake.mu.Lock()
+	_, err := fake.requirePermission(journalID, user, "journals.read")
+	fake.mu.Unlock()
+	if err != nil {
+		return spire.JournalPermissionsList{}, err
+	}
+	return fake.Scopes(journalID), nil
+}
+
+// CheckJournalPermission reports journals on which the user holds no permissions at all as missing.
+func (fake *Fake) CheckJournalPermission(token, journalID, permission string) (bool, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return false, authErr
+	}
+	if !spire.IsValidJournalPermission(permission) {
+		return false, apiError(http.StatusBadRequest, fmt.Sprintf("Invalid permission: %s", permission))
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, exists := fake.state.Journals[journalID]
+	if !exists {
+		return false, apiError(http.StatusNotFound, "Journal not found")
+	}
+	for _, heldPermission := range spire.ValidJournalPermissions() {
+		if fake.hasPermission(journal, user, heldPermission) {
+			return fake.hasPermission(journal, user, permission), nil
+		}
+	}
+	return false, apiError(http.StatusNotFound, "Journal not found")
+}
+
+func (fake *Fake) CreateEntry(token, journalID, title, content string, tags []string, entryContext spire.EntryContext) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.create")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry := spire.Entry{
+		Title:       title,
+		Content:     content,
+		Tags:        tags,
+		ContextType: entryContext.ContextType,
+		ContextUrl:  entryContext.ContextURL,
+		ContextID:   entryContext.ContextID,
+	}
+	return fake.insertEntry(journal, entry), nil
+}
+
+func (fake *Fake) DeleteEntry(token, journalID, entryID string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.delete")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	delete(journal.Entries, entryID)
+	return copyEntry(entry), nil
+}
+
+func (fake *Fake) GetEntry(token, journalID, entryID string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.read")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	return copyEntry(entry), nil
+}
+
+func (fake *Fake) ListEntries(token, journalID string, limit, offset int) (spire.EntryResultsPage, error) {
+	return fake.SearchEntries(token, journalID, "", limit, offset, nil)
+}
+
+func (fake *Fake) SearchEntries(token, journalID, searchQuery string, limit, offset int, queryParameters map[string]string) (spire.EntryResultsPage, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.EntryResultsPage{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.read")
+	if err != nil {
+		return spire.EntryResultsPage{}, err
+	}
+
+	query, parseErr := spire.ParseSearchQuery(searchQuery, queryParameters)
+	if parseErr != nil {
+		return spire.EntryResultsPage{}, apiError(http.StatusBadRequest, parseErr.Error())
+	}
+	order := query.Order
+	if order != spire.SearchOrderAscending {
+		order = spire.SearchOrderDescending
+	}
+	results := []spire.Entry{}
+	for _, entry := range sortedEntries(journal, order) {
+		if matches(query, entry) {
+			if query.ExcludeContent {
+				entry.Content = ""
+			}
+			// Spire leaves the IDs out of search results. They are part of the entry URLs.
+			entry.Id = ""
+			entry.Score = 1
+			results = append(results, entry)
+		}
+	}
+
+	return paginate(results, limit, offset), nil
+}
+
+func (fake *Fake) TagEntry(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry.Tags = appendUnique(entry.Tags, tags...)
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
+
+func (fake *Fake) UntagEntry(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	remaining := []string{}
+	for _, tag := range entry.Tags {
+		if !contains(tags, tag) {
+			remaining = append(remaining, tag)
+		}
+	}
+	entry.Tags = remaining
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
+
+func (fake *Fake) SetEntryTags(token, journalID, entryID string, tags []string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	toAdd, toRemove := spire.DiffTags(entry.Tags, tags)
+	if len(toAdd) == 0 && len(toRemove) == 0 {
+		return copyEntry(entry), nil
+	}
+	entry.Tags = appendUnique([]string{}, tags...)
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
+
+// UpdateEntry keeps the current title or content of the entry if the new one is empty, just like
+// spire.SpireClient does.
+func (fake *Fake) UpdateEntry(token, journalID, entryID, title, content string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	if title != "" {
+		entry.Title = title
+	}
+	if content != "" {
+		entry.Content = content
+	}
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
+
+// ReplaceEntry sets the title and content of the entry, even if they are empty.
+func (fake *Fake) ReplaceEntry(token, journalID, entryID, title, content string) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry.Title = title
+	entry.Content = content
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
+
+func (fake *Fake) UpdateEntryIfUnchanged(token, journalID, entryID, title, content string, version spire.EntryVersion) (spire.Entry, error) {
+	user, authErr := fake.authenticate(token)
+	if authErr != nil {
+		return spire.Entry{}, authErr
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	journal, err := fake.requirePermission(journalID, user, "journals.entries.update")
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	entry, err := fake.requireEntry(journal, entryID)
+	if err != nil {
+		return spire.Entry{}, err
+	}
+	if !version.Matches(*entry) {
+		return spire.Entry{}, &spire.EntryConflictError{EntryID: entryID, Expected: version, Current: copyEntry(entry)}
+	}
+	entry.Title = title
+	entry.Content = content
+	entry.UpdatedAt = fake.timestamp()
+	return copyEntry(entry), nil
+}
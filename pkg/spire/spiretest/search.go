@@ -0,0 +1,110 @@
+package spiretest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+)
+
+// Checks an entry against a search query. Title and content terms are matched as case-insensitive
+// substrings, which is good enough for tests but simpler than Spire's full text search.
+func matches(query spire.SearchQuery, entry spire.Entry) bool {
+	for _, tag := range query.RequiredTags {
+		if !contains(entry.Tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range query.ExcludedTags {
+		if contains(entry.Tags, tag) {
+			return false
+		}
+	}
+	if query.ContextType != "" && entry.ContextType != query.ContextType {
+		return false
+	}
+	if query.ContextID != "" && entry.ContextID != query.ContextID {
+		return false
+	}
+
+	if !inRange(entry.CreatedAt, query.CreatedAfter, query.CreatedBefore) || !inRange(entry.UpdatedAt, query.UpdatedAfter, query.UpdatedBefore) {
+		return false
+	}
+
+	title := strings.ToLower(entry.Title)
+	content := strings.ToLower(entry.Content)
+	for _, term := range query.TitleTerms {
+		if !strings.Contains(title, strings.ToLower(term)) {
+			return false
+		}
+	}
+	for _, term := range query.ContentTerms {
+		if !strings.Contains(content, strings.ToLower(term)) {
+			return false
+		}
+	}
+	for _, term := range query.Terms {
+		term = strings.ToLower(term)
+		if !strings.Contains(title, term) && !strings.Contains(content, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks whether a timestamp lies strictly between the given bounds. Zero bounds are not applied.
+func inRange(timestamp, after, before time.Time) bool {
+	if !after.IsZero() && !timestamp.After(after) {
+		return false
+	}
+	if !before.IsZero() && !timestamp.Before(before) {
+		return false
+	}
+	return true
+}
+
+// Returns copies of the entries in a journal sorted by creation time, in "asc" or "desc" order.
+func sortedEntries(journal *journalRecord, order string) []spire.Entry {
+	entries := make([]spire.Entry, 0, len(journal.Entries))
+	for _, entry := range journal.Entries {
+		entries = append(entries, copyEntry(entry))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].Id < entries[j].Id
+	})
+	if order == "desc" {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	return entries
+}
+
+// Spire only sets next_offset if there are more results after the current page.
+func paginate(matches []spire.Entry, limit, offset int) spire.EntryResultsPage {
+	page := spire.EntryResultsPage{
+		TotalResults: len(matches),
+		Offset:       offset,
+		Results:      []spire.Entry{},
+	}
+	if len(matches) > 0 {
+		page.MaxScore = 1
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(matches) {
+		return page
+	}
+	end := len(matches)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+		page.NextOffset = end
+	}
+	page.Results = matches[offset:end]
+	return page
+}
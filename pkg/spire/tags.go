@@ -0,0 +1,188 @@
+package spire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TagError is the error from a request to add or remove a single tag.
+type TagError struct {
+	Tag string
+	Err error
+}
+
+func (e TagError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Tag, e.Err.Error())
+}
+
+func (e TagError) Unwrap() error {
+	return e.Err
+}
+
+// TagErrors is returned by UntagEntry and SetEntryTags when some of the tags could not be added or
+// removed. The other tags are still added or removed.
+type TagErrors []TagError
+
+func (e TagErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, tagErr := range e {
+		messages[i] = tagErr.Error()
+	}
+	return fmt.Sprintf("Could not update %d tags:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// Is reports whether any of the individual errors matches target, so that errors.Is can be used on
+// TagErrors.
+func (e TagErrors) Is(target error) bool {
+	for _, tagErr := range e {
+		if errors.Is(tagErr, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first individual error which matches target, so that errors.As can be used on
+// TagErrors.
+func (e TagErrors) As(target interface{}) bool {
+	for _, tagErr := range e {
+		if errors.As(tagErr, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffTags compares the current tags of an entry with the tags it should have, and returns the tags
+// which need to be added and removed. Duplicates are ignored and the order of the tags is kept.
+func DiffTags(current, desired []string) (toAdd []string, toRemove []string) {
+	currentTags := make(map[string]bool)
+	for _, tag := range current {
+		currentTags[tag] = true
+	}
+	desiredTags := make(map[string]bool)
+	for _, tag := range desired {
+		desiredTags[tag] = true
+	}
+
+	toAdd = []string{}
+	for _, tag := range desired {
+		if !currentTags[tag] {
+			toAdd = append(toAdd, tag)
+			currentTags[tag] = true
+		}
+	}
+	toRemove = []string{}
+	for _, tag := range current {
+		if !desiredTags[tag] {
+			toRemove = append(toRemove, tag)
+			desiredTags[tag] = true
+		}
+	}
+	return toAdd, toRemove
+}
+
+// TagUpdateResult reports the outcome of changing the tags of a single entry with
+// UpdateTagsByQuery.
+type TagUpdateResult struct {
+	EntryID string
+	// False if the entry already had the right tags, in which case no request was made for it
+	Changed bool
+	// The entry after the update. For unchanged entries, this is the entry as returned by the
+	// search, without its content.
+	Entry Entry
+	Err   error
+}
+
+// UpdateTagsByQuery adds and removes tags on every entry in a journal which matches the given
+// query. Requests are spread out according to options, and a failure for one entry does not stop
+// the others from being updated. The returned error is only set if the matching entries could not
+// be found.
+//
+// All the matching entries are found before any of them are changed, so that the changes do not
+// affect which entries match (for example, when removing a tag from every entry with that tag).
+func UpdateTagsByQuery(ctx context.Context, client SpireCallerContext, token, journalID string, query SearchQuery, addTags, removeTags []string, options BulkOptions) ([]TagUpdateResult, error) {
+	for _, tag := range addTags {
+		if contains(removeTags, tag) {
+			return nil, fmt.Errorf("Tag cannot be both added and removed: %s", tag)
+		}
+	}
+	if validateErr := query.Validate(); validateErr != nil {
+		return nil, validateErr
+	}
+
+	query.ExcludeContent = true
+	matches := []Entry{}
+	iterator := NewEntryIteratorContext(ctx, client, token, journalID, query.Query(), query.Parameters())
+	for iterator.Next() {
+		matches = append(matches, iterator.Entry())
+	}
+	if iteratorErr := iterator.Err(); iteratorErr != nil {
+		return nil, iteratorErr
+	}
+
+	results := make([]TagUpdateResult, len(matches))
+	limiter := newRateLimiter(options.RateLimit)
+	defer limiter.stop()
+
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range matches {
+			indices <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < options.parallelism(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				entry := matches[index]
+				result := TagUpdateResult{EntryID: entry.Id, Entry: entry}
+
+				desiredTags := []string{}
+				for _, tag := range entry.Tags {
+					if !contains(removeTags, tag) {
+						desiredTags = append(desiredTags, tag)
+					}
+				}
+				desiredTags = append(desiredTags, addTags...)
+				toAdd, toRemove := DiffTags(entry.Tags, desiredTags)
+
+				// Only the tags in the diff are changed, rather than setting all the tags of the
+				// entry, so that tags added by others since the search are kept.
+				if len(toAdd) > 0 || len(toRemove) > 0 {
+					if ctxErr := limiter.wait(ctx); ctxErr != nil {
+						result.Err = ctxErr
+					} else {
+						result.Changed = true
+						if len(toRemove) > 0 {
+							result.Entry, result.Err = client.UntagEntryContext(ctx, token, journalID, entry.Id, toRemove)
+						}
+						if result.Err == nil && len(toAdd) > 0 {
+							result.Entry, result.Err = client.TagEntryContext(ctx, token, journalID, entry.Id, toAdd)
+						}
+					}
+				}
+				results[index] = result
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
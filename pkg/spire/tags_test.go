@@ -0,0 +1,138 @@
+package spire_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/spire"
+	"github.com/bugout-dev/bugout-go/pkg/spire/spiretest"
+)
+
+func TestTagErrorsIsAs(t *testing.T) {
+	notFound := &spire.APIError{StatusCode: http.StatusNotFound, Detail: "Tag not found"}
+	tagErrs := spire.TagErrors{
+		{Tag: "deploy", Err: context.DeadlineExceeded},
+		{Tag: "prod", Err: fmt.Errorf("Could not remove tag: %w", notFound)},
+	}
+	wrapped := fmt.Errorf("Could not update entry: %w", tagErrs)
+
+	for _, err := range []error{tagErrs, wrapped} {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected errors.Is to find the first error in %v", err)
+		}
+		if !errors.Is(err, notFound) {
+			t.Errorf("Expected errors.Is to find the wrapped second error in %v", err)
+		}
+		if !errors.Is(err, spire.ErrNotFound) {
+			t.Errorf("Expected errors.Is to match ErrNotFound through the API error in %v", err)
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, spire.ErrForbidden) {
+			t.Errorf("Expected errors.Is not to match an error which is not in %v", err)
+		}
+
+		var apiErr *spire.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected errors.As to find the API error in %v", err)
+		}
+		if apiErr != notFound {
+			t.Errorf("Expected errors.As to return %v, got %v", notFound, apiErr)
+		}
+
+		var tagErr spire.TagError
+		if !errors.As(err, &tagErr) {
+			t.Fatalf("Expected errors.As to find a TagError in %v", err)
+		}
+		if tagErr.Tag != "deploy" {
+			t.Errorf("Expected errors.As to return the first TagError, got the one for %s", tagErr.Tag)
+		}
+	}
+
+	var apiErr *spire.APIError
+	if errors.As(spire.TagErrors{{Tag: "deploy", Err: context.Canceled}}, &apiErr) {
+		t.Errorf("Expected errors.As not to match when none of the errors is an API error")
+	}
+}
+
+func TestUpdateTagsByQuery(t *testing.T) {
+	fake := spiretest.NewFake()
+	fake.SeedUser("token", "owner")
+	journal := fake.SeedJournal("owner", "tagged")
+	seeded := map[string]spire.Entry{}
+	for _, entry := range []spire.Entry{
+		{Title: "deployed", Tags: []string{"deploy", "prod"}},
+		{Title: "reviewed", Tags: []string{"deploy", "reviewed"}},
+		{Title: "other", Tags: []string{"prod"}},
+	} {
+		created, err := fake.SeedEntry(journal.Id, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seeded[created.Title] = created
+	}
+
+	query := spire.SearchQuery{RequiredTags: []string{"deploy"}}
+	results, err := spire.UpdateTagsByQuery(context.Background(), fake, "token", journal.Id, query, []string{"reviewed"}, []string{"prod"}, spire.BulkOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	changed := map[string]bool{}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Unexpected error for %s: %v", result.Entry.Title, result.Err)
+		}
+		// Search results do not include entry IDs, so they have to come from the entry URLs.
+		if expected := seeded[result.Entry.Title].Id; result.EntryID != expected {
+			t.Errorf("Expected entry ID %q for %s, got %q", expected, result.Entry.Title, result.EntryID)
+		}
+		changed[result.Entry.Title] = result.Changed
+	}
+	expectedChanged := map[string]bool{"deployed": true, "reviewed": false}
+	if fmt.Sprint(changed) != fmt.Sprint(expectedChanged) {
+		t.Errorf("Expected changes %v, got %v", expectedChanged, changed)
+	}
+
+	expectedTags := map[string]string{"deployed": "deploy,reviewed", "reviewed": "deploy,reviewed", "other": "prod"}
+	for _, entry := range fake.Entries(journal.Id) {
+		if tags := strings.Join(entry.Tags, ","); tags != expectedTags[entry.Title] {
+			t.Errorf("Expected tags %s on %s, got %s", expectedTags[entry.Title], entry.Title, tags)
+		}
+	}
+}
+
+func TestTagEntryErrorsWrapAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail": "Entry not found"}`))
+	}))
+	defer server.Close()
+	client := spire.NewClient(server.URL, time.Second)
+
+	calls := map[string]func() error{
+		"TagEntryContext": func() error {
+			_, err := client.TagEntryContext(context.Background(), "token", "journal", "entry", []string{"deploy"})
+			return err
+		},
+		"SetEntryTagsContext": func() error {
+			_, err := client.SetEntryTagsContext(context.Background(), "token", "journal", "entry", []string{"deploy"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if !errors.Is(err, spire.ErrNotFound) {
+			t.Errorf("Expected %s to return an error matching ErrNotFound, got %v", name, err)
+		}
+		var apiErr *spire.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected %s to return an APIError, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes an outgoing request to a Bugout API.
+type RequestInfo struct {
+	// Operation is the name of the client method which made the request (e.g. "CreateEntry").
+	Operation string
+	Method    string
+	URL       string
+}
+
+// ResponseInfo describes the outcome of a request to a Bugout API.
+type ResponseInfo struct {
+	RequestInfo
+	// StatusCode is 0 if no response was received.
+	StatusCode int
+	// Latency covers the entire operation, including any retries.
+	Latency time.Duration
+	Err     error
+}
+
+// Middleware hooks into every request made by a Spire or Brood client. Either hook may be nil.
+//
+// BeforeRequest is called before the request is sent and may modify it (for example, to add
+// headers). If it returns an error, the request is not sent and the error is returned to the
+// caller. AfterResponse is called once the request has completed. It must not consume the
+// response body.
+//
+// BeforeRequest hooks run in the order in which middleware was registered, and AfterResponse
+// hooks run in the reverse order.
+type Middleware struct {
+	BeforeRequest func(request *http.Request, info RequestInfo) error
+	AfterResponse func(response *http.Response, info ResponseInfo)
+}
+
+type operationContextKey struct{}
+
+// ContextWithOperation annotates a context with the name of the client operation it is used for.
+func ContextWithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
+
+// OperationFromContext returns the name of the client operation (e.g. "CreateEntry") that a
+// request context belongs to. This lets custom transports identify requests.
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationContextKey{}).(string)
+	return operation
+}
+
+// DoWithMiddleware sends a request using the send function, running the given middleware around
+// it. The request context is annotated with the operation name.
+func DoWithMiddleware(request *http.Request, operation string, middleware []Middleware, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	request = request.WithContext(ContextWithOperation(request.Context(), operation))
+	info := RequestInfo{
+		Operation: operation,
+		Method:    request.Method,
+		URL:       request.URL.String(),
+	}
+
+	for _, m := range middleware {
+		if m.BeforeRequest == nil {
+			continue
+		}
+		if hookErr := m.BeforeRequest(request, info); hookErr != nil {
+			return nil, hookErr
+		}
+	}
+
+	start := time.Now()
+	response, err := send(request)
+	responseInfo := ResponseInfo{
+		RequestInfo: info,
+		Latency:     time.Since(start),
+		Err:         err,
+	}
+	if response != nil {
+		responseInfo.StatusCode = response.StatusCode
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i].AfterResponse != nil {
+			middleware[i].AfterResponse(response, responseInfo)
+		}
+	}
+
+	return response, err
+}
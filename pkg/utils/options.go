@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClientConfig collects the settings which ClientOptions apply to Spire and Brood clients.
+type ClientConfig struct {
+	HTTPClient *http.Client
+	Timeout    time.Duration
+	Transport  http.RoundTripper
+	UserAgent  string
+	Headers    http.Header
+	Retry      RetryPolicy
+	Middleware []Middleware
+
+	timeoutSet bool
+}
+
+type ClientOption func(*ClientConfig)
+
+func NewClientConfig(opts ...ClientOption) ClientConfig {
+	config := ClientConfig{Headers: http.Header{}}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// BuildHTTPClient returns the HTTP client described by the configuration. If an HTTP client was
+// provided with WithHTTPClient, it is used as is unless a timeout or transport was specified after
+// it, in which case those are applied to a copy of it.
+func (config ClientConfig) BuildHTTPClient() *http.Client {
+	if config.HTTPClient != nil && !config.timeoutSet && config.Transport == nil {
+		return config.HTTPClient
+	}
+
+	httpClient := &http.Client{}
+	if config.HTTPClient != nil {
+		clientCopy := *config.HTTPClient
+		httpClient = &clientCopy
+	}
+	if config.timeoutSet {
+		httpClient.Timeout = config.Timeout
+	}
+	if config.Transport != nil {
+		httpClient.Transport = config.Transport
+	}
+	return httpClient
+}
+
+// WithHTTPClient makes the client send its requests using the given HTTP client. It discards the
+// timeout and transport set by any earlier options.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(config *ClientConfig) {
+		config.HTTPClient = httpClient
+		config.Timeout = 0
+		config.timeoutSet = false
+		config.Transport = nil
+	}
+}
+
+// WithTransport sets the transport (e.g. with custom proxy or TLS settings) used by the client.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(config *ClientConfig) {
+		config.Transport = transport
+	}
+}
+
+// WithTimeout sets the timeout for each HTTP request made by the client.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *ClientConfig) {
+		config.Timeout = timeout
+		config.timeoutSet = true
+	}
+}
+
+// WithUserAgent sets the User-Agent header on every request made by the client.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(config *ClientConfig) {
+		config.UserAgent = userAgent
+	}
+}
+
+// WithHeader adds a header to every request made by the client. Headers set by the client methods
+// themselves (like Authorization) take precedence.
+func WithHeader(key, value string) ClientOption {
+	return func(config *ClientConfig) {
+		if config.Headers == nil {
+			config.Headers = http.Header{}
+		}
+		config.Headers.Add(key, value)
+	}
+}
+
+// WithRetry sets the retry policy of the client.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(config *ClientConfig) {
+		config.Retry = policy
+	}
+}
+
+// WithMiddleware registers request/response hooks which run around every request made by the
+// client, after any middleware registered by earlier options.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(config *ClientConfig) {
+		config.Middleware = append(config.Middleware, middleware...)
+	}
+}
+
+// ApplyDefaultHeaders adds the configured User-Agent and default headers to a request without
+// overwriting any header which has already been set on it.
+func ApplyDefaultHeaders(request *http.Request, userAgent string, headers http.Header) {
+	for key, values := range headers {
+		if request.Header.Get(key) != "" {
+			continue
+		}
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	if userAgent != "" {
+		request.Header.Set("User-Agent", userAgent)
+	}
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/config"
+)
+
+// RetryPolicy describes how failed requests to Bugout APIs are retried. The zero value disables
+// retries.
+//
+// Only requests whose method appears in RetryableMethods are retried. The default policy does not
+// include POST, so non-idempotent calls (like CreateEntry) are only retried if you add "POST" to
+// RetryableMethods yourself.
+type RetryPolicy struct {
+	// Total number of attempts, including the first one. Values less than 2 disable retries.
+	MaxAttempts int
+	// Delay before the first retry. Each subsequent retry doubles the delay.
+	BaseDelay time.Duration
+	// Upper bound on the delay between attempts. If a Retry-After header asks for a longer wait,
+	// the response is returned to the caller instead of being retried.
+	MaxDelay time.Duration
+	// Fraction (between 0 and 1) of each delay which is randomized.
+	Jitter float64
+	// HTTP status codes which are considered transient.
+	RetryableStatusCodes []int
+	// HTTP methods which may be retried.
+	RetryableMethods []string
+	// If true, the Retry-After header on retryable responses is used as the delay.
+	HonorRetryAfter bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            250 * time.Millisecond,
+		MaxDelay:             10 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryableMethods:     []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"},
+		HonorRetryAfter:      true,
+	}
+}
+
+// RetryPolicyFromEnv builds a retry policy from the BUGOUT_RETRY_MAX_ATTEMPTS environment
+// variable (or the setting of the same name in the active configuration profile). If it is not set,
+// retries are disabled.
+func RetryPolicyFromEnv() (RetryPolicy, error) {
+	env, envErr := config.LoadEnv()
+	if envErr != nil {
+		return RetryPolicy{}, envErr
+	}
+	return RetryPolicyFromConfig(env)
+}
+
+// RetryPolicyFromConfig is like RetryPolicyFromEnv, but takes settings from an already loaded
+// configuration profile.
+func RetryPolicyFromConfig(env config.Env) (RetryPolicy, error) {
+	maxAttemptsRaw := env.Getenv("BUGOUT_RETRY_MAX_ATTEMPTS")
+	if maxAttemptsRaw == "" {
+		return RetryPolicy{}, nil
+	}
+	maxAttempts, conversionErr := strconv.Atoi(maxAttemptsRaw)
+	if conversionErr != nil {
+		return RetryPolicy{}, fmt.Errorf("Could not parse environment variable as integer: BUGOUT_RETRY_MAX_ATTEMPTS=%s", maxAttemptsRaw)
+	}
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	return policy, nil
+}
+
+func (policy RetryPolicy) retriesMethod(method string) bool {
+	for _, retryableMethod := range policy.RetryableMethods {
+		if strings.EqualFold(method, retryableMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+func (policy RetryPolicy) retriesStatusCode(statusCode int) bool {
+	for _, retryableStatusCode := range policy.RetryableStatusCodes {
+		if statusCode == retryableStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay to wait after the given (1-indexed) attempt has failed.
+func (policy RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := float64(policy.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		delay = delay * (1 - jitter*rand.Float64())
+	}
+	return time.Duration(delay)
+}
+
+// Parses a Retry-After header, which may either be a number of seconds or an HTTP date.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// Only transient network errors are retryable: temporary errors, connections which were reset or
+// refused, and responses which were cut off. Errors like failed TLS verification or malformed URLs
+// fail the same way on every attempt.
+func isRetryableError(request *http.Request, err error) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Temporary()
+}
+
+// DoWithRetry sends the request using the given HTTP client, retrying it on transient network
+// errors and retryable status codes according to the policy. Request bodies are replayed using
+// request.GetBody, which http.NewRequest populates for in-memory bodies.
+func DoWithRetry(httpClient *http.Client, request *http.Request, policy RetryPolicy) (*http.Response, error) {
+	if policy.MaxAttempts < 2 || !policy.retriesMethod(request.Method) {
+		return httpClient.Do(request)
+	}
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return httpClient.Do(request)
+	}
+
+	ctx := request.Context()
+	for attempt := 1; ; attempt++ {
+		attemptRequest := request.Clone(ctx)
+		if request.GetBody != nil {
+			body, bodyErr := request.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			attemptRequest.Body = body
+		}
+
+		response, err := httpClient.Do(attemptRequest)
+		if attempt >= policy.MaxAttempts {
+			return response, err
+		}
+
+		delay := policy.Backoff(attempt)
+		if err != nil {
+			if !isRetryableError(attemptRequest, err) {
+				return response, err
+			}
+		} else {
+			if !policy.retriesStatusCode(response.StatusCode) {
+				return response, err
+			}
+			if requestedDelay, ok := retryAfter(response); ok && policy.HonorRetryAfter {
+				if policy.MaxDelay > 0 && requestedDelay > policy.MaxDelay {
+					return response, err
+				}
+				delay = requestedDelay
+			}
+			// Drain the body so that the underlying connection can be reused.
+			io.Copy(ioutil.Discard, io.LimitReader(response.Body, MaxErrorBodyBytes))
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package utils_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bugout-dev/bugout-go/pkg/utils"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+type temporaryError struct {
+	temporary bool
+}
+
+func (e temporaryError) Error() string   { return "network error" }
+func (e temporaryError) Timeout() bool   { return false }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+func TestDoWithRetryErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, 3},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 3},
+		{"unexpected EOF", io.ErrUnexpectedEOF, 3},
+		{"temporary network error", temporaryError{temporary: true}, 3},
+		{"permanent network error", temporaryError{temporary: false}, 1},
+		{"certificate error", x509.UnknownAuthorityError{}, 1},
+		{"other error", errors.New("unsupported protocol scheme"), 1},
+	}
+
+	policy := utils.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.Jitter = 0
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			attempts := 0
+			httpClient := &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				attempts++
+				return nil, c.err
+			})}
+			request, _ := http.NewRequest("GET", "http://bugout.test/ping", nil)
+
+			_, err := utils.DoWithRetry(httpClient, request, policy)
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if attempts != c.attempts {
+				t.Errorf("Expected %d attempts, got %d", c.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestDoWithRetryUnsupportedScheme(t *testing.T) {
+	policy := utils.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	attempts := 0
+	httpClient := &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultTransport.RoundTrip(request)
+	})}
+	request, _ := http.NewRequest("GET", "ftp://bugout.test/ping", nil)
+
+	if _, err := utils.DoWithRetry(httpClient, request, policy); err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
@@ -1,13 +1,105 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
 )
 
+// Sentinel errors which an *APIError matches (using errors.Is) based on its status code.
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// Maximum number of bytes of an error response body that is retained on an APIError.
+const MaxErrorBodyBytes int64 = 4096
+
+// APIError describes a non-2xx response from a Bugout API.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// Detail is the human readable error message returned by the API (the "detail" key in the
+	// response body), if one was present.
+	Detail string
+	// Body contains (at most MaxErrorBodyBytes of) the raw response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("Invalid status code in HTTP response: %d", e.StatusCode)
+	if e.Method != "" && e.URL != "" {
+		message = fmt.Sprintf("%s (%s %s)", message, e.Method, e.URL)
+	}
+	if e.Detail != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Detail)
+	}
+	return message
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Extracts the "detail" message from a Bugout API error response. Validation errors return a
+// structured detail, in which case the raw JSON is returned.
+func parseErrorDetail(body []byte) string {
+	var errorResponse struct {
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err != nil || len(errorResponse.Detail) == 0 {
+		return ""
+	}
+
+	var detail string
+	if err := json.Unmarshal(errorResponse.Detail, &detail); err == nil {
+		return detail
+	}
+	return string(errorResponse.Detail)
+}
+
+// HTTPStatusCheck returns an *APIError if the given response does not have a 2xx status code.
+// In that case, it consumes (part of) the response body.
 func HTTPStatusCheck(response *http.Response) error {
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return fmt.Errorf("Invalid status code in HTTP response: %d", response.StatusCode)
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
 	}
-	return nil
+
+	apiErr := &APIError{StatusCode: response.StatusCode}
+	if response.Request != nil {
+		apiErr.Method = response.Request.Method
+		if response.Request.URL != nil {
+			apiErr.URL = response.Request.URL.String()
+		}
+	}
+
+	if response.Body != nil {
+		body, readErr := ioutil.ReadAll(io.LimitReader(response.Body, MaxErrorBodyBytes))
+		if readErr == nil {
+			apiErr.Body = strings.TrimSpace(string(body))
+			apiErr.Detail = parseErrorDetail(body)
+		}
+	}
+
+	return apiErr
 }
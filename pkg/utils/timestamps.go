@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// Layouts of the timestamps returned by the Bugout APIs. Depending on the endpoint and the version
+// of the API, timestamps may use a space instead of a "T" and may leave out the time zone, in which
+// case they are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// ParseTimestamp parses a timestamp returned by a Bugout API and converts it to UTC. An empty
+// timestamp is parsed as the zero time.
+func ParseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, parseErr := time.Parse(layout, value); parseErr == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Invalid timestamp: %s", value)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the Bugout access token used by scoped clients. It is consulted before
+// every request, so implementations may rotate tokens over time.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+type staticTokenSource struct {
+	token string
+}
+
+func (source staticTokenSource) Token() (string, error) {
+	if source.token == "" {
+		return "", errors.New("No access token provided")
+	}
+	return source.token, nil
+}
+
+// NewStaticTokenSource returns a TokenSource which always supplies the given token.
+func NewStaticTokenSource(token string) TokenSource {
+	return staticTokenSource{token: token}
+}
+
+type envTokenSource struct {
+	envName string
+}
+
+func (source envTokenSource) Token() (string, error) {
+	token := os.Getenv(source.envName)
+	if token == "" {
+		return "", fmt.Errorf("Please set the %s environment variable", source.envName)
+	}
+	return token, nil
+}
+
+// NewEnvTokenSource returns a TokenSource which reads the token from the given environment
+// variable (e.g. BUGOUT_ACCESS_TOKEN) on every call.
+func NewEnvTokenSource(envName string) TokenSource {
+	return envTokenSource{envName: envName}
+}
+
+type fileTokenSource struct {
+	path string
+}
+
+func (source fileTokenSource) Token() (string, error) {
+	contents, readErr := ioutil.ReadFile(source.path)
+	if readErr != nil {
+		return "", readErr
+	}
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return "", fmt.Errorf("No access token found in file: %s", source.path)
+	}
+	return token, nil
+}
+
+// NewFileTokenSource returns a TokenSource which reads the token from the given file on every
+// call. Leading and trailing whitespace is ignored.
+func NewFileTokenSource(path string) TokenSource {
+	return fileTokenSource{path: path}
+}
+
+// RefreshingTokenSource caches the token returned by its Refresh function and calls it again
+// once the token is older than TTL (or after Invalidate is called). It is safe for concurrent
+// use.
+type RefreshingTokenSource struct {
+	Refresh func() (string, error)
+	TTL     time.Duration
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewRefreshingTokenSource(refresh func() (string, error), ttl time.Duration) *RefreshingTokenSource {
+	return &RefreshingTokenSource{Refresh: refresh, TTL: ttl}
+}
+
+func (source *RefreshingTokenSource) Token() (string, error) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	if source.token != "" && (source.TTL <= 0 || time.Now().Before(source.expiresAt)) {
+		return source.token, nil
+	}
+
+	token, refreshErr := source.Refresh()
+	if refreshErr != nil {
+		return "", refreshErr
+	}
+	source.token = token
+	source.expiresAt = time.Now().Add(source.TTL)
+	return token, nil
+}
+
+// Invalidate discards the cached token, so that the next call to Token refreshes it.
+func (source *RefreshingTokenSource) Invalidate() {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.token = ""
+}
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported matches the errors returned by wrappers (such as ScopedClient) when the caller
+// they wrap does not implement an optional interface.
+var ErrUnsupported = errors.New("operation not supported")
+
+// UnsupportedError returns an error, matching ErrUnsupported, for a caller which does not implement
+// the given operation.
+func UnsupportedError(caller interface{}, operation string) error {
+	return fmt.Errorf("%T does not implement %s: %w", caller, operation, ErrUnsupported)
+}